@@ -0,0 +1,221 @@
+package alarm
+
+import (
+	"sync"
+)
+
+// Handler 报警事件处理函数
+type Handler func(event Event)
+
+// DeliveryPolicy 消费者处理不及时（缓冲区已满）时的投递策略
+type DeliveryPolicy int
+
+const (
+	// DropNewest 丢弃新到达的事件（默认），不阻塞SDK回调线程
+	DropNewest DeliveryPolicy = iota
+	// DropOldest 丢弃缓冲区中最旧的事件，保留最新事件
+	DropOldest
+	// Block 阻塞SDK回调线程直到有空间
+	// 所有设备共用同一个SDK回调线程，慢速消费者会拖慢其他设备的报警投递
+	Block
+)
+
+// DefaultBufferSize 默认事件缓冲区大小
+const DefaultBufferSize = 64
+
+// DeliveryOptions 事件投递参数
+type DeliveryOptions struct {
+	BufferSize int            // 缓冲区大小，0表示使用默认值
+	Policy     DeliveryPolicy // 缓冲区已满时的投递策略
+}
+
+// subscription 一个事件订阅
+type subscription struct {
+	ch     chan Event
+	policy DeliveryPolicy
+	done   chan struct{}  // 订阅已取消
+	wg     sync.WaitGroup // 正在进行的投递
+}
+
+// newSubscription 创建事件订阅
+func newSubscription(opts *DeliveryOptions) *subscription {
+	var o DeliveryOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.BufferSize <= 0 {
+		o.BufferSize = DefaultBufferSize
+	}
+	return &subscription{
+		ch:     make(chan Event, o.BufferSize),
+		policy: o.Policy,
+		done:   make(chan struct{}),
+	}
+}
+
+// send 按投递策略发送事件
+func (s *subscription) send(event Event) {
+	defer s.wg.Done()
+
+	select {
+	case s.ch <- event:
+		return
+	default:
+	}
+
+	switch s.policy {
+	case Block:
+		select {
+		case s.ch <- event:
+		case <-s.done:
+		}
+	case DropOldest:
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- event:
+		default:
+		}
+	}
+}
+
+// close 取消订阅并关闭通道
+// 先通知阻塞中的投递退出，等待其结束后再关闭通道
+func (s *subscription) close() {
+	close(s.done)
+	s.wg.Wait()
+	close(s.ch)
+}
+
+// registry 登录ID -> 报警监听器
+// SDK报警回调是进程全局的，根据报警设备信息中的登录ID找到对应的监听器
+var registry = struct {
+	sync.RWMutex
+	listeners map[int]*AlarmListener
+}{listeners: make(map[int]*AlarmListener)}
+
+// register 登记监听器
+func register(loginID int, a *AlarmListener) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.listeners[loginID] = a
+}
+
+// unregister 注销监听器（仅当登记的仍是该监听器时）
+func unregister(loginID int, a *AlarmListener) {
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.listeners[loginID] == a {
+		delete(registry.listeners, loginID)
+	}
+}
+
+// lookup 根据登录ID查找监听器
+func lookup(loginID int) *AlarmListener {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.listeners[loginID]
+}
+
+// hub 报警事件订阅集合
+// 报警监听器和报警监听服务共用，负责将事件按投递策略分发给所有订阅
+type hub struct {
+	mu   sync.Mutex
+	subs []*subscription
+}
+
+// subscribe 创建一个新的订阅
+func (h *hub) subscribe(opts *DeliveryOptions) *subscription {
+	s := newSubscription(opts)
+
+	h.mu.Lock()
+	h.subs = append(h.subs, s)
+	h.mu.Unlock()
+
+	return s
+}
+
+// handle 创建一个新的订阅，在独立协程中按顺序调用处理函数
+func (h *hub) handle(handler Handler, opts *DeliveryOptions) {
+	s := h.subscribe(opts)
+	go func() {
+		for event := range s.ch {
+			handler(event)
+		}
+	}()
+}
+
+// deliver 将事件投递给所有订阅
+// 返回false表示当前没有订阅
+func (h *hub) deliver(event Event) bool {
+	h.mu.Lock()
+	subs := h.subs
+	for _, s := range subs {
+		s.wg.Add(1)
+	}
+	h.mu.Unlock()
+
+	for _, s := range subs {
+		s.send(event)
+	}
+	return len(subs) > 0
+}
+
+// closeAll 关闭所有订阅
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	subs := h.subs
+	h.subs = nil
+	h.mu.Unlock()
+
+	for _, s := range subs {
+		s.close()
+	}
+}
+
+// Events 订阅报警事件，返回带缓冲的事件通道
+// 每次调用创建一个新的订阅，所有订阅都会收到该监听器的全部事件；
+// 通道在 Stop 后关闭，再次 Start 后需要重新订阅
+// 参数：
+//   - opts: 投递参数，nil表示使用默认值（缓冲区64，满时丢弃新事件）
+//
+// 返回值：
+//   - <-chan Event: 报警事件通道
+func (a *AlarmListener) Events(opts *DeliveryOptions) <-chan Event {
+	s := a.events.subscribe(opts)
+	capture := a.snapshotFunc()
+	if capture == nil {
+		return s.ch
+	}
+
+	// 联动抓图在独立协程中进行，抓图期间到达的事件按投递策略在订阅缓冲区中积压；
+	// 输出通道与订阅同样带缓冲，Stop 后消费者不再读取时转发协程也能退出
+	out := make(chan Event, cap(s.ch))
+	go func() {
+		defer close(out)
+		for event := range s.ch {
+			AttachSnapshots(&event, capture)
+			select {
+			case out <- event:
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// OnEvent 订阅报警事件，在独立协程中按顺序调用处理函数
+// 处理函数不会阻塞SDK回调线程；处理不及时时按 opts.Policy 处理积压事件
+// 订阅在 Stop 后结束，再次 Start 后需要重新订阅
+// 参数：
+//   - handler: 事件处理函数
+//   - opts: 投递参数，nil表示使用默认值
+func (a *AlarmListener) OnEvent(handler Handler, opts *DeliveryOptions) {
+	if capture := a.snapshotFunc(); capture != nil {
+		handler = WithSnapshots(capture, handler)
+	}
+	a.events.handle(handler, opts)
+}
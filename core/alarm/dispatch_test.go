@@ -0,0 +1,198 @@
+package alarm
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// newTestListener 登录模拟设备并创建报警监听器
+func newTestListener(t *testing.T, fake *sdk.Fake) (*AlarmListener, int) {
+	t.Helper()
+	userID := fake.LoginDevice()
+	l := NewAlarmListenerWithBackend(fake, userID)
+	t.Cleanup(func() { l.Stop() })
+	return l, userID
+}
+
+// motionAlarm 构造指定通道的V30移动侦测报警
+func motionAlarm(channel int) []byte {
+	info := make([]byte, 268)
+	binary.LittleEndian.PutUint32(info[0:], uint32(AlarmTypeMotion))
+	info[alarmV30ChannelOffset+channel-1] = 1
+	return info
+}
+
+// TestListenerRouting 报警事件按登录ID投递给对应的监听器
+func TestListenerRouting(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	l1, id1 := newTestListener(t, fake)
+	l2, id2 := newTestListener(t, fake)
+	ch1 := l1.Events(nil)
+	ch2 := l2.Events(nil)
+	if err := l1.Start(); err != nil {
+		t.Fatalf("启动监听器1失败: %v", err)
+	}
+	if err := l2.Start(); err != nil {
+		t.Fatalf("启动监听器2失败: %v", err)
+	}
+
+	fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: id1}, motionAlarm(1))
+	fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: id2}, motionAlarm(2))
+
+	for i, ch := range []<-chan Event{ch1, ch2} {
+		select {
+		case event := <-ch:
+			if event.Alarm == nil || len(event.Alarm.Channels) != 1 || event.Alarm.Channels[0] != i+1 {
+				t.Errorf("监听器%d收到错误的事件: %+v", i+1, event.Alarm)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("监听器%d未收到事件", i+1)
+		}
+		select {
+		case event := <-ch:
+			t.Errorf("监听器%d收到多余事件: %+v", i+1, event)
+		default:
+		}
+	}
+
+	// Stop 后通道关闭，事件不再投递
+	l1.Stop()
+	if _, ok := <-ch1; ok {
+		t.Error("Stop 后事件通道应关闭")
+	}
+	fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: id1}, motionAlarm(1))
+}
+
+// TestListenerDropPolicy 缓冲区满时按策略丢弃事件
+func TestListenerDropPolicy(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	l, id := newTestListener(t, fake)
+	newest := l.Events(&DeliveryOptions{BufferSize: 2, Policy: DropNewest})
+	oldest := l.Events(&DeliveryOptions{BufferSize: 2, Policy: DropOldest})
+	if err := l.Start(); err != nil {
+		t.Fatalf("启动监听器失败: %v", err)
+	}
+
+	for channel := 1; channel <= 3; channel++ {
+		fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: id}, motionAlarm(channel))
+	}
+
+	check := func(name string, ch <-chan Event, want []int) {
+		for _, channel := range want {
+			event := <-ch
+			if got := event.Alarm.Channels[0]; got != channel {
+				t.Errorf("%s: 期望通道%d，实际%d", name, channel, got)
+			}
+		}
+	}
+	check("DropNewest", newest, []int{1, 2})
+	check("DropOldest", oldest, []int{2, 3})
+}
+
+// TestListenerBlockPolicy 阻塞投递在 Stop 时退出
+func TestListenerBlockPolicy(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	l, id := newTestListener(t, fake)
+	ch := l.Events(&DeliveryOptions{BufferSize: 1, Policy: Block})
+	if err := l.Start(); err != nil {
+		t.Fatalf("启动监听器失败: %v", err)
+	}
+
+	fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: id}, motionAlarm(1))
+
+	emitted := make(chan struct{})
+	go func() {
+		fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: id}, motionAlarm(2))
+		close(emitted)
+	}()
+
+	select {
+	case <-emitted:
+		t.Fatal("缓冲区已满时投递应阻塞")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := l.Stop(); err != nil {
+		t.Fatalf("停止监听器失败: %v", err)
+	}
+	select {
+	case <-emitted:
+	case <-time.After(time.Second):
+		t.Fatal("Stop 后阻塞的投递应退出")
+	}
+
+	if event := <-ch; event.Alarm.Channels[0] != 1 {
+		t.Errorf("应保留缓冲区中的事件: %+v", event.Alarm)
+	}
+}
+
+// TestListenerOnEventRearm 重新布防后事件按新登录ID路由
+func TestListenerOnEventRearm(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	l, oldID := newTestListener(t, fake)
+	received := make(chan Event, 1)
+	l.OnEvent(func(event Event) { received <- event }, nil)
+	if err := l.Start(); err != nil {
+		t.Fatalf("启动监听器失败: %v", err)
+	}
+
+	newID := fake.LoginDevice()
+	l.login.Set(newID)
+	if err := l.Rearm(); err != nil {
+		t.Fatalf("重新布防失败: %v", err)
+	}
+
+	fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: oldID}, motionAlarm(1))
+	fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: newID}, motionAlarm(2))
+
+	select {
+	case event := <-received:
+		if event.Alarmer.UserID != newID {
+			t.Errorf("应只收到新登录ID的事件，实际: %d", event.Alarmer.UserID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("处理函数未收到事件")
+	}
+}
+
+// TestListenerStopCloseFailure 撤销报警上传通道失败时仍注销路由并关闭订阅
+func TestListenerStopCloseFailure(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	l, userID := newTestListener(t, fake)
+	ch := l.Events(nil)
+	if err := l.Start(); err != nil {
+		t.Fatalf("启动监听器失败: %v", err)
+	}
+
+	fake.FailWith("CloseAlarmChanV30", sdk.NET_DVR_USERNOTEXIST)
+	if err := l.Stop(); err == nil {
+		t.Fatal("撤销失败时应返回错误")
+	}
+	if l.IsRunning() {
+		t.Error("撤销失败后监听器不应处于运行状态")
+	}
+	if lookup(userID) != nil {
+		t.Error("撤销失败后应注销事件路由")
+	}
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("撤销失败后不应收到事件")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("撤销失败后订阅通道应关闭")
+	}
+}
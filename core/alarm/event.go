@@ -0,0 +1,368 @@
+package alarm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+	"github.com/samsaralc/hiksdk/core/utils"
+)
+
+// AlarmType 报警类型（对应报警信息中的 dwAlarmType）
+type AlarmType uint32
+
+// 常用报警类型（来自官方SDK）
+const (
+	AlarmTypeIO               AlarmType = 0  // 信号量报警（IO报警输入）
+	AlarmTypeDiskFull         AlarmType = 1  // 硬盘满
+	AlarmTypeVideoLoss        AlarmType = 2  // 视频信号丢失
+	AlarmTypeMotion           AlarmType = 3  // 移动侦测
+	AlarmTypeDiskUnformatted  AlarmType = 4  // 硬盘未格式化
+	AlarmTypeDiskError        AlarmType = 5  // 读写硬盘出错
+	AlarmTypeTamper           AlarmType = 6  // 遮挡报警
+	AlarmTypeStandardMismatch AlarmType = 7  // 制式不匹配
+	AlarmTypeIllegalAccess    AlarmType = 8  // 非法访问
+	AlarmTypeVideoException   AlarmType = 9  // 视频信号异常
+	AlarmTypeRecordException  AlarmType = 10 // 录像异常
+)
+
+// String 返回报警类型名称
+func (t AlarmType) String() string {
+	switch t {
+	case AlarmTypeIO:
+		return "信号量报警"
+	case AlarmTypeDiskFull:
+		return "硬盘满"
+	case AlarmTypeVideoLoss:
+		return "视频信号丢失"
+	case AlarmTypeMotion:
+		return "移动侦测"
+	case AlarmTypeDiskUnformatted:
+		return "硬盘未格式化"
+	case AlarmTypeDiskError:
+		return "读写硬盘出错"
+	case AlarmTypeTamper:
+		return "遮挡报警"
+	case AlarmTypeStandardMismatch:
+		return "制式不匹配"
+	case AlarmTypeIllegalAccess:
+		return "非法访问"
+	case AlarmTypeVideoException:
+		return "视频信号异常"
+	case AlarmTypeRecordException:
+		return "录像异常"
+	default:
+		return fmt.Sprintf("未知报警类型(%d)", uint32(t))
+	}
+}
+
+// AlarmInfo 报警信息（由 COMM_ALARM_V30 / COMM_ALARM_V40 解码）
+// 通道号、报警输入/输出号、硬盘号均为设备上报的编号（从1开始）
+type AlarmInfo struct {
+	Type           AlarmType // 报警类型
+	Time           time.Time // 设备上报的报警时间（仅V40有效，V30为零值）
+	AlarmInputs    []int     // 报警输入号（信号量报警时有效）
+	AlarmOutputs   []int     // 触发的报警输出号
+	Channels       []int     // 发生报警的通道号（移动侦测、视频丢失、遮挡等）
+	RecordChannels []int     // 触发录像的通道号
+	Disks          []int     // 发生报警的硬盘号
+}
+
+// Rect 目标区域框（归一化坐标，取值0~1）
+type Rect struct {
+	X      float32 // 左上角X坐标
+	Y      float32 // 左上角Y坐标
+	Width  float32 // 宽度
+	Height float32 // 高度
+}
+
+// RuleAlarm 行为分析报警（由 COMM_ALARM_RULE 解码）
+type RuleAlarm struct {
+	RuleID     int       // 规则ID
+	RuleName   string    // 规则名称（已由GBK转换为UTF-8）
+	EventType  int       // 行为事件类型（参考 VCA_RULE_EVENT_TYPE_EX）
+	Time       time.Time // 报警时间（设备OSD时间）
+	TargetID   int       // 目标ID
+	TargetRect Rect      // 目标边界框
+	DeviceIP   string    // 前端设备IP
+	DevicePort int       // 前端设备端口
+	Channel    int       // 前端设备通道号
+	IvmsChan   int       // 后端设备通道号
+	PicDataLen int       // 图片数据长度（0表示没有图片）
+}
+
+// Event 解码后的报警事件
+// 根据 Command 不同，Alarm 和 Rule 至多一个非空；未知类型只保留原始数据
+type Event struct {
+	Command int         // 报警消息类型（COMM_ALARM_*）
+	Alarmer sdk.Alarmer // 报警设备信息
+	Alarm   *AlarmInfo  // COMM_ALARM_V30/COMM_ALARM_V40 报警信息
+	Rule    *RuleAlarm  // COMM_ALARM_RULE 行为分析报警
+	Raw     []byte      // 报警信息原始字节
+
+	Snapshots []Snapshot // 联动抓图（启用 EnableSnapshots 或使用 WithSnapshots 时填充）
+}
+
+// Source 返回报警来源设备标识
+// 优先使用设备序列号，序列号无效时使用设备IP（有连接端口时附带端口）
+func (e Event) Source() string {
+	switch {
+	case e.Alarmer.SerialNumber != "":
+		return e.Alarmer.SerialNumber
+	case e.Alarmer.LinkPort > 0:
+		return net.JoinHostPort(e.Alarmer.DeviceIP, strconv.Itoa(e.Alarmer.LinkPort))
+	default:
+		return e.Alarmer.DeviceIP
+	}
+}
+
+// DecodeEvent 解码报警回调收到的报警信息
+// 参数：
+//   - command: 报警消息类型
+//   - alarmer: 报警设备信息
+//   - info: 报警信息原始字节
+//
+// 返回值：
+//   - *Event: 报警事件，未知类型时仅包含原始数据
+//   - error: 解码错误（数据长度不足等），此时仍返回包含原始数据的事件
+func DecodeEvent(command int, alarmer *sdk.Alarmer, info []byte) (*Event, error) {
+	event := &Event{Command: command, Raw: info}
+	if alarmer != nil {
+		event.Alarmer = *alarmer
+	}
+
+	var err error
+	switch command {
+	case COMM_ALARM_V30:
+		event.Alarm, err = DecodeAlarmInfoV30(info)
+	case COMM_ALARM_V40:
+		event.Alarm, err = DecodeAlarmInfoV40(info)
+	case COMM_ALARM_RULE:
+		event.Rule, err = DecodeRuleAlarm(info)
+	}
+	return event, err
+}
+
+// NET_DVR_ALARMINFO_V30 字段偏移
+const (
+	alarmV30OutputOffset  = 8   // byAlarmOutputNumber[96]
+	alarmV30RelateOffset  = 104 // byAlarmRelateChannel[64]
+	alarmV30ChannelOffset = 168 // byChannel[64]
+	alarmV30DiskOffset    = 232 // byDiskNumber[33]
+	alarmV30Size          = 265 // 有效字段长度（不含结尾对齐）
+)
+
+// DecodeAlarmInfoV30 解码 NET_DVR_ALARMINFO_V30
+// 参数：
+//   - info: 报警信息原始字节
+//
+// 返回值：
+//   - *AlarmInfo: 报警信息
+//   - error: 数据长度不足时返回错误
+func DecodeAlarmInfoV30(info []byte) (*AlarmInfo, error) {
+	if len(info) < alarmV30Size {
+		return nil, fmt.Errorf("V30报警信息长度不足: %d < %d", len(info), alarmV30Size)
+	}
+
+	le := binary.LittleEndian
+	a := &AlarmInfo{Type: AlarmType(le.Uint32(info[0:]))}
+	if a.Type == AlarmTypeIO {
+		a.AlarmInputs = []int{int(le.Uint32(info[4:]))}
+	}
+	a.AlarmOutputs = flagIndexes(info[alarmV30OutputOffset:alarmV30RelateOffset])
+	a.RecordChannels = flagIndexes(info[alarmV30RelateOffset:alarmV30ChannelOffset])
+	a.Channels = flagIndexes(info[alarmV30ChannelOffset:alarmV30DiskOffset])
+	a.Disks = flagIndexes(info[alarmV30DiskOffset:alarmV30Size])
+	return a, nil
+}
+
+// alarmV40Layout NET_DVR_ALARMINFO_V40 字段偏移
+// 联合体中包含指针，64位下按8字节对齐，因此偏移与指针长度相关
+type alarmV40Layout struct {
+	union    int // uStruAlarm
+	timeDiff int // byTimeDiffFlag
+	size     int // sizeof(NET_DVR_ALARMINFO_V40)
+}
+
+// newAlarmV40Layout 按指针长度计算V40报警信息布局
+func newAlarmV40Layout(ptrSize int) alarmV40Layout {
+	union := alignUp(12, ptrSize)
+	pRes := union + alignUp(116, ptrSize)
+	timeDiff := pRes + ptrSize
+	header := alignUp(timeDiff+8, ptrSize)
+	return alarmV40Layout{
+		union:    union,
+		timeDiff: timeDiff,
+		size:     header + ptrSize,
+	}
+}
+
+// alarmV40 当前平台的V40报警信息布局
+var alarmV40 = newAlarmV40Layout(strconv.IntSize / 8)
+
+// DecodeAlarmInfoV40 解码 NET_DVR_ALARMINFO_V40
+// 可变部分（DWORD数组）由SDK后端拷贝到结构体之后
+// 参数：
+//   - info: 报警信息原始字节
+//
+// 返回值：
+//   - *AlarmInfo: 报警信息
+//   - error: 数据长度不足时返回错误
+func DecodeAlarmInfoV40(info []byte) (*AlarmInfo, error) {
+	return decodeAlarmInfoV40(info, alarmV40)
+}
+
+// decodeAlarmInfoV40 按指定布局解码V40报警信息
+func decodeAlarmInfoV40(info []byte, layout alarmV40Layout) (*AlarmInfo, error) {
+	if len(info) < layout.size {
+		return nil, fmt.Errorf("V40报警信息长度不足: %d < %d", len(info), layout.size)
+	}
+
+	le := binary.LittleEndian
+	a := &AlarmInfo{Type: AlarmType(le.Uint32(info[0:]))}
+
+	// 报警时间及时区
+	loc := time.Local
+	if info[layout.timeDiff] == 1 {
+		offset := int(int8(info[layout.timeDiff+1]))*3600 + int(int8(info[layout.timeDiff+2]))*60
+		loc = time.FixedZone("", offset)
+	}
+	a.Time = time.Date(int(le.Uint16(info[4:])), time.Month(info[6]), int(info[7]),
+		int(info[8]), int(info[9]), int(info[10]), 0, loc)
+
+	union := info[layout.union:]
+	data := info[layout.size:]
+	var err error
+	switch a.Type {
+	case AlarmTypeIO:
+		a.AlarmInputs = []int{int(le.Uint32(union[0:]))}
+		outNum, recNum := int(le.Uint32(union[4:])), int(le.Uint32(union[8:]))
+		if a.AlarmOutputs, data, err = readDwords(data, outNum); err != nil {
+			return nil, err
+		}
+		a.RecordChannels, _, err = readDwords(data, recNum)
+	case AlarmTypeDiskFull, AlarmTypeDiskUnformatted, AlarmTypeDiskError:
+		a.Disks, _, err = readDwords(data, int(le.Uint32(union[0:])))
+	case AlarmTypeVideoLoss, AlarmTypeMotion, AlarmTypeTamper, AlarmTypeVideoException,
+		AlarmTypeRecordException, 13, 28: // 13-前端/录像分辨率不匹配，28-相机视角异常
+		a.Channels, _, err = readDwords(data, int(le.Uint32(union[0:])))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// NET_VCA_RULE_ALARM 字段偏移
+const (
+	ruleAbsTimeOffset    = 8   // dwAbsTime
+	ruleIDOffset         = 12  // struRuleInfo.byRuleID
+	ruleEventExOffset    = 14  // struRuleInfo.wEventTypeEx
+	ruleNameOffset       = 16  // struRuleInfo.byRuleName[32]
+	ruleEventTypeOffset  = 48  // struRuleInfo.dwEventType
+	ruleTargetOffset     = 144 // struTargetInfo.dwID
+	ruleDevIPOffset      = 168 // struDevInfo.struDevIP.sIpV4[16]
+	ruleDevPortOffset    = 312 // struDevInfo.wPort
+	rulePicLenOffset     = 316 // dwPicDataLen
+	ruleIvmsChanExOffset = 328 // wDevInfoIvmsChannelEx
+	ruleAlarmSize        = 332 // 有效字段长度（不含结尾指针）
+)
+
+// DecodeRuleAlarm 解码 NET_VCA_RULE_ALARM
+// 参数：
+//   - info: 报警信息原始字节
+//
+// 返回值：
+//   - *RuleAlarm: 行为分析报警
+//   - error: 数据长度不足或规则名称转码失败时返回错误
+func DecodeRuleAlarm(info []byte) (*RuleAlarm, error) {
+	if len(info) < ruleAlarmSize {
+		return nil, fmt.Errorf("行为分析报警信息长度不足: %d < %d", len(info), ruleAlarmSize)
+	}
+
+	le := binary.LittleEndian
+	name, err := utils.GBKToUTF8(trimNul(info[ruleNameOffset:ruleEventTypeOffset]))
+	if err != nil {
+		return nil, fmt.Errorf("规则名称转码失败: %w", err)
+	}
+
+	r := &RuleAlarm{
+		RuleID:     int(info[ruleIDOffset]),
+		RuleName:   name,
+		EventType:  int(le.Uint16(info[ruleEventExOffset:])),
+		Time:       absTime(le.Uint32(info[ruleAbsTimeOffset:])),
+		TargetID:   int(le.Uint32(info[ruleTargetOffset:])),
+		DeviceIP:   string(trimNul(info[ruleDevIPOffset : ruleDevIPOffset+16])),
+		DevicePort: int(le.Uint16(info[ruleDevPortOffset:])),
+		Channel:    int(info[ruleDevPortOffset+2]),
+		IvmsChan:   int(le.Uint16(info[ruleIvmsChanExOffset:])),
+		PicDataLen: int(le.Uint32(info[rulePicLenOffset:])),
+	}
+	// wEventTypeEx 为0时使用兼容字段 dwEventType
+	if r.EventType == 0 {
+		r.EventType = int(le.Uint32(info[ruleEventTypeOffset:]))
+	}
+	// 老设备只填写 byIvmsChannel
+	if r.IvmsChan == 0 {
+		r.IvmsChan = int(info[ruleDevPortOffset+3])
+	}
+
+	rect := info[ruleTargetOffset+4:]
+	r.TargetRect = Rect{
+		X:      math.Float32frombits(le.Uint32(rect[0:])),
+		Y:      math.Float32frombits(le.Uint32(rect[4:])),
+		Width:  math.Float32frombits(le.Uint32(rect[8:])),
+		Height: math.Float32frombits(le.Uint32(rect[12:])),
+	}
+	return r, nil
+}
+
+// absTime 解析SDK压缩格式的绝对时间（年份从2000年起，6位秒/6位分/5位时/5位日/4位月）
+func absTime(t uint32) time.Time {
+	return time.Date(int(t>>26)+2000, time.Month((t>>22)&15), int((t>>17)&31),
+		int((t>>12)&31), int((t>>6)&63), int(t&63), 0, time.Local)
+}
+
+// flagIndexes 返回标志数组中值为1的位置（从1开始编号）
+func flagIndexes(flags []byte) []int {
+	var result []int
+	for i, f := range flags {
+		if f == 1 {
+			result = append(result, i+1)
+		}
+	}
+	return result
+}
+
+// readDwords 读取n个DWORD，返回读取结果和剩余数据
+func readDwords(data []byte, n int) ([]int, []byte, error) {
+	if n == 0 {
+		return nil, data, nil
+	}
+	if n < 0 || len(data) < n*4 {
+		return nil, data, fmt.Errorf("报警信息可变部分长度不足: 需要%d个DWORD，实际%d字节", n, len(data))
+	}
+	result := make([]int, n)
+	for i := range result {
+		result[i] = int(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return result, data[n*4:], nil
+}
+
+// trimNul 截断到第一个空字符
+func trimNul(b []byte) []byte {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return b[:i]
+	}
+	return b
+}
+
+// alignUp 按对齐长度向上取整
+func alignUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
@@ -0,0 +1,165 @@
+package alarm
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+	"github.com/samsaralc/hiksdk/core/utils"
+)
+
+func TestDecodeAlarmInfoV30(t *testing.T) {
+	info := make([]byte, 268)
+	binary.LittleEndian.PutUint32(info[0:], uint32(AlarmTypeMotion))
+	info[alarmV30ChannelOffset+0] = 1 // 通道1
+	info[alarmV30ChannelOffset+3] = 1 // 通道4
+	info[alarmV30RelateOffset+1] = 1  // 联动录像通道2
+	info[alarmV30OutputOffset+0] = 1  // 报警输出1
+
+	event, err := DecodeEvent(COMM_ALARM_V30, &sdk.Alarmer{DeviceIP: "192.168.1.64"}, info)
+	if err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	a := event.Alarm
+	if a == nil || a.Type != AlarmTypeMotion {
+		t.Fatalf("报警类型错误: %+v", a)
+	}
+	if !reflect.DeepEqual(a.Channels, []int{1, 4}) {
+		t.Errorf("报警通道错误: %v", a.Channels)
+	}
+	if !reflect.DeepEqual(a.RecordChannels, []int{2}) || !reflect.DeepEqual(a.AlarmOutputs, []int{1}) {
+		t.Errorf("联动信息错误: 录像%v 输出%v", a.RecordChannels, a.AlarmOutputs)
+	}
+	if a.AlarmInputs != nil {
+		t.Errorf("非IO报警不应有报警输入: %v", a.AlarmInputs)
+	}
+	if event.Alarmer.DeviceIP != "192.168.1.64" {
+		t.Errorf("报警设备信息丢失: %+v", event.Alarmer)
+	}
+
+	if _, err := DecodeAlarmInfoV30(info[:100]); err == nil {
+		t.Error("数据长度不足时应返回错误")
+	}
+}
+
+// buildAlarmV40 按指定布局构造V40报警信息（固定部分 + 可变部分）
+func buildAlarmV40(layout alarmV40Layout, alarmType AlarmType, union []uint32, data []uint32) []byte {
+	info := make([]byte, layout.size+len(data)*4)
+	le := binary.LittleEndian
+	le.PutUint32(info[0:], uint32(alarmType))
+	le.PutUint16(info[4:], 2024)
+	info[6], info[7], info[8], info[9], info[10] = 5, 20, 13, 14, 15
+	for i, v := range union {
+		le.PutUint32(info[layout.union+i*4:], v)
+	}
+	for i, v := range data {
+		le.PutUint32(info[layout.size+i*4:], v)
+	}
+	return info
+}
+
+func TestDecodeAlarmInfoV40(t *testing.T) {
+	// 32位和64位平台的结构体布局不同
+	for _, ptrSize := range []int{4, 8} {
+		layout := newAlarmV40Layout(ptrSize)
+
+		info := buildAlarmV40(layout, AlarmTypeVideoLoss, []uint32{2}, []uint32{33, 35})
+		info[layout.timeDiff] = 1
+		info[layout.timeDiff+1] = 8
+		a, err := decodeAlarmInfoV40(info, layout)
+		if err != nil {
+			t.Fatalf("[指针%d字节] 解码失败: %v", ptrSize, err)
+		}
+		if a.Type != AlarmTypeVideoLoss || !reflect.DeepEqual(a.Channels, []int{33, 35}) {
+			t.Errorf("[指针%d字节] 视频丢失报警解码错误: %+v", ptrSize, a)
+		}
+		want := time.Date(2024, 5, 20, 13, 14, 15, 0, time.FixedZone("", 8*3600))
+		if !a.Time.Equal(want) {
+			t.Errorf("[指针%d字节] 报警时间错误: %v, 期望 %v", ptrSize, a.Time, want)
+		}
+
+		// IO报警：可变部分依次为报警输出号和录像通道号
+		info = buildAlarmV40(layout, AlarmTypeIO, []uint32{3, 1, 2}, []uint32{1, 5, 6})
+		a, err = decodeAlarmInfoV40(info, layout)
+		if err != nil {
+			t.Fatalf("[指针%d字节] IO报警解码失败: %v", ptrSize, err)
+		}
+		if !reflect.DeepEqual(a.AlarmInputs, []int{3}) ||
+			!reflect.DeepEqual(a.AlarmOutputs, []int{1}) ||
+			!reflect.DeepEqual(a.RecordChannels, []int{5, 6}) {
+			t.Errorf("[指针%d字节] IO报警解码错误: %+v", ptrSize, a)
+		}
+
+		// 可变部分缺失
+		info = buildAlarmV40(layout, AlarmTypeMotion, []uint32{4}, []uint32{1})
+		if _, err := decodeAlarmInfoV40(info, layout); err == nil {
+			t.Errorf("[指针%d字节] 可变部分长度不足时应返回错误", ptrSize)
+		}
+	}
+
+	if l := newAlarmV40Layout(8); l.size != 160 || l.union != 16 {
+		t.Errorf("64位布局错误: %+v", l)
+	}
+	if l := newAlarmV40Layout(4); l.size != 144 || l.union != 12 {
+		t.Errorf("32位布局错误: %+v", l)
+	}
+}
+
+func TestDecodeRuleAlarm(t *testing.T) {
+	info := make([]byte, 352)
+	le := binary.LittleEndian
+
+	// 2024-05-20 13:14:15
+	abs := uint32(24)<<26 | 5<<22 | 20<<17 | 13<<12 | 14<<6 | 15
+	le.PutUint32(info[ruleAbsTimeOffset:], abs)
+	info[ruleIDOffset] = 2
+	le.PutUint16(info[ruleEventExOffset:], 1)
+	name, err := utils.UTF8ToGBK("周界入侵")
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(info[ruleNameOffset:], name)
+	le.PutUint32(info[ruleTargetOffset:], 77)
+	le.PutUint32(info[ruleTargetOffset+4:], math.Float32bits(0.25))
+	le.PutUint32(info[ruleTargetOffset+8:], math.Float32bits(0.5))
+	le.PutUint32(info[ruleTargetOffset+12:], math.Float32bits(0.1))
+	le.PutUint32(info[ruleTargetOffset+16:], math.Float32bits(0.2))
+	copy(info[ruleDevIPOffset:], "10.0.0.8")
+	le.PutUint16(info[ruleDevPortOffset:], 8000)
+	info[ruleDevPortOffset+2] = 1
+	info[ruleDevPortOffset+3] = 3
+
+	event, err := DecodeEvent(COMM_ALARM_RULE, nil, info)
+	if err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	r := event.Rule
+	if r == nil {
+		t.Fatal("行为分析报警未解码")
+	}
+	if r.RuleName != "周界入侵" || r.RuleID != 2 || r.EventType != 1 {
+		t.Errorf("规则信息错误: %+v", r)
+	}
+	if r.TargetID != 77 || r.TargetRect != (Rect{X: 0.25, Y: 0.5, Width: 0.1, Height: 0.2}) {
+		t.Errorf("目标信息错误: %d %+v", r.TargetID, r.TargetRect)
+	}
+	if r.DeviceIP != "10.0.0.8" || r.DevicePort != 8000 || r.Channel != 1 || r.IvmsChan != 3 {
+		t.Errorf("前端设备信息错误: %+v", r)
+	}
+	if want := time.Date(2024, 5, 20, 13, 14, 15, 0, time.Local); !r.Time.Equal(want) {
+		t.Errorf("报警时间错误: %v", r.Time)
+	}
+}
+
+func TestDecodeEventUnknown(t *testing.T) {
+	event, err := DecodeEvent(0x9999, nil, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("未知类型不应返回错误: %v", err)
+	}
+	if event.Alarm != nil || event.Rule != nil || len(event.Raw) != 3 {
+		t.Errorf("未知类型应只保留原始数据: %+v", event)
+	}
+}
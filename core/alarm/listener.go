@@ -1,35 +1,19 @@
 package alarm
 
-/*
-#cgo CFLAGS: -I../../include
-#cgo CFLAGS: -I..
-
-// Linux 平台的链接配置（需要在系统 LD_LIBRARY_PATH 中配置海康 SDK 库路径）
-#cgo linux LDFLAGS: -lhcnetsdk -lhpr -lHCCore
-
-// Windows 平台的链接配置（需要在系统 PATH 中配置海康 SDK 库路径）
-#cgo windows LDFLAGS: -lHCNetSDK -lHCCore
-
-#include <stdio.h>
-#include <stdlib.h>
-#include "../hiksdk_wrapper.h"
-
-// 声明Go回调函数
-extern void AlarmCallBack(LONG command, NET_DVR_ALARMER *alarm, char *info, DWORD len, void *user);
-*/
-import "C"
 import (
 	"fmt"
-	"log"
-	"unsafe"
+	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
 )
 
 // 报警类型常量（来自官方SDK）
 const (
 	// COMM_ALARM_RULE 行为分析报警
-	COMM_ALARM_RULE = 0x1101
+	COMM_ALARM_RULE = 0x1102
 	// COMM_ALARM_V30 V30报警信息（移动侦测、视频丢失、遮挡、IO等）
 	COMM_ALARM_V30 = 0x4000
 	// COMM_ALARM_V40 V40报警信息（扩展报警类型）
@@ -41,131 +25,258 @@ const MAX_SERIALNO_LEN = 48
 
 // AlarmListener 报警监听器
 // 封装设备报警监听的所有操作
+// 报警事件按登录ID路由到对应的监听器，通过 Events / OnEvent 订阅；
+// 没有订阅时事件只记录日志
 type AlarmListener struct {
-	loginID     int // 登录句柄
-	alarmHandle int // 报警句柄
+	mu          sync.Mutex
+	backend     sdk.Backend       // SDK后端
+	login       *core.LoginHandle // 登录句柄
+	alarmHandle int               // 报警句柄
+	armed       bool              // 是否处于布防状态（Start成功后至Stop前），用于重新布防
+	routeID     int               // 事件路由登记的登录ID，未登记时为-1
+	opts        ArmOptions        // 布防参数（重新布防时沿用）
+	events      hub               // 事件订阅
+	capture     SnapshotFunc      // 联动抓图函数，nil表示不抓图
 }
 
-// NewAlarmListener 创建报警监听器
+// NewAlarmListener 创建报警监听器（使用默认SDK后端）
 // 参数：
 //   - loginID: 设备登录ID
 //
 // 返回：
 //   - *AlarmListener: 报警监听器实例
 func NewAlarmListener(loginID int) *AlarmListener {
+	return NewAlarmListenerWithBackend(sdk.Default(), loginID)
+}
+
+// NewAlarmListenerWithBackend 使用指定SDK后端创建报警监听器
+// 参数：
+//   - backend: SDK后端
+//   - loginID: 设备登录ID
+//
+// 返回：
+//   - *AlarmListener: 报警监听器实例
+func NewAlarmListenerWithBackend(backend sdk.Backend, loginID int) *AlarmListener {
+	return NewAlarmListenerWithHandle(backend, core.NewLoginHandle(loginID))
+}
+
+// NewAlarmListenerWithHandle 使用共享登录句柄创建报警监听器
+// 重新登录后更新句柄并调用 Rearm 即可恢复布防
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+//
+// 返回：
+//   - *AlarmListener: 报警监听器实例
+func NewAlarmListenerWithHandle(backend sdk.Backend, login *core.LoginHandle) *AlarmListener {
 	return &AlarmListener{
-		loginID:     loginID,
+		backend:     backend,
+		login:       login,
 		alarmHandle: -1,
+		routeID:     -1,
+		opts:        DefaultArmOptions(),
 	}
 }
 
 // AlarmCallBack 报警回调函数
-// 由C代码调用，接收设备的报警信息
-// 这是一个全局回调函数，会被所有设备共享
-//
-//export AlarmCallBack
-func AlarmCallBack(command C.LONG, alarm *C.NET_DVR_ALARMER, info *C.char, len C.DWORD, user unsafe.Pointer) {
+// 由SDK后端调用，接收设备的报警信息
+// 这是一个全局回调函数，会被所有设备共享：解码后根据登录ID投递给对应监听器的订阅，
+// 找不到监听器或监听器没有订阅时记录日志
+func AlarmCallBack(command int, alarmer *sdk.Alarmer, info []byte) {
 	// 安全检查
-	if alarm == nil {
-		log.Println("警告: 收到空报警信息")
+	if alarmer == nil {
+		core.Logger().Warn("收到空报警信息", "command", command)
 		return
 	}
 
-	// 获取报警设备信息
-	var deviceIP string
-	if alarm.byDeviceIPValid == 1 {
-		// 提取设备IP（需要从DWORD转换为IP字符串）
-		ip := alarm.dwDeviceIP
-		deviceIP = fmt.Sprintf("%d.%d.%d.%d",
-			byte(ip&0xFF),
-			byte((ip>>8)&0xFF),
-			byte((ip>>16)&0xFF),
-			byte((ip>>24)&0xFF))
-	}
-
-	// 获取设备序列号
-	var serialNo string
-	if alarm.bySerialValid == 1 {
-		// 手动复制序列号字节
-		serialBytes := make([]byte, MAX_SERIALNO_LEN)
-		for i := 0; i < MAX_SERIALNO_LEN; i++ {
-			serialBytes[i] = byte(alarm.sSerialNumber[i])
-		}
-		// 去除空字符
-		for i, b := range serialBytes {
-			if b == 0 {
-				serialNo = string(serialBytes[:i])
-				break
-			}
-		}
-		if serialNo == "" {
-			serialNo = string(serialBytes)
-		}
+	a := lookup(alarmer.UserID)
+	logger := core.Logger()
+	if a != nil {
+		logger = a.login.Logger()
 	}
 
-	// 记录报警信息
-	log.Printf("收到报警 - 类型: 0x%X, 设备IP: %s, 序列号: %s", command, deviceIP, serialNo)
+	// 解码失败时仍投递包含原始数据的事件
+	event, err := DecodeEvent(command, alarmer, info)
+	if err != nil {
+		logger.Warn("报警信息解码失败", "command", command, "device_ip", alarmer.DeviceIP, "error", err)
+	}
 
-	// 根据命令类型处理不同的报警
-	switch int(command) {
-	case COMM_ALARM_RULE:
-		log.Println("  → 行为分析报警（规则检测）")
-	case COMM_ALARM_V30:
-		log.Println("  → 标准报警（移动侦测/视频丢失/遮挡/IO信号等）")
-	case COMM_ALARM_V40:
-		log.Println("  → 扩展报警V40（增强型报警信息）")
+	device := event.Source()
+	if a != nil {
+		device = a.login.Device()
+	}
+	core.GetMetrics().ObserveAlarm(device, eventType(event))
+
+	if a != nil && a.events.deliver(*event) {
+		return
+	}
+	logEvent(logger, event)
+}
+
+// logEvent 记录没有订阅接收的报警事件
+func logEvent(logger *slog.Logger, event *Event) {
+	attrs := []any{"command", event.Command, "device_ip", event.Alarmer.DeviceIP, "serial", event.Alarmer.SerialNumber}
+
+	// 根据命令类型附加不同的报警信息
+	switch {
+	case event.Rule != nil:
+		r := event.Rule
+		attrs = append(attrs, "rule_id", r.RuleID, "rule_name", r.RuleName, "event_type", r.EventType, "channel", r.Channel)
+	case event.Alarm != nil:
+		a := event.Alarm
+		attrs = append(attrs, "alarm_type", a.Type.String(), "channels", a.Channels, "alarm_inputs", a.AlarmInputs)
+	}
+	logger.Info("收到报警（无订阅）", attrs...)
+}
+
+// eventType 报警事件在指标中的类型标签
+// 报警信息使用报警类型名称，行为分析报警为"行为分析"，其他为报警消息类型的十六进制值
+func eventType(event *Event) string {
+	switch {
+	case event.Alarm != nil:
+		return event.Alarm.Type.String()
+	case event.Rule != nil:
+		return "行为分析"
 	default:
-		log.Printf("  → 其他报警类型: 0x%X\n", command)
+		return fmt.Sprintf("0x%X", event.Command)
 	}
 }
 
-// Start 启动报警监听
+// Start 启动报警监听（使用默认布防参数）
 // 建立报警上传通道，开始接收设备的报警信息
 // 返回值：
 //   - error: 错误信息，成功时为nil
 func (a *AlarmListener) Start() error {
-	if a.loginID < 0 {
+	return a.StartWithOptions(nil)
+}
+
+// StartWithOptions 使用指定布防参数启动报警监听
+// 参数在重新布防（Rearm）时沿用
+// 参数：
+//   - opts: 布防参数，nil表示使用 DefaultArmOptions()
+//
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (a *AlarmListener) StartWithOptions(opts *ArmOptions) error {
+	o := DefaultArmOptions()
+	if opts != nil {
+		o = *opts
+	}
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.opts = o
+	if err := a.start(); err != nil {
+		return err
+	}
+	a.armed = true
+	return nil
+}
+
+// start 建立报警上传通道（调用方必须持有锁）
+func (a *AlarmListener) start() error {
+	loginID := a.login.ID()
+	if loginID < 0 {
 		return fmt.Errorf("无效的登录ID")
 	}
 
 	// 设置报警回调函数
-	C.NET_DVR_SetDVRMessageCallBack_V30(
-		(*[0]byte)(C.AlarmCallBack),
-		nil,
-	)
-
-	// 设置报警布防参数
-	var setupParam C.NET_DVR_SETUPALARM_PARAM
-	setupParam.dwSize = C.DWORD(unsafe.Sizeof(setupParam))
-	setupParam.byLevel = 1         // 布防等级
-	setupParam.byAlarmInfoType = 1 // 上传报警信息类型：0-老报警信息，1-新报警信息
+	a.backend.SetDVRMessageCallBackV30(AlarmCallBack)
 
 	// 建立报警上传通道
-	a.alarmHandle = int(C.NET_DVR_SetupAlarmChan_V41(
-		C.LONG(a.loginID),
-		&setupParam,
-	))
-
-	if a.alarmHandle < 0 {
-		return core.NewHKError("建立报警上传通道")
+	began := time.Now()
+	handle := a.backend.SetupAlarmChanV41(loginID, a.opts.setupParam())
+	if handle < 0 {
+		err := core.NewHKErrorFrom(a.backend, "建立报警上传通道")
+		core.ObserveCall(core.OpAlarmArm, a.login.Device(), began, err)
+		return err
 	}
+	core.ObserveCall(core.OpAlarmArm, a.login.Device(), began, nil)
+	a.setAlarmHandle(handle)
 
-	log.Printf("✓ 报警监听启动成功（句柄: %d）", a.alarmHandle)
+	register(loginID, a)
+	a.routeID = loginID
+
+	a.login.Logger().Info("报警监听启动成功", "handle", a.alarmHandle)
 	return nil
 }
 
 // Stop 停止报警监听
-// 撤销报警上传通道，停止接收设备的报警信息
+// 撤销报警上传通道，停止接收设备的报警信息，并关闭所有事件订阅；
+// 撤销失败（如会话已失效）时同样停止监听并关闭订阅，再返回撤销的错误
 // 返回值：
 //   - error: 错误信息，成功时为nil
 func (a *AlarmListener) Stop() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// 关闭失败（如会话已失效）时句柄同样不再可用，仍然注销路由并关闭订阅
+	var err error
 	if a.alarmHandle >= 0 {
-		if C.NET_DVR_CloseAlarmChan_V30(C.LONG(a.alarmHandle)) != C.TRUE {
-			return core.NewHKError("关闭报警上传通道")
+		began := time.Now()
+		if !a.backend.CloseAlarmChanV30(a.alarmHandle) {
+			err = core.NewHKErrorFrom(a.backend, "关闭报警上传通道")
 		}
+		core.ObserveCall(core.OpAlarmDisarm, a.login.Device(), began, err)
 
-		a.alarmHandle = -1
-		log.Println("✓ 报警监听已停止")
+		a.setAlarmHandle(-1)
+		a.login.Logger().Info("报警监听已停止")
 	}
-	return nil
+	a.armed = false
+	a.unroute()
+	a.events.closeAll()
+	return err
+}
+
+// setAlarmHandle 更新报警句柄，并同步布防中的监听数指标（调用方必须持有锁）
+func (a *AlarmListener) setAlarmHandle(handle int) {
+	switch {
+	case a.alarmHandle < 0 && handle >= 0:
+		core.GetMetrics().AddArmedListeners(1)
+	case a.alarmHandle >= 0 && handle < 0:
+		core.GetMetrics().AddArmedListeners(-1)
+	}
+	a.alarmHandle = handle
+}
+
+// unroute 注销事件路由（调用方必须持有锁）
+func (a *AlarmListener) unroute() {
+	if a.routeID >= 0 {
+		unregister(a.routeID, a)
+		a.routeID = -1
+	}
+}
+
+// IsRunning 报警监听是否已启动
+func (a *AlarmListener) IsRunning() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.alarmHandle >= 0
+}
+
+// Rearm 重新布防
+// 用于重新登录之后：旧的报警句柄随旧会话失效，尽力关闭后使用新的登录ID重新建立上传通道
+// 未启动（或已停止）的监听器不做任何操作；重新布防失败时保持布防意图，可再次调用重试
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (a *AlarmListener) Rearm() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.armed {
+		return nil
+	}
+
+	// 旧句柄通常已随会话失效，关闭失败可以忽略
+	if a.alarmHandle >= 0 {
+		a.backend.CloseAlarmChanV30(a.alarmHandle)
+		a.setAlarmHandle(-1)
+	}
+	a.unroute()
+
+	return a.start()
 }
@@ -0,0 +1,129 @@
+package alarm
+
+import (
+	"fmt"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// ArmingLevel 布防优先级
+type ArmingLevel int
+
+const (
+	LevelHigh   ArmingLevel = 0 // 一等级（高）
+	LevelMedium ArmingLevel = 1 // 二等级（中）
+	LevelLow    ArmingLevel = 2 // 三等级（低）
+)
+
+// AlarmInfoType 上传报警信息类型（抓拍机支持）
+type AlarmInfoType int
+
+const (
+	AlarmInfoOld AlarmInfoType = 0 // 老报警信息（NET_DVR_PLATE_RESULT）
+	AlarmInfoNew AlarmInfoType = 1 // 新报警信息（NET_ITS_PLATE_RESULT）
+)
+
+// DeployType 布防类型
+type DeployType int
+
+const (
+	DeployClient   DeployType = 0 // 客户端布防（设备断线后不保存报警）
+	DeployRealTime DeployType = 1 // 实时布防
+)
+
+// Retransmit 断网续传类型（按位组合），设备恢复连接后补传断网期间的数据
+type Retransmit uint8
+
+const (
+	RetransmitPlate          Retransmit = 1 << 0 // 车牌检测
+	RetransmitPeopleCounting Retransmit = 1 << 1 // 客流统计
+	RetransmitHeatMap        Retransmit = 1 << 2 // 热度图统计
+	RetransmitFaceSnap       Retransmit = 1 << 3 // 人脸抓拍
+	RetransmitFaceMatch      Retransmit = 1 << 4 // 人脸比对
+)
+
+// URLUpload 图片数据采用URL方式上传（按位组合），需设备支持云存储
+type URLUpload uint8
+
+const (
+	URLFaceSnap  URLUpload = 1 << 0 // 人脸抓拍报警图片
+	URLEventJSON URLUpload = 1 << 1 // EVENT_JSON 报警图片
+	URLFaceMatch URLUpload = 1 << 2 // 人脸比对报警图片
+	URLRule      URLUpload = 1 << 3 // 行为分析报警（COMM_ALARM_RULE）图片
+)
+
+// Subscription 智能事件订阅（按位组合）
+type Subscription uint8
+
+const (
+	// SubscribeMotionTargetPicture 移动侦测人车分类上传图片（以V40报警信息上报）
+	SubscribeMotionTargetPicture Subscription = 1 << 7
+)
+
+// ArmOptions 布防参数
+// 不同型号的设备对参数组合的支持不同，设备不支持时布防会失败
+type ArmOptions struct {
+	Level         ArmingLevel   // 布防优先级
+	AlarmInfoType AlarmInfoType // 上传报警信息类型
+	AlarmInfoV40  bool          // 设备支持时以 COMM_ALARM_V40 上报（支持超过64个通道）
+	DeployType    DeployType    // 布防类型
+
+	FaceDetection bool         // 人脸侦测报警使用扩展结构
+	NoPicture     bool         // 二级布防时不上传图片
+	CopilotFace   bool         // 车辆报警上传副驾驶人脸子图
+	URLUpload     URLUpload    // 图片数据采用URL方式上传
+	UploadConfirm bool         // 开启数据上传确认机制
+	Retransmit    Retransmit   // 断网续传
+	Subscription  Subscription // 智能事件订阅
+}
+
+// DefaultArmOptions 返回默认布防参数（二等级、新报警信息、客户端布防）
+func DefaultArmOptions() ArmOptions {
+	return ArmOptions{
+		Level:         LevelMedium,
+		AlarmInfoType: AlarmInfoNew,
+		DeployType:    DeployClient,
+	}
+}
+
+// validate 检查参数取值范围
+func (o *ArmOptions) validate() error {
+	if o.Level < LevelHigh || o.Level > LevelLow {
+		return fmt.Errorf("布防优先级必须在 %d-%d 之间，当前值: %d", LevelHigh, LevelLow, o.Level)
+	}
+	if o.AlarmInfoType != AlarmInfoOld && o.AlarmInfoType != AlarmInfoNew {
+		return fmt.Errorf("无效的报警信息类型: %d", o.AlarmInfoType)
+	}
+	if o.DeployType != DeployClient && o.DeployType != DeployRealTime {
+		return fmt.Errorf("无效的布防类型: %d", o.DeployType)
+	}
+	return nil
+}
+
+// setupParam 转换为SDK布防参数
+func (o *ArmOptions) setupParam() *sdk.AlarmSetupParam {
+	param := &sdk.AlarmSetupParam{
+		Level:         int(o.Level),
+		AlarmInfoType: int(o.AlarmInfoType),
+		DeployType:    int(o.DeployType),
+		BrokenNetHTTP: uint8(o.Retransmit),
+		Subscription:  uint8(o.Subscription),
+		AlarmTypeURL:  uint8(o.URLUpload),
+	}
+	if o.AlarmInfoV40 {
+		param.RetAlarmTypeV40 = 1
+	}
+	if o.FaceDetection {
+		param.FaceAlarmDetection = 1
+	}
+	if o.NoPicture {
+		param.Support |= 1 << 0
+	}
+	if o.UploadConfirm {
+		param.Support |= 1 << 1
+	}
+	if o.CopilotFace {
+		param.CustomCtrl |= 1 << 0
+	}
+	return param
+}
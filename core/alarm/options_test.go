@@ -0,0 +1,85 @@
+package alarm
+
+import (
+	"testing"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestStartWithOptions 布防参数正确传递给SDK，重新布防时沿用
+func TestStartWithOptions(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+	l, _ := newTestListener(t, fake)
+
+	opts := DefaultArmOptions()
+	opts.Level = LevelHigh
+	opts.AlarmInfoV40 = true
+	opts.DeployType = DeployRealTime
+	opts.NoPicture = true
+	opts.UploadConfirm = true
+	opts.Retransmit = RetransmitFaceSnap | RetransmitFaceMatch
+	opts.URLUpload = URLRule
+	opts.Subscription = SubscribeMotionTargetPicture
+	if err := l.StartWithOptions(&opts); err != nil {
+		t.Fatalf("布防失败: %v", err)
+	}
+
+	want := sdk.AlarmSetupParam{
+		Level:           0,
+		AlarmInfoType:   1,
+		RetAlarmTypeV40: 1,
+		Support:         0x03,
+		BrokenNetHTTP:   0x18,
+		DeployType:      1,
+		Subscription:    0x80,
+		AlarmTypeURL:    0x08,
+	}
+	calls := fake.CallsTo("SetupAlarmChanV41")
+	if len(calls) != 1 || calls[0].Args[1] != want {
+		t.Fatalf("布防参数错误: %v，期望 %+v", calls, want)
+	}
+
+	if err := l.Rearm(); err != nil {
+		t.Fatalf("重新布防失败: %v", err)
+	}
+	calls = fake.CallsTo("SetupAlarmChanV41")
+	if len(calls) != 2 || calls[1].Args[1] != want {
+		t.Errorf("重新布防应沿用布防参数: %v", calls)
+	}
+}
+
+// TestStartDefaultOptions 默认布防参数（二等级、新报警信息）
+func TestStartDefaultOptions(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+	l, _ := newTestListener(t, fake)
+
+	if err := l.Start(); err != nil {
+		t.Fatalf("布防失败: %v", err)
+	}
+	want := sdk.AlarmSetupParam{Level: 1, AlarmInfoType: 1}
+	if calls := fake.CallsTo("SetupAlarmChanV41"); calls[0].Args[1] != want {
+		t.Errorf("默认布防参数错误: %v", calls[0])
+	}
+}
+
+// TestStartInvalidOptions 参数越界时不调用SDK
+func TestStartInvalidOptions(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+	l, _ := newTestListener(t, fake)
+
+	for _, opts := range []ArmOptions{
+		{Level: 3},
+		{AlarmInfoType: 2},
+		{DeployType: -1},
+	} {
+		if err := l.StartWithOptions(&opts); err == nil {
+			t.Errorf("参数 %+v 应返回错误", opts)
+		}
+	}
+	if calls := fake.CallsTo("SetupAlarmChanV41"); len(calls) != 0 {
+		t.Errorf("参数越界时不应调用SDK: %v", calls)
+	}
+}
@@ -0,0 +1,147 @@
+package alarm
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// ListenServer 报警监听服务（报警主机模式）
+// 在本地IP/端口上接收设备主动上传的报警，无需登录和布防；
+// 设备需在“报警主机”配置中填写本机地址和端口。
+// 事件解码方式与 AlarmListener 相同，来源设备通过 Event.Source() 区分
+type ListenServer struct {
+	mu           sync.Mutex
+	backend      sdk.Backend // SDK后端
+	localIP      string      // 本地监听IP，空表示所有地址
+	localPort    int         // 本地监听端口
+	listenHandle int         // 监听句柄
+	events       hub         // 事件订阅
+}
+
+// NewListenServer 创建报警监听服务（使用默认SDK后端）
+// 使用前SDK需已初始化，推荐通过 auth.Listen 创建
+// 参数：
+//   - localIP: 本地监听IP，空字符串表示监听所有本地地址
+//   - localPort: 本地监听端口
+//
+// 返回：
+//   - *ListenServer: 报警监听服务实例
+func NewListenServer(localIP string, localPort int) *ListenServer {
+	return NewListenServerWithBackend(sdk.Default(), localIP, localPort)
+}
+
+// NewListenServerWithBackend 使用指定SDK后端创建报警监听服务
+// 参数：
+//   - backend: SDK后端
+//   - localIP: 本地监听IP，空字符串表示监听所有本地地址
+//   - localPort: 本地监听端口
+//
+// 返回：
+//   - *ListenServer: 报警监听服务实例
+func NewListenServerWithBackend(backend sdk.Backend, localIP string, localPort int) *ListenServer {
+	return &ListenServer{
+		backend:      backend,
+		localIP:      localIP,
+		localPort:    localPort,
+		listenHandle: -1,
+	}
+}
+
+// Addr 返回监听地址
+func (s *ListenServer) Addr() string {
+	return fmt.Sprintf("%s:%d", s.localIP, s.localPort)
+}
+
+// Start 启动报警监听服务
+// 重复调用是安全的
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *ListenServer) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listenHandle >= 0 {
+		return nil
+	}
+	if s.localPort <= 0 || s.localPort > 65535 {
+		return fmt.Errorf("无效的监听端口: %d", s.localPort)
+	}
+
+	handle := s.backend.StartListenV30(s.localIP, uint16(s.localPort), s.callback)
+	if handle < 0 {
+		return core.NewHKErrorFrom(s.backend, "启动报警监听服务")
+	}
+	s.listenHandle = handle
+
+	core.Logger().Info("报警监听服务启动成功", "addr", s.Addr(), "handle", handle)
+	return nil
+}
+
+// Stop 停止报警监听服务，并关闭所有事件订阅
+// 停止失败时同样关闭订阅，再返回停止的错误
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *ListenServer) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.listenHandle >= 0 {
+		if !s.backend.StopListenV30(s.listenHandle) {
+			err = core.NewHKErrorFrom(s.backend, "停止报警监听服务")
+		}
+
+		s.listenHandle = -1
+		core.Logger().Info("报警监听服务已停止", "addr", s.Addr())
+	}
+	s.events.closeAll()
+	return err
+}
+
+// IsRunning 报警监听服务是否已启动
+func (s *ListenServer) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listenHandle >= 0
+}
+
+// Events 订阅报警事件，返回带缓冲的事件通道
+// 所有设备上传的报警都投递到同一通道，使用 Event.Source() 区分来源设备；
+// 通道在 Stop 后关闭
+// 参数：
+//   - opts: 投递参数，nil表示使用默认值
+//
+// 返回值：
+//   - <-chan Event: 报警事件通道
+func (s *ListenServer) Events(opts *DeliveryOptions) <-chan Event {
+	return s.events.subscribe(opts).ch
+}
+
+// OnEvent 订阅报警事件，在独立协程中按顺序调用处理函数
+// 参数：
+//   - handler: 事件处理函数
+//   - opts: 投递参数，nil表示使用默认值
+func (s *ListenServer) OnEvent(handler Handler, opts *DeliveryOptions) {
+	s.events.handle(handler, opts)
+}
+
+// callback 监听回调，解码后投递给订阅，没有订阅时记录日志
+func (s *ListenServer) callback(command int, alarmer *sdk.Alarmer, info []byte) {
+	if alarmer == nil {
+		core.Logger().Warn("收到空报警信息", "command", command)
+		return
+	}
+
+	event, err := DecodeEvent(command, alarmer, info)
+	if err != nil {
+		core.Logger().Warn("报警信息解码失败", "command", command, "source", event.Source(), "error", err)
+	}
+	core.GetMetrics().ObserveAlarm(event.Source(), eventType(event))
+
+	if !s.events.deliver(*event) {
+		logEvent(core.Logger(), event)
+	}
+}
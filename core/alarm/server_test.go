@@ -0,0 +1,105 @@
+package alarm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestListenServer 报警监听服务接收多台设备上传的报警并区分来源
+func TestListenServer(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	server := NewListenServerWithBackend(fake, "", 7200)
+	events := server.Events(nil)
+	if err := server.Start(); err != nil {
+		t.Fatalf("启动监听服务失败: %v", err)
+	}
+	if !server.IsRunning() {
+		t.Fatal("启动后应处于运行状态")
+	}
+
+	calls := fake.CallsTo("StartListenV30")
+	if len(calls) != 1 || calls[0].Args[1] != uint16(7200) {
+		t.Fatalf("监听参数错误: %v", calls)
+	}
+
+	fake.EmitListen(0, COMM_ALARM_V30, &sdk.Alarmer{UserID: -1, SerialNumber: "NVR-A"}, motionAlarm(1))
+	fake.EmitListen(0, COMM_ALARM_V30, &sdk.Alarmer{UserID: -1, DeviceIP: "10.0.0.2", LinkPort: 8000}, motionAlarm(2))
+
+	want := []string{"NVR-A", "10.0.0.2:8000"}
+	for i, source := range want {
+		select {
+		case event := <-events:
+			if event.Source() != source {
+				t.Errorf("事件%d来源错误: %s，期望 %s", i, event.Source(), source)
+			}
+			if event.Alarm == nil || event.Alarm.Channels[0] != i+1 {
+				t.Errorf("事件%d解码错误: %+v", i, event.Alarm)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("未收到事件%d", i)
+		}
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("停止监听服务失败: %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("Stop 后事件通道应关闭")
+	}
+	if len(fake.CallsTo("StopListenV30")) != 1 {
+		t.Error("应调用 StopListenV30")
+	}
+	if fake.EmitListen(0, COMM_ALARM_V30, &sdk.Alarmer{}, motionAlarm(1)) {
+		t.Error("停止后监听句柄应失效")
+	}
+}
+
+// TestListenServerStartError 启动失败时返回错误
+func TestListenServerStartError(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+	fake.FailOnce("StartListenV30", sdk.NET_DVR_PARAMETER_ERROR)
+
+	server := NewListenServerWithBackend(fake, "127.0.0.1", 7200)
+	if err := server.Start(); err == nil {
+		t.Fatal("启动失败时应返回错误")
+	}
+	if server.IsRunning() {
+		t.Error("启动失败后不应处于运行状态")
+	}
+	if err := NewListenServerWithBackend(fake, "", 0).Start(); err == nil {
+		t.Error("无效端口应返回错误")
+	}
+}
+
+// TestListenServerStopError 停止监听失败时仍关闭事件订阅
+func TestListenServerStopError(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	server := NewListenServerWithBackend(fake, "", 7200)
+	events := server.Events(nil)
+	if err := server.Start(); err != nil {
+		t.Fatalf("启动监听服务失败: %v", err)
+	}
+
+	fake.FailWith("StopListenV30", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+	if err := server.Stop(); err == nil {
+		t.Fatal("停止失败时应返回错误")
+	}
+	if server.IsRunning() {
+		t.Error("停止失败后不应处于运行状态")
+	}
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("停止失败后不应收到事件")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("停止失败后事件通道应关闭")
+	}
+}
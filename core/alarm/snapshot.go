@@ -0,0 +1,71 @@
+package alarm
+
+// SnapshotFunc 抓图函数，返回指定通道的 JPEG 图片
+// 通常由 auth.Device.Snapshot 包装而来
+type SnapshotFunc func(channel int) ([]byte, error)
+
+// Snapshot 报警联动抓图
+type Snapshot struct {
+	Channel int    // 通道号
+	Data    []byte // JPEG 图片数据，抓图失败时为nil
+	Err     error  // 抓图错误
+}
+
+// VideoChannels 返回报警涉及的视频通道号（用于联动抓图）
+// 移动侦测、视频丢失等报警返回报警通道；行为分析报警优先返回后端设备通道号；
+// 信号量报警等不涉及视频通道的事件返回nil
+func (e Event) VideoChannels() []int {
+	switch {
+	case e.Alarm != nil:
+		return e.Alarm.Channels
+	case e.Rule != nil && e.Rule.IvmsChan > 0:
+		return []int{e.Rule.IvmsChan}
+	case e.Rule != nil && e.Rule.Channel > 0:
+		return []int{e.Rule.Channel}
+	default:
+		return nil
+	}
+}
+
+// AttachSnapshots 对报警涉及的每个视频通道抓图，结果写入 event.Snapshots
+// 抓图通过网络完成，耗时较长，不能在SDK回调线程中调用
+// 参数：
+//   - event: 报警事件
+//   - capture: 抓图函数
+func AttachSnapshots(event *Event, capture SnapshotFunc) {
+	for _, channel := range event.VideoChannels() {
+		data, err := capture(channel)
+		event.Snapshots = append(event.Snapshots, Snapshot{Channel: channel, Data: data, Err: err})
+	}
+}
+
+// WithSnapshots 包装事件处理函数：先对报警通道抓图，再调用处理函数
+// 配合 OnEvent 使用时抓图在订阅协程中进行，不阻塞SDK回调线程
+// 参数：
+//   - capture: 抓图函数
+//   - handler: 事件处理函数
+func WithSnapshots(capture SnapshotFunc, handler Handler) Handler {
+	return func(event Event) {
+		AttachSnapshots(&event, capture)
+		handler(event)
+	}
+}
+
+// EnableSnapshots 启用报警联动抓图
+// 启用后新建的订阅（Events / OnEvent）收到的事件都带有报警通道的抓图；
+// 已有的订阅不受影响。传入nil关闭联动抓图；
+// 启用后 Events 返回的通道由抓图协程转发，消费方应一直读到通道关闭
+// 参数：
+//   - capture: 抓图函数
+func (a *AlarmListener) EnableSnapshots(capture SnapshotFunc) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.capture = capture
+}
+
+// snapshotFunc 返回当前的抓图函数
+func (a *AlarmListener) snapshotFunc() SnapshotFunc {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.capture
+}
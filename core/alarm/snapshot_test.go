@@ -0,0 +1,108 @@
+package alarm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestListenerSnapshots 启用联动抓图后，事件带有报警通道的抓图
+func TestListenerSnapshots(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	l, id := newTestListener(t, fake)
+	plain := l.Events(nil)
+	l.EnableSnapshots(func(channel int) ([]byte, error) {
+		if channel == 3 {
+			return nil, errors.New("抓图失败")
+		}
+		return []byte{0xFF, 0xD8, byte(channel)}, nil
+	})
+	withSnapshots := l.Events(nil)
+	handled := make(chan Event, 1)
+	l.OnEvent(func(e Event) { handled <- e }, nil)
+	if err := l.Start(); err != nil {
+		t.Fatalf("启动监听器失败: %v", err)
+	}
+
+	info := motionAlarm(2)
+	info[alarmV30ChannelOffset+2] = 1 // 通道3
+	fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: id}, info)
+
+	for name, ch := range map[string]<-chan Event{"Events": withSnapshots, "OnEvent": handled} {
+		select {
+		case e := <-ch:
+			s := e.Snapshots
+			if len(s) != 2 || s[0].Channel != 2 || s[0].Data[2] != 2 || s[1].Channel != 3 || s[1].Err == nil {
+				t.Errorf("%s 的抓图错误: %+v", name, s)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s 未收到事件", name)
+		}
+	}
+	// 启用之前的订阅不受影响
+	if e := <-plain; e.Snapshots != nil {
+		t.Errorf("启用前的订阅不应抓图: %+v", e.Snapshots)
+	}
+
+	l.Stop()
+	if _, ok := <-withSnapshots; ok {
+		t.Error("Stop 后抓图订阅的通道应关闭")
+	}
+}
+
+// TestListenerSnapshotsStop 消费者未读取抓图订阅时，Stop 后转发协程退出并关闭通道
+func TestListenerSnapshotsStop(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.Init()
+
+	l, id := newTestListener(t, fake)
+	l.EnableSnapshots(func(channel int) ([]byte, error) {
+		return []byte{0xFF, 0xD8}, nil
+	})
+	events := l.Events(&DeliveryOptions{BufferSize: 1})
+	if cap(events) != 1 {
+		t.Errorf("抓图订阅的缓冲区应与订阅一致，实际: %d", cap(events))
+	}
+	if err := l.Start(); err != nil {
+		t.Fatalf("启动监听器失败: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		fake.Emit(COMM_ALARM_V30, &sdk.Alarmer{UserID: id}, motionAlarm(1))
+	}
+	l.Stop()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Stop 后抓图订阅的通道未关闭")
+		}
+	}
+}
+
+// TestVideoChannels 行为分析报警优先使用后端通道号，信号量报警没有视频通道
+func TestVideoChannels(t *testing.T) {
+	cases := []struct {
+		event Event
+		want  []int
+	}{
+		{Event{Rule: &RuleAlarm{Channel: 1, IvmsChan: 5}}, []int{5}},
+		{Event{Rule: &RuleAlarm{Channel: 1}}, []int{1}},
+		{Event{Alarm: &AlarmInfo{Type: AlarmTypeIO, AlarmInputs: []int{1}}}, nil},
+		{Event{}, nil},
+	}
+	for _, c := range cases {
+		got := c.event.VideoChannels()
+		if len(got) != len(c.want) || (len(got) > 0 && got[0] != c.want[0]) {
+			t.Errorf("VideoChannels() = %v，期望 %v", got, c.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// DefaultLoginConcurrency LoginAll 默认的最大并发登录数
+const DefaultLoginConcurrency = 32
+
+// LoginFuture 异步登录的结果
+// 登录完成（成功、失败或上下文取消）后 Done 返回的通道被关闭
+type LoginFuture struct {
+	done    chan struct{}
+	once    sync.Once
+	session *SessionInfo
+	err     error
+}
+
+// Done 返回登录完成时关闭的通道，可用于 select
+func (f *LoginFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait 等待登录完成
+// 返回值：
+//   - *SessionInfo: 会话信息
+//   - error: 错误信息，成功时为nil；上下文取消时为 ctx.Err()
+func (f *LoginFuture) Wait() (*SessionInfo, error) {
+	<-f.done
+	return f.session, f.err
+}
+
+// resolve 设置登录结果，只有第一次生效
+func (f *LoginFuture) resolve(session *SessionInfo, err error) bool {
+	resolved := false
+	f.once.Do(func() {
+		f.session, f.err = session, err
+		close(f.done)
+		resolved = true
+	})
+	return resolved
+}
+
+// LoginV40Async 使用V40接口异步登录设备
+// 立即返回，登录结果由SDK的异步登录回调（fLoginResultCallBack）给出，不占用等待中的goroutine。
+// 上下文取消后 Future 立即以 ctx.Err() 完成，之后才到达的登录成功结果会被自动登出。
+// 异步登录只返回V30设备信息，SessionInfo.Device 中的密码等级和锁定信息为零值
+// 参数：
+//   - ctx: 上下文
+//   - cred: 登录凭据
+//
+// 返回值：
+//   - *LoginFuture: 异步登录的结果
+func LoginV40Async(ctx context.Context, cred *Credentials) *LoginFuture {
+	future := &LoginFuture{done: make(chan struct{})}
+	if err := initSDK(); err != nil {
+		future.resolve(nil, err)
+		return future
+	}
+	loginV40Async(ctx, sdk.Default(), cred, future)
+	return future
+}
+
+// loginV40Async 使用指定后端异步登录，结果写入 future
+// 调用前SDK必须已初始化
+func loginV40Async(ctx context.Context, backend sdk.Backend, cred *Credentials, future *LoginFuture) {
+	if err := ctx.Err(); err != nil {
+		future.resolve(nil, err)
+		return
+	}
+
+	loginInfo := &sdk.LoginInfo{
+		DeviceAddress: cred.IP,
+		Port:          cred.Port,
+		Username:      cred.Username,
+		Password:      cred.Password,
+	}
+	const operation = "登录设备(V40异步)"
+	began := time.Now()
+	accepted := backend.LoginV40Async(loginInfo, func(userID int, deviceInfo *sdk.DeviceInfo, errorCode int) {
+		if errorCode != sdk.NET_DVR_NOERROR {
+			err := &core.HKError{Code: errorCode, Msg: core.GetErrorMsg(errorCode), Operation: operation}
+			core.ObserveCall(core.OpLogin, cred.addr(), began, err)
+			future.resolve(nil, err)
+			return
+		}
+		core.ObserveCall(core.OpLogin, cred.addr(), began, nil)
+
+		session := &SessionInfo{
+			LoginID:      userID,
+			SerialNumber: deviceInfo.SerialNumber,
+			ChannelNum:   deviceInfo.ChanNum,
+			Device:       newDeviceInfo(deviceInfo),
+		}
+		if !future.resolve(session, nil) {
+			// 已因上下文取消而完成，释放迟到的会话（不在SDK回调线程中调用SDK接口）
+			go backend.Logout(userID)
+			return
+		}
+		core.GetMetrics().AddSessions(1)
+		logLogin(core.Logger(), "V40异步", cred, session, time.Since(began))
+	})
+	if !accepted {
+		future.resolve(nil, core.NewHKErrorFrom(backend, operation))
+		return
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			future.resolve(nil, ctx.Err())
+		case <-future.done:
+		}
+	}()
+}
+
+// LoginResult 批量登录中单个设备的结果
+type LoginResult struct {
+	Credentials Credentials // 登录凭据
+	Device      *Device     // 设备句柄，登录失败时为nil
+	Err         error       // 错误信息，成功时为nil
+}
+
+// LoginAll 批量异步登录设备，限制同时进行的登录数
+// 结果与 creds 一一对应；部分设备登录失败不影响其他设备，调用方需检查每个结果的 Err。
+// 上下文取消后尚未开始的登录直接返回 ctx.Err()
+// 参数：
+//   - ctx: 上下文
+//   - creds: 登录凭据列表
+//   - concurrency: 最大并发登录数，<=0 表示 DefaultLoginConcurrency
+//
+// 返回值：
+//   - []LoginResult: 登录结果，成功的设备使用完毕后需调用 Close
+func LoginAll(ctx context.Context, creds []Credentials, concurrency int) []LoginResult {
+	results := make([]LoginResult, len(creds))
+	for i := range creds {
+		results[i].Credentials = creds[i]
+	}
+	if err := initSDK(); err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+	loginAll(ctx, sdk.Default(), results, concurrency)
+	return results
+}
+
+// loginAll 使用指定后端批量登录，结果写入 results
+func loginAll(ctx context.Context, backend sdk.Backend, results []LoginResult, concurrency int) {
+	if concurrency <= 0 {
+		concurrency = DefaultLoginConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		r := &results[i]
+		future := &LoginFuture{done: make(chan struct{})}
+		loginV40Async(ctx, backend, &r.Credentials, future)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			session, err := future.Wait()
+			if err != nil {
+				r.Err = err
+				return
+			}
+			r.Device = newDevice(backend, &r.Credentials, session)
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Err == nil {
+			succeeded++
+		}
+	}
+	core.Logger().Info("批量登录完成", "succeeded", succeeded, "failed", len(results)-succeeded)
+}
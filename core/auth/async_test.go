@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestLoginV40Async 登录结果通过回调返回
+func TestLoginV40Async(t *testing.T) {
+	fake := useFake(t)
+	fake.DeviceInfo.ChanNum = 4
+
+	future := LoginV40Async(context.Background(), &Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	session, err := future.Wait()
+	if err != nil {
+		t.Fatalf("异步登录失败: %v", err)
+	}
+	if session.LoginID < 0 || session.ChannelNum != 4 || session.Device.AnalogChannels != 4 {
+		t.Errorf("会话信息错误: %+v", session)
+	}
+	select {
+	case <-future.Done():
+	default:
+		t.Error("登录完成后 Done 应已关闭")
+	}
+
+	fake.FailOnce("LoginV40Async", sdk.NET_DVR_PASSWORD_ERROR)
+	_, err = LoginV40Async(context.Background(), &Credentials{IP: "192.168.1.65"}).Wait()
+	var hkErr *core.HKError
+	if !errors.As(err, &hkErr) || hkErr.Code != sdk.NET_DVR_PASSWORD_ERROR {
+		t.Errorf("应返回登录失败的错误码，实际: %v", err)
+	}
+}
+
+// TestLoginV40AsyncCancel 上下文取消后立即返回，迟到的会话被登出
+func TestLoginV40AsyncCancel(t *testing.T) {
+	fake := useFake(t)
+	fake.LoginDelay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	future := LoginV40Async(ctx, &Credentials{IP: "192.168.1.64"})
+	cancel()
+	if _, err := future.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("应返回 context.Canceled，实际: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(fake.CallsTo("Logout")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("迟到的会话应被登出")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestLoginAll 批量登录限制并发数，结果与凭据一一对应
+func TestLoginAll(t *testing.T) {
+	fake := useFake(t)
+	fake.LoginDelay = 5 * time.Millisecond
+
+	creds := make([]Credentials, 10)
+	for i := range creds {
+		creds[i] = Credentials{IP: fmt.Sprintf("192.168.1.%d", 100+i), Port: 8000}
+	}
+	fake.FailOnce("LoginV40Async", sdk.NET_DVR_NETWORK_FAIL_CONNECT)
+
+	results := LoginAll(context.Background(), creds, 3)
+	if len(results) != len(creds) {
+		t.Fatalf("结果数量错误: %d", len(results))
+	}
+	if results[0].Err == nil || results[0].Device != nil {
+		t.Errorf("第一台设备应登录失败: %+v", results[0])
+	}
+	for _, r := range results[1:] {
+		if r.Err != nil || r.Device == nil {
+			t.Fatalf("登录失败: %+v", r)
+		}
+		if r.Device.Credentials().IP != r.Credentials.IP {
+			t.Errorf("结果与凭据不对应: %+v", r)
+		}
+		r.Device.Close()
+	}
+	if n := fake.MaxPendingLogins(); n > 3 {
+		t.Errorf("同时进行的登录不应超过3个，实际: %d", n)
+	}
+}
+
+// TestLoginAllCancel 上下文已取消时不再发起登录
+func TestLoginAllCancel(t *testing.T) {
+	fake := useFake(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := LoginAll(ctx, []Credentials{{IP: "192.168.1.64"}, {IP: "192.168.1.65"}}, 1)
+	for _, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("应返回 context.Canceled，实际: %v", r.Err)
+		}
+	}
+	if len(fake.CallsTo("LoginV40Async")) != 0 {
+		t.Error("上下文已取消时不应发起登录")
+	}
+}
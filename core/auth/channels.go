@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+	"github.com/samsaralc/hiksdk/core/utils"
+)
+
+// 参数配置命令（来自官方SDK）
+const (
+	// NET_DVR_GET_IPPARACFG_V40 获取IP接入配置（通道参数为组号）
+	NET_DVR_GET_IPPARACFG_V40 = 1062
+	// NET_DVR_GET_PICCFG_V40 获取图像参数（含通道名称）
+	NET_DVR_GET_PICCFG_V40 = 6179
+)
+
+// NET_DVR_IPPARACFG_V40 结构体布局
+const (
+	ipParaCfgSize     = 50792 // 结构体大小
+	ipDevInfoOffset   = 84    // struIPDevInfo 偏移
+	ipDevInfoSize     = 296   // NET_DVR_IPDEVINFO_V31 大小
+	streamModeOffset  = 19028 // struStreamMode 偏移
+	streamModeSize    = 496   // NET_DVR_STREAM_MODE 大小
+	channelsPerGroup  = 64    // 每组通道数（MAX_CHANNUM_V30）
+	ipDevAddrOffset   = 116   // NET_DVR_IPDEVINFO_V31.struIP.sIpV4 偏移
+	ipDevPortOffset   = 260   // NET_DVR_IPDEVINFO_V31.wDVRPort 偏移
+	channelNameOffset = 4     // NET_DVR_PICCFG_V40.sChanName 偏移
+	channelNameLen    = 32    // 通道名称长度（NAME_LEN）
+)
+
+// picCfgBufferSize 获取图像参数的缓冲区大小（大于 NET_DVR_PICCFG_V40）
+const picCfgBufferSize = 64 * 1024
+
+// ChannelKind 通道类型
+type ChannelKind int
+
+const (
+	ChannelAnalog ChannelKind = 0 // 模拟通道
+	ChannelIP     ChannelKind = 1 // 数字（IP）通道
+)
+
+// String 返回通道类型的中文描述
+func (k ChannelKind) String() string {
+	switch k {
+	case ChannelAnalog:
+		return "模拟通道"
+	case ChannelIP:
+		return "数字通道"
+	default:
+		return fmt.Sprintf("未知通道类型(%d)", int(k))
+	}
+}
+
+// Protocol 前端设备的接入协议（NET_DVR_IPDEVINFO_V31.byProType）
+type Protocol int
+
+const (
+	ProtocolPrivate   Protocol = 0 // 海康私有协议
+	ProtocolPanasonic Protocol = 1 // 松下
+	ProtocolSony      Protocol = 2 // 索尼
+)
+
+// String 返回接入协议的描述
+// 其他取值（如 ONVIF）与设备有关，以编号表示
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolPrivate:
+		return "私有协议"
+	case ProtocolPanasonic:
+		return "松下"
+	case ProtocolSony:
+		return "索尼"
+	default:
+		return fmt.Sprintf("协议%d", int(p))
+	}
+}
+
+// Channel 通道描述
+// Number 即 PTZ、预览、抓图、回放等接口使用的通道号（NVR 的数字通道从 DeviceInfo.StartIPChannel 开始）
+type Channel struct {
+	Number        int         // 通道号
+	Kind          ChannelKind // 通道类型
+	Name          string      // 通道名称（获取失败时为空）
+	Online        bool        // 模拟通道：是否启用；数字通道：前端设备是否在线
+	DeviceIP      string      // 前端设备IP（仅数字通道，未添加设备时为空）
+	DevicePort    int         // 前端设备端口（仅数字通道）
+	Protocol      Protocol    // 前端设备接入协议（仅数字通道）
+	RemoteChannel int         // 前端设备上的通道号（仅数字通道）
+}
+
+// Channels 获取设备的全部模拟通道和数字通道
+// 读取 NET_DVR_IPPARACFG_V40；设备不支持IP接入（如IPC）时按登录信息返回模拟通道
+// 返回值：
+//   - []Channel: 通道列表，模拟通道在前
+//   - error: 错误信息，成功时为nil
+func (d *Device) Channels() ([]Channel, error) {
+	d.mu.Lock()
+	info := d.session.Device
+	d.mu.Unlock()
+	userID := d.GetLoginID()
+	if userID < 0 {
+		return nil, fmt.Errorf("无效的登录ID：%d", userID)
+	}
+
+	var channels []Channel
+	buf := make([]byte, ipParaCfgSize)
+	for group, groups := 0, 1; group < groups; group++ {
+		clear(buf)
+		if _, ok := d.backend.GetDVRConfig(userID, NET_DVR_GET_IPPARACFG_V40, group, buf); !ok {
+			if group == 0 && d.backend.GetLastError() == sdk.NET_DVR_NOSUPPORT {
+				channels = analogChannels(info)
+				break
+			}
+			return nil, core.NewHKErrorFrom(d.backend, fmt.Sprintf("获取IP接入配置[组:%d]", group))
+		}
+
+		le := binary.LittleEndian
+		if group == 0 {
+			groups = max(int(le.Uint32(buf[4:])), 1)
+			analogNum := min(int(le.Uint32(buf[8:])), channelsPerGroup)
+			for i := 0; i < analogNum; i++ {
+				channels = append(channels, Channel{
+					Number: info.StartChannel + i,
+					Kind:   ChannelAnalog,
+					Online: buf[20+i] != 0,
+				})
+			}
+		}
+		channels = append(channels, parseIPChannels(buf)...)
+	}
+
+	for i := range channels {
+		channels[i].Name = d.channelName(userID, channels[i].Number)
+	}
+
+	d.login.Logger().Debug("获取通道列表成功", "channels", len(channels))
+	return channels, nil
+}
+
+// analogChannels 根据登录信息构造模拟通道列表
+func analogChannels(info DeviceInfo) []Channel {
+	channels := make([]Channel, 0, info.AnalogChannels)
+	for i := 0; i < info.AnalogChannels; i++ {
+		channels = append(channels, Channel{
+			Number: info.StartChannel + i,
+			Kind:   ChannelAnalog,
+			Online: true,
+		})
+	}
+	return channels
+}
+
+// parseIPChannels 解析一组 NET_DVR_IPPARACFG_V40 中的数字通道
+func parseIPChannels(buf []byte) []Channel {
+	le := binary.LittleEndian
+	num := min(int(le.Uint32(buf[12:])), channelsPerGroup)
+	start := int(le.Uint32(buf[16:]))
+
+	channels := make([]Channel, 0, num)
+	for i := 0; i < num; i++ {
+		ch := Channel{Number: start + i, Kind: ChannelIP}
+
+		// 只有直接从设备取流的通道才能解析出前端设备
+		mode := buf[streamModeOffset+i*streamModeSize:]
+		if mode[0] == 0 {
+			chanInfo := mode[4:]
+			ipID := int(chanInfo[1]) | int(chanInfo[3])<<8
+			ch.Online = chanInfo[0] != 0
+			ch.RemoteChannel = int(chanInfo[2])
+			if ipID >= 1 && ipID <= channelsPerGroup {
+				dev := buf[ipDevInfoOffset+(ipID-1)*ipDevInfoSize:]
+				ch.Protocol = Protocol(dev[1])
+				ch.DeviceIP = cString(dev[ipDevAddrOffset : ipDevAddrOffset+16])
+				ch.DevicePort = int(le.Uint16(dev[ipDevPortOffset:]))
+			}
+		}
+		channels = append(channels, ch)
+	}
+	return channels
+}
+
+// channelName 获取通道名称，失败时返回空字符串
+func (d *Device) channelName(userID, channel int) string {
+	buf := make([]byte, picCfgBufferSize)
+	if _, ok := d.backend.GetDVRConfig(userID, NET_DVR_GET_PICCFG_V40, channel, buf); !ok {
+		return ""
+	}
+	name := cString(buf[channelNameOffset : channelNameOffset+channelNameLen])
+	if s, err := utils.GBKToUTF8([]byte(name)); err == nil {
+		return s
+	}
+	return name
+}
+
+// cString 截取C字符串（到第一个空字符为止）
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
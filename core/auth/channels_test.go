@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+	"github.com/samsaralc/hiksdk/core/utils"
+)
+
+// ipParaCfg 构造 NET_DVR_IPPARACFG_V40：2个数字通道，第一个接入 192.168.1.100:8000 的通道1并在线
+func ipParaCfg(analog int) []byte {
+	le := binary.LittleEndian
+	buf := make([]byte, ipParaCfgSize)
+	le.PutUint32(buf[0:], ipParaCfgSize)
+	le.PutUint32(buf[4:], 1)
+	le.PutUint32(buf[8:], uint32(analog))
+	le.PutUint32(buf[12:], 2)
+	le.PutUint32(buf[16:], 33)
+	for i := 0; i < analog; i++ {
+		buf[20+i] = 1
+	}
+
+	dev := buf[ipDevInfoOffset:]
+	dev[0], dev[1] = 1, byte(ProtocolPrivate)
+	copy(dev[ipDevAddrOffset:], "192.168.1.100")
+	le.PutUint16(dev[ipDevPortOffset:], 8000)
+
+	mode := buf[streamModeOffset:]
+	mode[4], mode[5], mode[6] = 1, 1, 1 // 在线，IP设备1，前端通道1
+	return buf
+}
+
+// TestChannels NVR返回模拟通道和数字通道，数字通道从起始数字通道号开始
+func TestChannels(t *testing.T) {
+	fake := useFake(t)
+	fake.SetConfig(NET_DVR_GET_IPPARACFG_V40, 0, ipParaCfg(1))
+	name, _ := utils.UTF8ToGBK("大门")
+	picCfg := make([]byte, 64)
+	copy(picCfg[channelNameOffset:], name)
+	fake.SetConfig(NET_DVR_GET_PICCFG_V40, 33, picCfg)
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	defer dev.Close()
+
+	channels, err := dev.Channels()
+	if err != nil {
+		t.Fatalf("获取通道列表失败: %v", err)
+	}
+	if len(channels) != 3 {
+		t.Fatalf("应有1个模拟通道和2个数字通道，实际: %+v", channels)
+	}
+	if ch := channels[0]; ch.Number != 1 || ch.Kind != ChannelAnalog || !ch.Online {
+		t.Errorf("模拟通道错误: %+v", ch)
+	}
+	want := Channel{Number: 33, Kind: ChannelIP, Name: "大门", Online: true, DeviceIP: "192.168.1.100", DevicePort: 8000, RemoteChannel: 1}
+	if channels[1] != want {
+		t.Errorf("数字通道错误: %+v", channels[1])
+	}
+	if ch := channels[2]; ch.Number != 34 || ch.Online || ch.DeviceIP != "" {
+		t.Errorf("未接入设备的数字通道错误: %+v", ch)
+	}
+}
+
+// TestChannelsNotSupported 设备不支持IP接入时按登录信息返回模拟通道
+func TestChannelsNotSupported(t *testing.T) {
+	fake := useFake(t)
+	fake.DeviceInfo.ChanNum = 2
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	defer dev.Close()
+
+	channels, err := dev.Channels()
+	if err != nil || len(channels) != 2 || channels[1].Number != 2 || channels[1].Kind != ChannelAnalog {
+		t.Errorf("应返回2个模拟通道: %+v %v", channels, err)
+	}
+
+	fake.FailOnce("GetDVRConfig", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+	if _, err := dev.Channels(); err == nil {
+		t.Error("网络错误应返回错误")
+	}
+}
@@ -0,0 +1,417 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/alarm"
+	"github.com/samsaralc/hiksdk/core/capability"
+	"github.com/samsaralc/hiksdk/core/playback"
+	"github.com/samsaralc/hiksdk/core/preview"
+	"github.com/samsaralc/hiksdk/core/ptz"
+	"github.com/samsaralc/hiksdk/core/sdk"
+	"github.com/samsaralc/hiksdk/core/snapshot"
+)
+
+// errDeviceClosed 设备已关闭
+var errDeviceClosed = errors.New("设备已关闭")
+
+// Device 设备句柄
+// 持有一个登录会话，记住登录凭据和通道数，并按通道分发各类控制器
+// 同一通道多次获取返回同一个控制器实例；Close 时先停止预览、回放和报警监听再登出
+type Device struct {
+	mu      sync.Mutex
+	backend sdk.Backend       // SDK后端
+	cred    Credentials       // 登录凭据
+	session *SessionInfo      // 会话信息
+	login   *core.LoginHandle // 登录句柄（所有控制器共享，重新登录后更新）
+	logger  *slog.Logger      // 设备日志记录器，nil表示使用全局日志记录器
+	closed  bool              // 是否已关闭
+
+	controllers map[int]*ptz.Controller    // 通道号 -> PTZ控制器
+	presets     map[int]*ptz.PresetManager // 通道号 -> 预置点控制器
+	cruises     map[int]*ptz.CruiseManager // 通道号 -> 巡航控制器
+	tracks      map[int]*ptz.TrackManager  // 通道号 -> 轨迹控制器
+	alarms      *alarm.AlarmListener       // 报警监听器
+	streams     []*preview.Stream          // 正在进行的实时预览
+	capturer    *snapshot.Capturer         // 设备抓图
+	playback    *playback.Manager          // 录像查找、下载与回放
+	querier     *capability.Querier        // 能力集查询
+	supervisor  *Supervisor                // 会话保活监督器
+}
+
+// Login 登录设备并返回设备句柄（使用V40接口）
+// 参数：
+//   - cred: 登录凭据
+//
+// 返回值：
+//   - *Device: 设备句柄，使用完毕后需调用 Close
+//   - error: 错误信息，成功时为nil
+func Login(cred *Credentials) (*Device, error) {
+	session, err := LoginV40(cred)
+	if err != nil {
+		return nil, err
+	}
+	return newDevice(sdk.Default(), cred, session), nil
+}
+
+// newDevice 根据已建立的会话创建设备句柄
+func newDevice(backend sdk.Backend, cred *Credentials, session *SessionInfo) *Device {
+	login := core.NewLoginHandle(session.LoginID)
+	login.SetDevice(cred.addr())
+	return &Device{
+		backend:     backend,
+		cred:        *cred,
+		session:     session,
+		login:       login,
+		controllers: make(map[int]*ptz.Controller),
+		presets:     make(map[int]*ptz.PresetManager),
+		cruises:     make(map[int]*ptz.CruiseManager),
+		tracks:      make(map[int]*ptz.TrackManager),
+	}
+}
+
+// SetLogger 设置该设备的日志记录器
+// 设备的所有控制器（PTZ、报警、预览、回放等）及会话保活都使用此记录器
+// 参数：
+//   - logger: 日志记录器，nil表示使用全局日志记录器（core.SetLogger）
+func (d *Device) SetLogger(logger *slog.Logger) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.logger = logger
+	d.login.SetLogger(logger)
+}
+
+// log 获取设备日志记录器，日志附带设备地址
+func (d *Device) log() *slog.Logger {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.loggerLocked()
+}
+
+// loggerLocked 获取设备日志记录器，调用方需持有 d.mu
+func (d *Device) loggerLocked() *slog.Logger {
+	logger := d.logger
+	if logger == nil {
+		logger = core.Logger()
+	}
+	return logger.With(slog.String("device", d.cred.addr()))
+}
+
+// GetLoginID 获取登录ID
+// 设备关闭后返回 -1
+func (d *Device) GetLoginID() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return -1
+	}
+	return d.login.ID()
+}
+
+// Session 获取会话信息
+func (d *Device) Session() SessionInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return *d.session
+}
+
+// Credentials 获取登录凭据
+func (d *Device) Credentials() Credentials {
+	return d.cred
+}
+
+// Info 获取登录时设备返回的设备信息
+// 重新登录后返回新会话的设备信息
+func (d *Device) Info() DeviceInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.session.Device
+}
+
+// ChannelNum 获取通道数量
+func (d *Device) ChannelNum() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.session.ChannelNum
+}
+
+// PTZ 获取指定通道的PTZ控制器
+// 参数：
+//   - channel: 通道号
+func (d *Device) PTZ(channel int) *ptz.Controller {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if c, ok := d.controllers[channel]; ok {
+		return c
+	}
+	c := ptz.NewControllerWithHandle(d.backend, d.login, channel)
+	d.controllers[channel] = c
+	return c
+}
+
+// Presets 获取指定通道的预置点控制器
+// 参数：
+//   - channel: 通道号
+func (d *Device) Presets(channel int) *ptz.PresetManager {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if p, ok := d.presets[channel]; ok {
+		return p
+	}
+	p := ptz.NewPresetManagerWithHandle(d.backend, d.login, channel)
+	d.presets[channel] = p
+	return p
+}
+
+// Cruise 获取指定通道的巡航控制器
+// 参数：
+//   - channel: 通道号
+func (d *Device) Cruise(channel int) *ptz.CruiseManager {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if c, ok := d.cruises[channel]; ok {
+		return c
+	}
+	c := ptz.NewCruiseManagerWithHandle(d.backend, d.login, channel)
+	d.cruises[channel] = c
+	return c
+}
+
+// Track 获取指定通道的轨迹控制器
+// 参数：
+//   - channel: 通道号
+func (d *Device) Track(channel int) *ptz.TrackManager {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if t, ok := d.tracks[channel]; ok {
+		return t
+	}
+	t := ptz.NewTrackManagerWithHandle(d.backend, d.login, channel)
+	d.tracks[channel] = t
+	return t
+}
+
+// Alarms 获取设备的报警监听器
+// 监听器需要调用方自行 Start，Close 时会自动 Stop
+func (d *Device) Alarms() *alarm.AlarmListener {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.alarms == nil {
+		d.alarms = alarm.NewAlarmListenerWithHandle(d.backend, d.login)
+	}
+	return d.alarms
+}
+
+// Snapshot 抓取指定通道的 JPEG 图片
+// 参数：
+//   - channel: 通道号
+//   - quality: 图片质量（snapshot.QualityBest、QualityBetter、QualityNormal）
+//   - resolution: 图片尺寸（snapshot.SizeHD1080 等，snapshot.SizeAuto 表示当前码流分辨率）
+//
+// 返回值：
+//   - []byte: JPEG 图片数据
+//   - error: 错误信息，成功时为nil
+func (d *Device) Snapshot(channel, quality, resolution int) ([]byte, error) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil, errDeviceClosed
+	}
+	if d.capturer == nil {
+		d.capturer = snapshot.NewCapturerWithHandle(d.backend, d.login)
+	}
+	capturer := d.capturer
+	d.mu.Unlock()
+
+	return capturer.Capture(channel, quality, resolution)
+}
+
+// EnableAlarmSnapshots 启用报警联动抓图
+// 之后通过 Alarms().Events / OnEvent 订阅的报警事件都带有报警通道的抓图（Event.Snapshots）
+// 参数：
+//   - quality: 图片质量
+//   - resolution: 图片尺寸
+func (d *Device) EnableAlarmSnapshots(quality, resolution int) {
+	d.Alarms().EnableSnapshots(func(channel int) ([]byte, error) {
+		return d.Snapshot(channel, quality, resolution)
+	})
+}
+
+// Playback 获取设备的录像查找、下载与回放
+// 使用共享登录句柄，重新登录后无需重新获取；Close 时自动停止正在进行的回放
+func (d *Device) Playback() *playback.Manager {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.playback == nil {
+		d.playback = playback.NewManagerWithHandle(d.backend, d.login)
+	}
+	return d.playback
+}
+
+// Abilities 获取设备的能力集查询
+// 使用共享登录句柄，重新登录后无需重新获取
+func (d *Device) Abilities() *capability.Querier {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.querier == nil {
+		d.querier = capability.NewQuerierWithHandle(d.backend, d.login)
+	}
+	return d.querier
+}
+
+// Capabilities 查询指定通道的能力（是否支持云台、3D定位以及支持的智能事件）
+// 参数：
+//   - channel: 通道号
+//
+// 返回值：
+//   - *capability.ChannelCapabilities: 通道能力
+//   - error: 错误信息，成功时为nil
+func (d *Device) Capabilities(channel int) (*capability.ChannelCapabilities, error) {
+	return d.Abilities().Channel(channel)
+}
+
+// Preview 启动指定通道的实时预览
+// 每次调用都建立新的预览；设备会记住正在进行的预览，重新登录后自动恢复，Close 时自动停止
+// 参数：
+//   - channel: 通道号
+//   - opts: 预览参数，nil表示使用 preview.DefaultOptions()
+//
+// 返回值：
+//   - *preview.Stream: 实时预览，不再使用时调用其 Close
+//   - error: 错误信息，成功时为nil
+func (d *Device) Preview(channel int, opts *preview.Options) (*preview.Stream, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil, errDeviceClosed
+	}
+
+	stream := preview.NewStreamWithHandle(d.backend, d.login, channel)
+	if err := stream.StartWithOptions(opts); err != nil {
+		return nil, err
+	}
+
+	// 顺便清理调用方已关闭的预览；重新启动失败的预览保留，下次重新登录时再恢复
+	open := d.streams[:0]
+	for _, s := range d.streams {
+		if !s.IsClosed() {
+			open = append(open, s)
+		}
+	}
+	d.streams = append(open, stream)
+	return stream, nil
+}
+
+// Close 关闭设备
+// 依次停止会话保活、停止实时预览和录像回放、停止报警监听（撤防），最后登出设备；重复调用是安全的
+//
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (d *Device) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	supervisor := d.supervisor
+	d.mu.Unlock()
+
+	// 先停止保活，避免关闭过程中触发重新登录
+	if supervisor != nil {
+		supervisor.Stop()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var errs []error
+
+	// 停止预览和撤防都必须在登出之前进行
+	for _, s := range d.streams {
+		if err := s.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("停止实时预览失败: %w", err))
+		}
+	}
+	d.streams = nil
+
+	if d.playback != nil {
+		if err := d.playback.CloseAll(); err != nil {
+			errs = append(errs, fmt.Errorf("停止录像回放失败: %w", err))
+		}
+	}
+
+	if d.alarms != nil {
+		if err := d.alarms.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("停止报警监听失败: %w", err))
+		}
+	}
+
+	if err := logout(d.backend, d.login.ID(), d.login.Device(), d.loggerLocked()); err != nil {
+		errs = append(errs, err)
+	}
+	d.login.Set(-1)
+
+	d.loggerLocked().Info("设备已关闭")
+	return errors.Join(errs...)
+}
+
+// relogin 重新登录设备
+// 释放旧会话后使用保存的凭据重新登录，成功后更新共享登录句柄，重新布防并恢复实时预览
+//
+// 返回值：
+//   - int: 新的登录ID
+//   - error: 登录失败时的错误；重新布防或恢复预览失败只记录日志，不视为登录失败
+func (d *Device) relogin() (int, error) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return -1, errDeviceClosed
+	}
+	oldID := d.login.ID()
+	logger := d.loggerLocked()
+	d.mu.Unlock()
+
+	// 旧会话通常已失效，登出失败可以忽略
+	if oldID >= 0 {
+		d.backend.Logout(oldID)
+		d.login.Set(-1)
+		core.GetMetrics().AddSessions(-1)
+	}
+
+	session, err := loginV40(d.backend, &d.cred, logger)
+	if err != nil {
+		return -1, err
+	}
+
+	d.mu.Lock()
+	if d.closed {
+		// 登录期间设备已被关闭，释放新会话
+		d.mu.Unlock()
+		d.backend.Logout(session.LoginID)
+		core.GetMetrics().AddSessions(-1)
+		return -1, errDeviceClosed
+	}
+	d.session = session
+	d.login.Set(session.LoginID)
+	alarms := d.alarms
+	streams := append([]*preview.Stream(nil), d.streams...)
+	d.mu.Unlock()
+
+	if alarms != nil {
+		if err := alarms.Rearm(); err != nil {
+			logger.Warn("重新布防失败", "login_id", session.LoginID, "error", err)
+		}
+	}
+	for _, s := range streams {
+		if err := s.Restart(); err != nil {
+			logger.Warn("恢复实时预览失败", "login_id", session.LoginID, "channel", s.Channel(), "error", err)
+		}
+	}
+
+	return session.LoginID, nil
+}
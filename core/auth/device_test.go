@@ -0,0 +1,367 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/alarm"
+	"github.com/samsaralc/hiksdk/core/sdk"
+	"github.com/samsaralc/hiksdk/core/snapshot"
+)
+
+// useFake 将默认后端替换为模拟后端，测试结束后恢复
+func useFake(t *testing.T) *sdk.Fake {
+	t.Helper()
+	fake := sdk.NewFake()
+	prev := sdk.SetDefault(fake)
+	t.Cleanup(func() {
+		Cleanup()
+		sdk.SetDefault(prev)
+	})
+	return fake
+}
+
+// TestDeviceLifecycle 设备句柄分发控制器，并按正确顺序撤防、登出
+func TestDeviceLifecycle(t *testing.T) {
+	fake := useFake(t)
+	fake.DeviceInfo.ChanNum = 4
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if dev.ChannelNum() != 4 {
+		t.Errorf("通道数应为4，实际: %d", dev.ChannelNum())
+	}
+	if dev.Credentials().IP != "192.168.1.64" {
+		t.Errorf("未记住登录凭据: %+v", dev.Credentials())
+	}
+	if dev.PTZ(1) != dev.PTZ(1) || dev.PTZ(1) == dev.PTZ(2) {
+		t.Error("同一通道应返回同一控制器，不同通道应返回不同控制器")
+	}
+	if err := dev.Presets(1).GotoPreset(3); err != nil {
+		t.Errorf("转到预置点失败: %v", err)
+	}
+	if err := dev.Alarms().Start(); err != nil {
+		t.Fatalf("启动报警监听失败: %v", err)
+	}
+
+	fake.ResetCalls()
+	if err := dev.Close(); err != nil {
+		t.Fatalf("关闭设备失败: %v", err)
+	}
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0].Method != "CloseAlarmChanV30" || calls[1].Method != "Logout" {
+		t.Errorf("关闭顺序应为撤防再登出，实际: %v", calls)
+	}
+	if dev.GetLoginID() != -1 {
+		t.Errorf("关闭后登录ID应为-1，实际: %d", dev.GetLoginID())
+	}
+	if err := dev.Close(); err != nil {
+		t.Errorf("重复关闭不应报错: %v", err)
+	}
+}
+
+// TestDevicePreview 预览在重新登录后恢复，关闭设备时先停止预览再登出
+func TestDevicePreview(t *testing.T) {
+	fake := useFake(t)
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	stream, err := dev.Preview(1, nil)
+	if err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+
+	fake.DropSession(dev.GetLoginID())
+	newID, err := dev.relogin()
+	if err != nil {
+		t.Fatalf("重新登录失败: %v", err)
+	}
+	calls := fake.CallsTo("RealPlayV40")
+	if len(calls) != 2 || calls[1].Args[0] != newID || !stream.IsRunning() {
+		t.Errorf("重新登录后应恢复预览: %v", calls)
+	}
+
+	fake.ResetCalls()
+	if err := dev.Close(); err != nil {
+		t.Fatalf("关闭设备失败: %v", err)
+	}
+	calls = fake.Calls()
+	if len(calls) != 2 || calls[0].Method != "StopRealPlay" || calls[1].Method != "Logout" {
+		t.Errorf("关闭顺序应为停止预览再登出，实际: %v", calls)
+	}
+	if _, err := dev.Preview(1, nil); err == nil {
+		t.Error("设备关闭后不应再启动预览")
+	}
+}
+
+// TestDevicePreviewRestartFailure 恢复失败的预览不会被清理，下次重新登录时再恢复
+func TestDevicePreviewRestartFailure(t *testing.T) {
+	fake := useFake(t)
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	defer dev.Close()
+	stream, err := dev.Preview(1, nil)
+	if err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+
+	fake.FailOnce("RealPlayV40", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+	if _, err := dev.relogin(); err != nil {
+		t.Fatalf("重新登录失败: %v", err)
+	}
+	if stream.IsRunning() || stream.IsClosed() {
+		t.Fatal("恢复失败的预览应处于未运行但未关闭的状态")
+	}
+
+	// 启动其他预览时不应清理恢复失败的预览
+	if _, err := dev.Preview(2, nil); err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+	newID, err := dev.relogin()
+	if err != nil {
+		t.Fatalf("重新登录失败: %v", err)
+	}
+	calls := fake.CallsTo("RealPlayV40")
+	if !stream.IsRunning() || calls[len(calls)-2].Args[0] != newID {
+		t.Errorf("再次重新登录后应恢复预览: %v", calls)
+	}
+}
+
+// TestDeviceSnapshot 抓图与报警联动抓图
+func TestDeviceSnapshot(t *testing.T) {
+	fake := useFake(t)
+	picture := []byte{0xFF, 0xD8, 0xFF, 0xD9}
+	fake.SetPicture(1, picture)
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	data, err := dev.Snapshot(1, snapshot.QualityBest, snapshot.SizeAuto)
+	if err != nil || len(data) != len(picture) {
+		t.Fatalf("抓图失败: %v", err)
+	}
+
+	dev.EnableAlarmSnapshots(snapshot.QualityNormal, snapshot.SizeAuto)
+	events := dev.Alarms().Events(nil)
+	if err := dev.Alarms().Start(); err != nil {
+		t.Fatalf("启动报警监听失败: %v", err)
+	}
+	info := make([]byte, 268)
+	info[0] = byte(alarm.AlarmTypeMotion) // dwAlarmType（小端）
+	info[168] = 1                         // byChannel[0]：通道1
+	fake.Emit(alarm.COMM_ALARM_V30, &sdk.Alarmer{UserID: dev.GetLoginID()}, info)
+	select {
+	case e := <-events:
+		if len(e.Snapshots) != 1 || e.Snapshots[0].Err != nil || len(e.Snapshots[0].Data) != len(picture) {
+			t.Errorf("报警事件应带有通道1的抓图: %+v", e.Snapshots)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("未收到报警事件")
+	}
+
+	dev.Close()
+	if _, err := dev.Snapshot(1, snapshot.QualityBest, snapshot.SizeAuto); err == nil {
+		t.Error("设备关闭后不应再抓图")
+	}
+}
+
+// TestDeviceInfo 登录时返回的设备信息转换为 DeviceInfo
+func TestDeviceInfo(t *testing.T) {
+	fake := useFake(t)
+	fake.DeviceInfo = sdk.DeviceInfo{
+		SerialNumber:    "DS-7608NI0120240501",
+		AlarmInPortNum:  8,
+		AlarmOutPortNum: 4,
+		DiskNum:         2,
+		DVRType:         96,
+		IPChanNum:       8,
+		StartDChan:      33,
+		StartChan:       1,
+		SupportLock:     true,
+		RetryLoginTime:  5,
+		PasswordLevel:   1,
+		SurplusLockTime: 30,
+	}
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "12345"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	defer dev.Close()
+
+	info := dev.Info()
+	if info.SerialNumber != "DS-7608NI0120240501" || info.AlarmInPorts != 8 || info.AlarmOutPorts != 4 || info.Disks != 2 || info.DeviceType != 96 {
+		t.Errorf("设备信息错误: %+v", info)
+	}
+	if info.IPChannels != 8 || info.StartIPChannel != 33 || info.StartChannel != 1 {
+		t.Errorf("通道信息错误: %+v", info)
+	}
+	if info.PasswordLevel != PasswordDefault || !info.PasswordLevel.Weak() || !info.SupportLock || info.RetryLoginTimes != 5 || info.LockRemaining != 30*time.Second {
+		t.Errorf("密码与锁定信息错误: %+v", info)
+	}
+	if dev.Session().Device != info {
+		t.Error("会话信息中应包含设备信息")
+	}
+}
+
+// TestLoginLocked 登录失败时错误中附带设备返回的剩余尝试次数和锁定时间
+func TestLoginLocked(t *testing.T) {
+	fake := useFake(t)
+	fake.DeviceInfo.SupportLock = true
+	fake.DeviceInfo.RetryLoginTime = 2
+	fake.FailOnce("LoginV40", sdk.NET_DVR_PASSWORD_ERROR)
+
+	cred := &Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "wrong"}
+	_, err := Login(cred)
+	var loginErr *LoginError
+	if !errors.As(err, &loginErr) || !loginErr.SupportLock || loginErr.RetryLoginTimes != 2 || loginErr.LockRemaining != 0 {
+		t.Fatalf("密码错误时应返回剩余尝试次数: %#v", err)
+	}
+	if !errors.Is(err, core.ErrPasswordWrong) {
+		t.Errorf("应能判断为密码错误: %v", err)
+	}
+
+	fake.DeviceInfo.RetryLoginTime = 0
+	fake.DeviceInfo.SurplusLockTime = 1800
+	fake.FailOnce("LoginV40", sdk.NET_DVR_USER_LOCKED)
+	_, err = Login(cred)
+	var hkErr *core.HKError
+	if !errors.As(err, &loginErr) || loginErr.LockRemaining != 30*time.Minute || !errors.As(err, &hkErr) || hkErr.Code != sdk.NET_DVR_USER_LOCKED {
+		t.Fatalf("用户被锁定时应返回剩余锁定时间: %#v", err)
+	}
+	if !errors.Is(err, core.ErrUserLocked) {
+		t.Errorf("应能判断为用户被锁定: %v", err)
+	}
+}
+
+// TestDeviceLogger 默认不输出日志；设备日志记录器优先于全局日志记录器，并附带结构化字段
+func TestDeviceLogger(t *testing.T) {
+	useFake(t)
+	var global, device bytes.Buffer
+	core.SetLogger(slog.New(slog.NewJSONHandler(&global, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { core.SetLogger(nil) })
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if !strings.Contains(global.String(), `"msg":"登录成功","device":"192.168.1.64:8000","login_id":`) {
+		t.Errorf("登录日志应记录到全局日志记录器: %s", global.String())
+	}
+
+	global.Reset()
+	dev.SetLogger(slog.New(slog.NewJSONHandler(&device, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	if err := dev.Presets(2).GotoPreset(3); err != nil {
+		t.Fatalf("转到预置点失败: %v", err)
+	}
+	if err := dev.Close(); err != nil {
+		t.Fatalf("关闭设备失败: %v", err)
+	}
+	if global.Len() != 0 {
+		t.Errorf("设置设备日志记录器后不应写入全局日志: %s", global.String())
+	}
+	for _, want := range []string{`"msg":"转到预置点","login_id":`, `"channel":2,"preset":3`, `"msg":"设备已关闭","device":"192.168.1.64:8000"`} {
+		if !strings.Contains(device.String(), want) {
+			t.Errorf("设备日志缺少 %s: %s", want, device.String())
+		}
+	}
+}
+
+// recordingMetrics 记录指标回调的测试实现
+type recordingMetrics struct {
+	mu       sync.Mutex
+	calls    []string // operation device code
+	sessions int
+	armed    int
+	alarms   []string // device type
+}
+
+func (m *recordingMetrics) ObserveCall(operation, device string, code int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, fmt.Sprintf("%s %s %d", operation, device, code))
+}
+
+func (m *recordingMetrics) AddSessions(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions += delta
+}
+
+func (m *recordingMetrics) AddArmedListeners(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.armed += delta
+}
+
+func (m *recordingMetrics) ObserveAlarm(device, eventType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alarms = append(m.alarms, device+" "+eventType)
+}
+
+// TestDeviceMetrics SDK调用按操作、设备和错误码记录，会话数和布防数随登录、布防变化
+func TestDeviceMetrics(t *testing.T) {
+	fake := useFake(t)
+	m := &recordingMetrics{}
+	core.SetMetrics(m)
+	t.Cleanup(func() { core.SetMetrics(nil) })
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if err := dev.Presets(1).GotoPreset(3); err != nil {
+		t.Fatalf("转到预置点失败: %v", err)
+	}
+	fake.FailOnce("PTZControlWithSpeedOther", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+	if err := dev.PTZ(1).StartUp(3); err == nil {
+		t.Fatal("云台控制应失败")
+	}
+	if err := dev.Alarms().Start(); err != nil {
+		t.Fatalf("布防失败: %v", err)
+	}
+	if m.sessions != 1 || m.armed != 1 {
+		t.Errorf("会话数=%d 布防数=%d，应为 1 1", m.sessions, m.armed)
+	}
+	fake.Emit(0x9999, &sdk.Alarmer{UserID: dev.GetLoginID()}, nil)
+
+	if err := dev.Close(); err != nil {
+		t.Fatalf("关闭设备失败: %v", err)
+	}
+	if m.sessions != 0 || m.armed != 0 {
+		t.Errorf("关闭后会话数=%d 布防数=%d，应为 0 0", m.sessions, m.armed)
+	}
+
+	const addr = "192.168.1.64:8000"
+	want := []string{
+		"login " + addr + " 0",
+		"preset " + addr + " 0",
+		fmt.Sprintf("ptz_control %s %d", addr, sdk.NET_DVR_NETWORK_RECV_TIMEOUT),
+		"alarm_arm " + addr + " 0",
+		"alarm_disarm " + addr + " 0",
+		"logout " + addr + " 0",
+	}
+	if !slices.Equal(m.calls, want) {
+		t.Errorf("调用指标 = %q，应为 %q", m.calls, want)
+	}
+	if !slices.Equal(m.alarms, []string{addr + " 0x9999"}) {
+		t.Errorf("报警指标 = %q", m.alarms)
+	}
+}
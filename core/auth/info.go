@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// PasswordLevel admin密码安全等级（NET_DVR_DEVICEINFO_V40.byPasswordLevel）
+type PasswordLevel int
+
+const (
+	PasswordInvalid PasswordLevel = 0 // 无效（设备未返回或V30登录）
+	PasswordDefault PasswordLevel = 1 // 默认密码
+	PasswordValid   PasswordLevel = 2 // 有效密码
+	PasswordRisky   PasswordLevel = 3 // 风险较高的密码
+)
+
+// String 返回密码安全等级的中文描述
+func (l PasswordLevel) String() string {
+	switch l {
+	case PasswordInvalid:
+		return "无效"
+	case PasswordDefault:
+		return "默认密码"
+	case PasswordValid:
+		return "有效密码"
+	case PasswordRisky:
+		return "风险密码"
+	default:
+		return fmt.Sprintf("未知密码等级(%d)", int(l))
+	}
+}
+
+// Weak 是否为默认密码或风险较高的密码（应提示用户修改密码）
+func (l PasswordLevel) Weak() bool {
+	return l == PasswordDefault || l == PasswordRisky
+}
+
+// DeviceInfo 登录时设备返回的设备信息（NET_DVR_DEVICEINFO_V40）
+// 使用V30接口登录时，密码等级和锁定相关字段为零值
+type DeviceInfo struct {
+	SerialNumber   string // 设备序列号
+	DeviceType     int    // 设备类型（byDVRType）
+	DeviceModel    int    // 设备型号（wDevType）
+	AlarmInPorts   int    // 报警输入个数
+	AlarmOutPorts  int    // 报警输出个数
+	Disks          int    // 硬盘个数
+	AnalogChannels int    // 模拟通道个数
+	StartChannel   int    // 起始模拟通道号
+	IPChannels     int    // 数字（IP）通道个数
+	StartIPChannel int    // 起始数字通道号
+	AudioChannels  int    // 语音对讲通道个数
+	ZeroChannels   int    // 零通道个数
+
+	PasswordLevel   PasswordLevel // admin密码安全等级
+	SupportLock     bool          // 是否支持登录失败锁定
+	RetryLoginTimes int           // 剩余可尝试登录的次数（登录失败时见 LoginError）
+	LockRemaining   time.Duration // 剩余锁定时间（登录失败时见 LoginError）
+}
+
+// newDeviceInfo 将SDK设备信息转换为 DeviceInfo
+func newDeviceInfo(info *sdk.DeviceInfo) DeviceInfo {
+	return DeviceInfo{
+		SerialNumber:    info.SerialNumber,
+		DeviceType:      info.DVRType,
+		DeviceModel:     info.DevType,
+		AlarmInPorts:    info.AlarmInPortNum,
+		AlarmOutPorts:   info.AlarmOutPortNum,
+		Disks:           info.DiskNum,
+		AnalogChannels:  info.ChanNum,
+		StartChannel:    info.StartChan,
+		IPChannels:      info.IPChanNum,
+		StartIPChannel:  info.StartDChan,
+		AudioChannels:   info.AudioChanNum,
+		ZeroChannels:    info.ZeroChanNum,
+		PasswordLevel:   PasswordLevel(info.PasswordLevel),
+		SupportLock:     info.SupportLock,
+		RetryLoginTimes: info.RetryLoginTime,
+		LockRemaining:   time.Duration(info.SurplusLockTime) * time.Second,
+	}
+}
+
+// LoginError V40登录失败的错误，附带设备返回的锁定信息
+// 包装 *core.HKError，可以继续使用 errors.Is（如 core.ErrPasswordWrong、core.ErrUserLocked）
+// 和 errors.As 获取错误码
+type LoginError struct {
+	*core.HKError
+	SupportLock     bool          // 设备是否支持登录失败锁定
+	RetryLoginTimes int           // 剩余可尝试登录的次数
+	LockRemaining   time.Duration // 剩余锁定时间（用户被锁定时有效）
+}
+
+// newLoginError 使用SDK登录失败时返回的设备信息创建 LoginError
+func newLoginError(err *core.HKError, info *sdk.DeviceInfo) *LoginError {
+	return &LoginError{
+		HKError:         err,
+		SupportLock:     info.SupportLock,
+		RetryLoginTimes: info.RetryLoginTime,
+		LockRemaining:   time.Duration(info.SurplusLockTime) * time.Second,
+	}
+}
+
+// Unwrap 返回被包装的 *core.HKError
+func (e *LoginError) Unwrap() error {
+	return e.HKError
+}
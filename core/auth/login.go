@@ -1,63 +1,189 @@
 package auth
 
-/*
-#cgo CFLAGS: -I../../include
-#cgo CFLAGS: -I..
-
-// Linux 平台的链接配置（需要在系统 LD_LIBRARY_PATH 中配置海康 SDK 库路径）
-#cgo linux LDFLAGS: -lhcnetsdk -lhpr -lHCCore
-
-// Windows 平台的链接配置（需要在系统 PATH 中配置海康 SDK 库路径）
-#cgo windows LDFLAGS: -lHCNetSDK -lHCCore
-
-#include <stdio.h>
-#include <stdlib.h>
-#include "../hiksdk_wrapper.h"
-*/
-import "C"
 import (
 	"errors"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
 	"sync"
-	"unsafe"
+	"time"
 
 	"github.com/samsaralc/hiksdk/core"
-	"github.com/samsaralc/hiksdk/core/utils"
+	"github.com/samsaralc/hiksdk/core/alarm"
+	"github.com/samsaralc/hiksdk/core/sdk"
 )
 
 var (
 	// sdkMutex 保护SDK初始化和清理操作的互斥锁
 	sdkMutex sync.Mutex
-	// sdkInitialized 标记SDK是否已初始化
-	sdkInitialized bool
+	// sdkInitialized 已初始化的SDK后端，nil表示未初始化
+	// 记录后端而非布尔值，以便测试中替换默认后端后能重新初始化
+	sdkInitialized sdk.Backend
+	// sdkLibraryPaths 初始化时设置的组件库路径，SDK初始化后无法修改
+	sdkLibraryPaths [3]string
 )
 
+// LogOptions SDK日志配置（NET_DVR_SetLogToFile）
+// Forward 为 true 时，SDK写入日志目录的日志按级别转发到Go日志记录器（core.SetLogger）；
+// 此时 Dir 为空表示使用临时目录（自动删除超量日志，Cleanup 时删除目录），适合没有固定可写日志目录的容器部署
+type LogOptions struct {
+	Level      int    // 日志级别（0-关闭, 1-错误, 2-警告, 3-信息, 4-调试）
+	Dir        string // 日志存储目录（空字符串表示默认）
+	AutoDelete bool   // 是否自动删除超量日志
+
+	Forward         bool          // 是否将SDK日志转发到Go日志记录器
+	ForwardInterval time.Duration // 读取日志文件的间隔，0 表示 DefaultLogForwardInterval
+}
+
+// InitOptions SDK初始化参数
+// 零值字段使用 DefaultInitOptions 中的默认值
+type InitOptions struct {
+	ConnectTimeout    time.Duration // 连接超时（SDK有效范围300毫秒-75秒），0 表示默认值（2秒）
+	ConnectRetries    int           // 连接尝试次数，0 表示默认值（5次）
+	ReconnectInterval time.Duration // 断线重连间隔（不小于1秒），0 表示默认值（10秒）
+	DisableReconnect  bool          // 是否关闭SDK的断线重连
+	RecvTimeout       time.Duration // 接收数据超时，0 表示使用SDK默认值（5秒）
+
+	// 以下路径只能在SDK初始化之前设置（NET_DVR_SetSDKInitCfg），空字符串表示使用默认查找路径
+	LibraryPath   string // 组件库（HCNetSDKCom）所在目录
+	LibCryptoPath string // libcrypto（Windows 为 libeay32.dll）完整路径
+	LibSSLPath    string // libssl（Windows 为 ssleay32.dll）完整路径
+
+	Log *LogOptions // SDK日志配置，nil表示不配置
+}
+
+// DefaultInitOptions 返回默认初始化参数（首次登录时自动初始化使用的参数）
+// 连接超时2秒、尝试5次，断线重连间隔10秒
+func DefaultInitOptions() *InitOptions {
+	return &InitOptions{
+		ConnectTimeout:    2 * time.Second,
+		ConnectRetries:    5,
+		ReconnectInterval: 10 * time.Second,
+	}
+}
+
+// withDefaults 返回以默认值填充零值字段后的副本
+func (o *InitOptions) withDefaults() *InitOptions {
+	filled := *o
+	defaults := DefaultInitOptions()
+	if filled.ConnectTimeout == 0 {
+		filled.ConnectTimeout = defaults.ConnectTimeout
+	}
+	if filled.ConnectRetries == 0 {
+		filled.ConnectRetries = defaults.ConnectRetries
+	}
+	if filled.ReconnectInterval == 0 {
+		filled.ReconnectInterval = defaults.ReconnectInterval
+	}
+	return &filled
+}
+
+// validate 检查初始化参数（零值字段已填充默认值）
+func (o *InitOptions) validate() error {
+	if o.ConnectTimeout < 300*time.Millisecond || o.ConnectTimeout > 75*time.Second {
+		return fmt.Errorf("无效的连接超时: %v（有效范围300ms-75s）", o.ConnectTimeout)
+	}
+	if o.ConnectRetries < 1 {
+		return fmt.Errorf("无效的连接尝试次数: %d", o.ConnectRetries)
+	}
+	if !o.DisableReconnect && o.ReconnectInterval < time.Second {
+		return fmt.Errorf("无效的重连间隔: %v", o.ReconnectInterval)
+	}
+	if o.RecvTimeout < 0 {
+		return fmt.Errorf("无效的接收超时: %v", o.RecvTimeout)
+	}
+	if l := o.Log; l != nil && l.Forward {
+		if l.Level <= 0 {
+			return errors.New("转发SDK日志需要设置日志级别")
+		}
+		if l.ForwardInterval < 0 {
+			return fmt.Errorf("无效的日志转发间隔: %v", l.ForwardInterval)
+		}
+	}
+	return nil
+}
+
+// Init 使用指定参数初始化SDK
+// 不调用 Init 时，首次登录会以 DefaultInitOptions 自动初始化。
+// SDK已初始化时重新应用超时、重连和日志参数；组件库路径只能在初始化之前设置，与已设置的路径不同时返回错误
+// 参数：
+//   - opts: 初始化参数，nil表示使用 DefaultInitOptions()，零值字段使用默认值
+//
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func Init(opts *InitOptions) error {
+	if opts == nil {
+		opts = DefaultInitOptions()
+	}
+	opts = opts.withDefaults()
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	sdkMutex.Lock()
+	defer sdkMutex.Unlock()
+	return initLocked(opts)
+}
+
 // initSDK 初始化SDK（私有方法）
-// 登录前会自动调用
+// 登录前会自动调用；已初始化（包括通过 Init 初始化）时直接返回
 func initSDK() error {
 	sdkMutex.Lock()
 	defer sdkMutex.Unlock()
 
 	// 如果已经初始化，直接返回
-	if sdkInitialized {
+	if sdkInitialized == sdk.Default() {
 		return nil
 	}
+	return initLocked(DefaultInitOptions())
+}
 
-	// 执行初始化
-	result := C.NET_DVR_Init()
-	if result != C.TRUE {
-		return fmt.Errorf("SDK初始化失败")
+// initLocked 初始化SDK并应用参数，调用方需持有 sdkMutex
+func initLocked(opts *InitOptions) error {
+	backend := sdk.Default()
+	paths := [3]string{opts.LibraryPath, opts.LibCryptoPath, opts.LibSSLPath}
+
+	if sdkInitialized != backend {
+		// 组件库路径必须在 NET_DVR_Init 之前设置
+		cfgTypes := [3]int{sdk.NET_SDK_INIT_CFG_SDK_PATH, sdk.NET_SDK_INIT_CFG_LIBEAY_PATH, sdk.NET_SDK_INIT_CFG_SSLEAY_PATH}
+		for i, path := range paths {
+			if path != "" && !backend.SetSDKInitCfg(cfgTypes[i], path) {
+				return core.NewHKErrorFrom(backend, fmt.Sprintf("设置SDK组件库路径[%s]", path))
+			}
+		}
+
+		// 执行初始化
+		if !backend.Init() {
+			return fmt.Errorf("SDK初始化失败")
+		}
+		sdkInitialized = backend
+		sdkLibraryPaths = paths
+		core.Logger().Info("海康SDK初始化成功")
+	} else if paths != sdkLibraryPaths && paths != [3]string{} {
+		return errors.New("SDK已初始化，无法修改组件库路径（需先调用 Cleanup）")
 	}
 
 	// 设置连接超时参数
-	C.NET_DVR_SetConnectTime(2000, 5) // 连接超时2秒，重试5次
+	if !backend.SetConnectTime(uint32(opts.ConnectTimeout.Milliseconds()), uint32(opts.ConnectRetries)) {
+		return core.NewHKErrorFrom(backend, "设置连接超时")
+	}
 	// 设置重连参数
-	C.NET_DVR_SetReconnect(10000, 1) // 重连间隔10秒，启用重连
+	if !backend.SetReconnect(uint32(opts.ReconnectInterval.Milliseconds()), !opts.DisableReconnect) {
+		return core.NewHKErrorFrom(backend, "设置断线重连")
+	}
+	if opts.RecvTimeout > 0 && !backend.SetRecvTimeOut(uint32(opts.RecvTimeout.Milliseconds())) {
+		return core.NewHKErrorFrom(backend, "设置接收超时")
+	}
+	if l := opts.Log; l != nil {
+		if err := applyLogOptions(backend, l); err != nil {
+			return err
+		}
+	}
 
-	sdkInitialized = true
-	log.Println("✓ 海康SDK初始化成功")
+	core.Logger().Info("SDK参数已设置", "connect_timeout", opts.ConnectTimeout,
+		"connect_retries", opts.ConnectRetries, "reconnect", !opts.DisableReconnect)
 	return nil
 }
 
@@ -67,22 +193,51 @@ func Cleanup() error {
 	sdkMutex.Lock()
 	defer sdkMutex.Unlock()
 
-	if !sdkInitialized {
+	if sdkInitialized == nil {
 		return nil // 已经清理
 	}
 
 	// 执行清理
-	result := C.NET_DVR_Cleanup()
-	if result != C.TRUE {
+	if !sdkInitialized.Cleanup() {
 		return fmt.Errorf("SDK清理失败")
 	}
+	// SDK清理后不再写入日志，转发剩余的日志后停止
+	stopLogForwarderLocked()
+
+	sdkInitialized = nil
+	sdkLibraryPaths = [3]string{}
+	core.Logger().Info("海康SDK已清理")
+	return nil
+}
+
+// applyLogOptions 设置SDK日志，并按需启动或停止日志转发，调用方需持有 sdkMutex
+func applyLogOptions(backend sdk.Backend, l *LogOptions) error {
+	dir, temp := l.Dir, false
+	if l.Forward && dir == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "hcnetsdk-log-"); err != nil {
+			return fmt.Errorf("创建SDK日志临时目录失败: %w", err)
+		}
+		temp = true
+	}
 
-	sdkInitialized = false
-	log.Println("✓ 海康SDK已清理")
+	if !backend.SetLogToFile(l.Level, dir, l.AutoDelete || temp) {
+		if temp {
+			os.RemoveAll(dir)
+		}
+		return core.NewHKErrorFrom(backend, "设置日志配置")
+	}
+
+	stopLogForwarderLocked()
+	if l.Forward {
+		sdkLogForwarder = startLogForwarder(dir, temp, l.ForwardInterval)
+		core.Logger().Info("SDK日志转发已启动", "dir", dir, "level", l.Level)
+	}
 	return nil
 }
 
 // SetLogConfig 配置SDK日志
+// 只写入日志目录，不转发（会停止正在进行的转发）；需要转发到Go日志记录器时使用 Init 的 LogOptions.Forward
 // 参数：
 //   - level: 日志级别（0-关闭, 1-错误, 2-警告, 3-信息, 4-调试）
 //   - logDir: 日志存储目录（空字符串表示默认）
@@ -96,26 +251,37 @@ func SetLogConfig(level int, logDir string, autoDelete bool) error {
 		return err
 	}
 
-	cLogDir := C.CString(logDir)
-	defer C.free(unsafe.Pointer(cLogDir))
-
-	deleteFlag := C.BOOL(0)
-	if autoDelete {
-		deleteFlag = C.BOOL(1)
+	sdkMutex.Lock()
+	defer sdkMutex.Unlock()
+	if !sdk.Default().SetLogToFile(level, logDir, autoDelete) {
+		return fmt.Errorf("设置日志配置失败")
 	}
+	stopLogForwarderLocked()
 
-	result := C.NET_DVR_SetLogToFile(
-		C.DWORD(level),
-		cLogDir,
-		deleteFlag,
-	)
+	core.Logger().Info("SDK日志已配置", "level", level, "dir", logDir)
+	return nil
+}
 
-	if result != C.TRUE {
-		return fmt.Errorf("设置日志配置失败")
+// Listen 启动报警监听服务（报警主机模式）
+// 在本地IP/端口上接收设备主动上传的报警，无需登录设备；使用完毕后需调用 Stop
+// 参数：
+//   - localIP: 本地监听IP，空字符串表示监听所有本地地址
+//   - localPort: 本地监听端口（与设备“报警主机”配置中的端口一致）
+//
+// 返回值：
+//   - *alarm.ListenServer: 已启动的报警监听服务
+//   - error: 错误信息，成功时为nil
+func Listen(localIP string, localPort int) (*alarm.ListenServer, error) {
+	// 确保SDK已初始化
+	if err := initSDK(); err != nil {
+		return nil, err
 	}
 
-	log.Printf("✓ SDK日志已配置 - 级别: %d, 目录: %s", level, logDir)
-	return nil
+	server := alarm.NewListenServerWithBackend(sdk.Default(), localIP, localPort)
+	if err := server.Start(); err != nil {
+		return nil, err
+	}
+	return server, nil
 }
 
 // ==================== 认证功能 ====================
@@ -128,11 +294,17 @@ type Credentials struct {
 	Password string // 密码
 }
 
+// addr 设备地址（IP:端口），用于日志和指标的 device 标签
+func (c *Credentials) addr() string {
+	return net.JoinHostPort(c.IP, strconv.Itoa(c.Port))
+}
+
 // SessionInfo 会话信息
 type SessionInfo struct {
-	LoginID      int    // 登录ID
-	SerialNumber string // 设备序列号
-	ChannelNum   int    // 通道数量
+	LoginID      int        // 登录ID
+	SerialNumber string     // 设备序列号
+	ChannelNum   int        // 通道数量
+	Device       DeviceInfo // 登录时设备返回的完整设备信息
 }
 
 // LoginV40 使用V40接口登录设备（推荐）
@@ -141,47 +313,47 @@ type SessionInfo struct {
 //
 // 返回值：
 //   - *SessionInfo: 会话信息（包含loginID等）
-//   - error: 错误信息，成功时为nil
+//   - error: 错误信息，成功时为nil；登录失败时为 *LoginError，附带剩余尝试次数和锁定时间
 func LoginV40(cred *Credentials) (*SessionInfo, error) {
 	// 确保SDK已初始化（登录前必须调用）
 	if err := initSDK(); err != nil {
 		return nil, err
 	}
 
-	var deviceInfoV40 C.NET_DVR_DEVICEINFO_V40
-	var userLoginInfo C.NET_DVR_USER_LOGIN_INFO
+	return loginV40(sdk.Default(), cred, core.Logger())
+}
 
+// loginV40 使用指定后端以V40接口登录设备，登录结果记录到 logger
+// 调用前SDK必须已初始化
+func loginV40(backend sdk.Backend, cred *Credentials, logger *slog.Logger) (*SessionInfo, error) {
 	// 设置登录参数
-	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sDeviceAddress[0]), cred.IP, len(userLoginInfo.sDeviceAddress))
-	userLoginInfo.wPort = C.WORD(cred.Port)
-	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sUserName[0]), cred.Username, len(userLoginInfo.sUserName))
-	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sPassword[0]), cred.Password, len(userLoginInfo.sPassword))
-
-	// 设置为同步登录模式（0=同步，1=异步）
-	userLoginInfo.byUseAsynLogin = 0
-
-	// 调用NET_DVR_Login_V40函数
-	loginID := int(C.NET_DVR_Login_V40(&userLoginInfo, (*C.NET_DVR_DEVICEINFO_V40)(unsafe.Pointer(&deviceInfoV40))))
-
-	// 提取设备序列号
-	serialNumberBytes := make([]byte, len(deviceInfoV40.struDeviceV30.sSerialNumber))
-	for i := range deviceInfoV40.struDeviceV30.sSerialNumber {
-		serialNumberBytes[i] = byte(deviceInfoV40.struDeviceV30.sSerialNumber[i])
+	loginInfo := &sdk.LoginInfo{
+		DeviceAddress: cred.IP,
+		Port:          cred.Port,
+		Username:      cred.Username,
+		Password:      cred.Password,
 	}
-	serialNumber := strings.Trim(string(serialNumberBytes), "\x00")
 
+	// 调用NET_DVR_Login_V40函数（同步登录）
+	var deviceInfo sdk.DeviceInfo
+	began := time.Now()
+	loginID := backend.LoginV40(loginInfo, &deviceInfo)
 	if loginID < 0 {
-		return nil, core.NewHKError("登录设备(V40)")
+		err := core.NewHKErrorFrom(backend, "登录设备(V40)")
+		core.ObserveCall(core.OpLogin, cred.addr(), began, err)
+		return nil, newLoginError(err, &deviceInfo)
 	}
+	core.ObserveCall(core.OpLogin, cred.addr(), began, nil)
+	core.GetMetrics().AddSessions(1)
 
 	session := &SessionInfo{
 		LoginID:      loginID,
-		SerialNumber: serialNumber,
-		ChannelNum:   int(deviceInfoV40.struDeviceV30.byChanNum),
+		SerialNumber: deviceInfo.SerialNumber,
+		ChannelNum:   deviceInfo.ChanNum,
+		Device:       newDeviceInfo(&deviceInfo),
 	}
 
-	log.Printf("✓ 登录成功(V40) - 用户ID: %d, 设备序列号: %s, 通道数: %d",
-		loginID, serialNumber, session.ChannelNum)
+	logLogin(logger, "V40", cred, session, time.Since(began))
 	return session, nil
 }
 
@@ -198,49 +370,40 @@ func LoginV30(cred *Credentials) (*SessionInfo, error) {
 		return nil, err
 	}
 
-	var deviceInfoV30 C.NET_DVR_DEVICEINFO_V30
-
-	// 转换参数
-	ip := C.CString(cred.IP)
-	usr := C.CString(cred.Username)
-	passwd := C.CString(cred.Password)
-	defer func() {
-		C.free(unsafe.Pointer(ip))
-		C.free(unsafe.Pointer(usr))
-		C.free(unsafe.Pointer(passwd))
-	}()
+	backend := sdk.Default()
 
 	// 调用NET_DVR_Login_V30函数
-	loginID := int(C.NET_DVR_Login_V30(
-		ip,
-		C.WORD(cred.Port),
-		usr,
-		passwd,
-		(*C.NET_DVR_DEVICEINFO_V30)(unsafe.Pointer(&deviceInfoV30)),
-	))
-
-	// 提取设备序列号
-	serialNumberBytes := make([]byte, len(deviceInfoV30.sSerialNumber))
-	for i := range deviceInfoV30.sSerialNumber {
-		serialNumberBytes[i] = byte(deviceInfoV30.sSerialNumber[i])
-	}
-	serialNumber := strings.Trim(string(serialNumberBytes), "\x00")
-
+	var deviceInfo sdk.DeviceInfo
+	began := time.Now()
+	loginID := backend.LoginV30(cred.IP, cred.Port, cred.Username, cred.Password, &deviceInfo)
 	if loginID < 0 {
-		return nil, core.NewHKError("登录设备(V30)")
+		err := core.NewHKErrorFrom(backend, "登录设备(V30)")
+		core.ObserveCall(core.OpLogin, cred.addr(), began, err)
+		return nil, err
 	}
+	core.ObserveCall(core.OpLogin, cred.addr(), began, nil)
+	core.GetMetrics().AddSessions(1)
 
 	session := &SessionInfo{
 		LoginID:      loginID,
-		SerialNumber: serialNumber,
-		ChannelNum:   int(deviceInfoV30.byChanNum),
+		SerialNumber: deviceInfo.SerialNumber,
+		ChannelNum:   deviceInfo.ChanNum,
+		Device:       newDeviceInfo(&deviceInfo),
 	}
 
-	log.Printf("✓ 登录成功(V30) - 用户ID: %d, 设备序列号: %s, 通道数: %d",
-		loginID, serialNumber, session.ChannelNum)
+	logLogin(core.Logger(), "V30", cred, session, time.Since(began))
 	return session, nil
 }
 
+// logLogin 记录登录成功日志，设备使用弱密码时记录警告
+func logLogin(logger *slog.Logger, api string, cred *Credentials, session *SessionInfo, duration time.Duration) {
+	logger = logger.With(slog.String("device", cred.addr()), slog.Int("login_id", session.LoginID))
+	logger.Info("登录成功", "api", api, "serial", session.SerialNumber, "channels", session.ChannelNum, "duration", duration)
+	if session.Device.PasswordLevel.Weak() {
+		logger.Warn("设备使用"+session.Device.PasswordLevel.String()+"，建议尽快修改", "password_level", int(session.Device.PasswordLevel))
+	}
+}
+
 // Logout 登出设备
 // 参数：
 //   - loginID: 登录ID
@@ -248,16 +411,27 @@ func LoginV30(cred *Credentials) (*SessionInfo, error) {
 // 返回值：
 //   - error: 错误信息，成功时为nil
 func Logout(loginID int) error {
+	return logout(sdk.Default(), loginID, core.NewLoginHandle(loginID).Device(), core.Logger())
+}
+
+// logout 使用指定后端登出设备，登出结果记录到 logger，device 为指标中的设备标识
+func logout(backend sdk.Backend, loginID int, device string, logger *slog.Logger) error {
 	if loginID < 0 {
 		return nil // 未登录，不是错误
 	}
 
-	result := C.NET_DVR_Logout(C.LONG(loginID))
-	if result == 0 {
-		return core.NewHKError("登出设备")
+	// 登出失败时会话同样不再使用，在线会话数照常减少
+	began := time.Now()
+	ok := backend.Logout(loginID)
+	core.GetMetrics().AddSessions(-1)
+	if !ok {
+		err := core.NewHKErrorFrom(backend, "登出设备")
+		core.ObserveCall(core.OpLogout, device, began, err)
+		return err
 	}
+	core.ObserveCall(core.OpLogout, device, began, nil)
 
-	log.Printf("✓ 登出成功（LoginID: %d）", loginID)
+	logger.Info("登出成功", "login_id", loginID)
 	return nil
 }
 
@@ -282,54 +456,13 @@ func ResolveDynamicIP(serverIP string, serverPort uint16, dvrName string, serial
 		return "", 0, errors.New("设备名称和序列号不能同时为空")
 	}
 
-	// 转换参数为C类型
-	cServerIP := C.CString(serverIP)
-	defer C.free(unsafe.Pointer(cServerIP))
-
-	// 准备设备名称参数
-	var cDVRName *C.BYTE
-	var dvrNameLen C.WORD
-	if dvrName != "" {
-		dvrNameBytes := []byte(dvrName)
-		cDVRName = (*C.BYTE)(unsafe.Pointer(&dvrNameBytes[0]))
-		dvrNameLen = C.WORD(len(dvrNameBytes))
-	}
-
-	// 准备序列号参数
-	var cSerialNumber *C.BYTE
-	var serialLen C.WORD
-	if serialNumber != "" {
-		serialBytes := []byte(serialNumber)
-		cSerialNumber = (*C.BYTE)(unsafe.Pointer(&serialBytes[0]))
-		serialLen = C.WORD(len(serialBytes))
+	backend := sdk.Default()
+	resolvedIP, resolvedPort, ok := backend.GetDVRIPByResolveSvrEx(serverIP, serverPort, dvrName, serialNumber)
+	if !ok {
+		return "", 0, core.NewHKErrorFrom(backend, "解析设备动态IP")
 	}
 
-	// 准备输出缓冲区
-	ipBuffer := make([]byte, 128)
-	cGetIP := (*C.char)(unsafe.Pointer(&ipBuffer[0]))
-	var dwPort C.DWORD
-
-	// 调用SDK函数
-	result := C.NET_DVR_GetDVRIPByResolveSvr_EX(
-		cServerIP,
-		C.WORD(serverPort),
-		cDVRName,
-		dvrNameLen,
-		cSerialNumber,
-		serialLen,
-		cGetIP,
-		&dwPort,
-	)
-
-	if result == 0 {
-		return "", 0, core.NewHKError("解析设备动态IP")
-	}
-
-	// 转换结果
-	resolvedIP := C.GoString(cGetIP)
-	resolvedPort := uint32(dwPort)
-
-	log.Printf("✓ 动态IP解析成功 - IP: %s, 端口: %d", resolvedIP, resolvedPort)
+	core.Logger().Info("动态IP解析成功", "ip", resolvedIP, "port", resolvedPort)
 	return resolvedIP, resolvedPort, nil
 }
 
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestLazyInit 未调用 Init 时首次登录以默认参数初始化
+func TestLazyInit(t *testing.T) {
+	fake := useFake(t)
+	if _, err := LoginV40(&Credentials{IP: "192.168.1.64"}); err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if c := fake.CallsTo("SetConnectTime"); len(c) != 1 || c[0].Args[0] != uint32(2000) || c[0].Args[1] != uint32(5) {
+		t.Errorf("默认连接超时错误: %v", c)
+	}
+	if c := fake.CallsTo("SetReconnect"); len(c) != 1 || c[0].Args[0] != uint32(10000) || c[0].Args[1] != true {
+		t.Errorf("默认重连参数错误: %v", c)
+	}
+	if len(fake.CallsTo("SetRecvTimeOut")) != 0 || len(fake.CallsTo("SetSDKInitCfg")) != 0 {
+		t.Error("默认参数不应设置接收超时和组件库路径")
+	}
+}
+
+// TestInit 显式初始化时应用全部参数，之后登录不再重复初始化
+func TestInit(t *testing.T) {
+	fake := useFake(t)
+	err := Init(&InitOptions{
+		ConnectTimeout:    10 * time.Second,
+		ConnectRetries:    3,
+		ReconnectInterval: 30 * time.Second,
+		RecvTimeout:       15 * time.Second,
+		LibraryPath:       "/opt/hcnetsdk/lib",
+		Log:               &LogOptions{Level: 3, Dir: "/var/log/hcnetsdk"},
+	})
+	if err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+
+	calls := fake.Calls()
+	if calls[0].Method != "SetSDKInitCfg" || calls[0].Args[0] != sdk.NET_SDK_INIT_CFG_SDK_PATH || calls[1].Method != "Init" {
+		t.Errorf("组件库路径应在初始化之前设置: %v", calls)
+	}
+	if c := fake.CallsTo("SetConnectTime"); c[0].Args[0] != uint32(10000) || c[0].Args[1] != uint32(3) {
+		t.Errorf("连接超时错误: %v", c)
+	}
+	if c := fake.CallsTo("SetRecvTimeOut"); len(c) != 1 || c[0].Args[0] != uint32(15000) {
+		t.Errorf("接收超时错误: %v", c)
+	}
+	if c := fake.CallsTo("SetLogToFile"); len(c) != 1 || c[0].Args[1] != "/var/log/hcnetsdk" {
+		t.Errorf("日志配置错误: %v", c)
+	}
+
+	fake.ResetCalls()
+	if _, err := LoginV40(&Credentials{IP: "192.168.1.64"}); err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if len(fake.CallsTo("Init")) != 0 || len(fake.CallsTo("SetConnectTime")) != 0 {
+		t.Error("已初始化时登录不应重新初始化")
+	}
+
+	// 已初始化后可以修改超时参数，但不能修改组件库路径
+	opts := DefaultInitOptions()
+	opts.ConnectTimeout = 500 * time.Millisecond
+	if err := Init(opts); err != nil {
+		t.Errorf("修改超时参数失败: %v", err)
+	}
+	opts.LibraryPath = "/usr/lib"
+	if err := Init(opts); err == nil {
+		t.Error("已初始化后修改组件库路径应返回错误")
+	}
+}
+
+// TestInitValidate 无效参数
+func TestInitValidate(t *testing.T) {
+	fake := useFake(t)
+	for _, opts := range []*InitOptions{
+		{ConnectTimeout: 100 * time.Millisecond, ConnectRetries: 1, DisableReconnect: true},
+		{ConnectTimeout: -time.Second},
+		{ConnectTimeout: time.Second, ConnectRetries: -1, DisableReconnect: true},
+		{ConnectTimeout: time.Second, ConnectRetries: 1, ReconnectInterval: 500 * time.Millisecond},
+		{ConnectTimeout: time.Second, ConnectRetries: 1, DisableReconnect: true, Log: &LogOptions{Forward: true}},
+	} {
+		if err := Init(opts); err == nil {
+			t.Errorf("无效参数应返回错误: %+v", opts)
+		}
+	}
+	if len(fake.CallsTo("Init")) != 0 {
+		t.Error("参数无效时不应初始化SDK")
+	}
+}
+
+// TestInitDefaults 零值字段使用默认值，不修改调用方的参数
+func TestInitDefaults(t *testing.T) {
+	fake := useFake(t)
+	opts := &InitOptions{ConnectTimeout: 5 * time.Second}
+	if err := Init(opts); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	if c := fake.CallsTo("SetConnectTime"); len(c) != 1 || c[0].Args[0] != uint32(5000) || c[0].Args[1] != uint32(5) {
+		t.Errorf("连接尝试次数应使用默认值: %v", c)
+	}
+	if c := fake.CallsTo("SetReconnect"); len(c) != 1 || c[0].Args[0] != uint32(10000) || c[0].Args[1] != true {
+		t.Errorf("重连间隔应使用默认值: %v", c)
+	}
+	if opts.ConnectRetries != 0 || opts.ReconnectInterval != 0 {
+		t.Errorf("不应修改调用方的参数: %+v", opts)
+	}
+}
@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/utils"
+)
+
+// DefaultLogForwardInterval 转发SDK日志时读取日志文件的默认间隔
+const DefaultLogForwardInterval = time.Second
+
+// maxLogReadSize 每次从单个日志文件读取的最大字节数，其余内容留到下次读取
+const maxLogReadSize = 1 << 20
+
+// sdkLogForwarder 正在运行的SDK日志转发器，受 sdkMutex 保护
+var sdkLogForwarder *logForwarder
+
+// sdkLogLevels SDK日志行中的级别标记 -> Go日志级别
+var sdkLogLevels = map[string]slog.Level{
+	"ERR":     slog.LevelError,
+	"ERROR":   slog.LevelError,
+	"WAR":     slog.LevelWarn,
+	"WARN":    slog.LevelWarn,
+	"WARNING": slog.LevelWarn,
+	"INF":     slog.LevelInfo,
+	"INFO":    slog.LevelInfo,
+	"DBG":     slog.LevelDebug,
+	"DEBUG":   slog.LevelDebug,
+	"TRACE":   slog.LevelDebug,
+}
+
+// logForwarder SDK日志转发器
+// SDK没有日志回调接口，只能将日志写入目录（NET_DVR_SetLogToFile）；
+// 转发器按间隔读取目录中日志文件新增的完整行，按级别转发到全局日志记录器（core.SetLogger）；
+// 启动时目录中已有的日志不转发，避免每次 Init 都重复输出历史日志
+type logForwarder struct {
+	dir      string           // SDK日志目录
+	temp     bool             // 目录是否为转发创建的临时目录，停止后删除
+	interval time.Duration    // 读取间隔
+	offsets  map[string]int64 // 文件路径 -> 已转发的位置
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// startLogForwarder 启动SDK日志转发器
+// 参数：
+//   - dir: SDK日志目录
+//   - temp: 目录是否为临时目录（停止后删除）
+//   - interval: 读取间隔，0 表示 DefaultLogForwardInterval
+func startLogForwarder(dir string, temp bool, interval time.Duration) *logForwarder {
+	if interval <= 0 {
+		interval = DefaultLogForwardInterval
+	}
+	f := &logForwarder{
+		dir:      dir,
+		temp:     temp,
+		interval: interval,
+		offsets:  make(map[string]int64),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	f.skipExisting()
+	go f.run()
+	return f
+}
+
+// skipExisting 将目录中已有日志文件的当前长度记为已转发的位置
+func (f *logForwarder) skipExisting() {
+	filepath.WalkDir(f.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			f.offsets[path] = info.Size()
+		}
+		return nil
+	})
+}
+
+// stopLogForwarderLocked 停止正在运行的SDK日志转发器，调用方需持有 sdkMutex
+func stopLogForwarderLocked() {
+	if sdkLogForwarder != nil {
+		sdkLogForwarder.close()
+		sdkLogForwarder = nil
+	}
+}
+
+// run 按间隔转发新增的日志
+func (f *logForwarder) run() {
+	defer close(f.done)
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+			f.poll()
+		}
+	}
+}
+
+// close 停止转发，转发剩余的日志后删除临时目录
+func (f *logForwarder) close() {
+	close(f.stop)
+	<-f.done
+	f.poll()
+	if f.temp {
+		os.RemoveAll(f.dir)
+	}
+}
+
+// poll 读取目录中所有日志文件新增的内容
+func (f *logForwarder) poll() {
+	seen := make(map[string]bool, len(f.offsets))
+	filepath.WalkDir(f.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		seen[path] = true
+		f.readFile(path)
+		return nil
+	})
+	// 已被SDK删除的文件不再记录位置
+	for path := range f.offsets {
+		if !seen[path] {
+			delete(f.offsets, path)
+		}
+	}
+}
+
+// readFile 转发单个日志文件中新增的完整行，不完整的最后一行留到下次读取；
+// 超过 maxLogReadSize 仍没有换行符的内容截断为一行转发，避免之后的日志无法读取
+func (f *logForwarder) readFile(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return
+	}
+	offset := f.offsets[path]
+	if info.Size() < offset {
+		offset = 0 // 文件被截断或重建
+	}
+	if info.Size() == offset {
+		return
+	}
+
+	data, err := io.ReadAll(io.NewSectionReader(file, offset, maxLogReadSize))
+	if err != nil {
+		return
+	}
+	end := bytes.LastIndexByte(data, '\n') + 1
+	if end == 0 {
+		if len(data) < maxLogReadSize {
+			return
+		}
+		end = len(data)
+	}
+	f.offsets[path] = offset + int64(end)
+
+	logger := core.Logger().With(slog.String("source", "hcnetsdk"), slog.String("file", filepath.Base(path)))
+	for _, line := range bytes.Split(data[:end], []byte{'\n'}) {
+		line = bytes.TrimRight(line, "\r")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		level, msg := parseSDKLogLine(decodeSDKLog(line))
+		logger.Log(context.Background(), level, msg)
+	}
+}
+
+// decodeSDKLog 将日志行转换为UTF-8（Windows 版SDK的日志为GBK编码）
+func decodeSDKLog(line []byte) string {
+	if !utf8.Valid(line) {
+		if s, err := utils.GBKToUTF8(line); err == nil {
+			return s
+		}
+	}
+	return string(line)
+}
+
+// parseSDKLogLine 解析SDK日志行的级别
+// 日志行以若干方括号字段开头（时间、级别、线程号等），如 "[2024-01-08 10:21:50.123][ERR] ..."，
+// 去掉这些字段后作为日志消息；没有级别标记的行按 Info 级别转发
+func parseSDKLogLine(line string) (slog.Level, string) {
+	level := slog.LevelInfo
+	rest := strings.TrimSpace(line)
+	for strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			break
+		}
+		if l, ok := sdkLogLevels[strings.ToUpper(strings.TrimSpace(rest[1:end]))]; ok {
+			level = l
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	if rest == "" {
+		rest = strings.TrimSpace(line)
+	}
+	return level, rest
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+)
+
+// lockedBuffer 可并发写入的缓冲区（转发器在独立协程中写日志）
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestParseSDKLogLine 按方括号中的级别标记映射日志级别
+func TestParseSDKLogLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		level slog.Level
+		msg   string
+	}{
+		{"[2024-01-08 10:21:50.123][ERR] connect failed", slog.LevelError, "connect failed"},
+		{"[2024-01-08 10:21:50][ WAR ][12345] retry", slog.LevelWarn, "retry"},
+		{"[2024-01-08 10:21:50][DBG] CNetService::Start", slog.LevelDebug, "CNetService::Start"},
+		{"plain message", slog.LevelInfo, "plain message"},
+		{"[2024-01-08]", slog.LevelInfo, "[2024-01-08]"},
+	}
+	for _, tt := range tests {
+		if level, msg := parseSDKLogLine(tt.line); level != tt.level || msg != tt.msg {
+			t.Errorf("%q 解析为 %v %q，期望 %v %q", tt.line, level, msg, tt.level, tt.msg)
+		}
+	}
+}
+
+// TestLogForward 未指定目录时使用临时目录，新增的完整行转发到Go日志记录器，Cleanup 后删除目录
+func TestLogForward(t *testing.T) {
+	fake := useFake(t)
+	var out lockedBuffer
+	core.SetLogger(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { core.SetLogger(nil) })
+
+	opts := DefaultInitOptions()
+	opts.Log = &LogOptions{Level: 3, Forward: true, ForwardInterval: 10 * time.Millisecond}
+	if err := Init(opts); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	calls := fake.CallsTo("SetLogToFile")
+	if len(calls) != 1 || calls[0].Args[2] != true {
+		t.Fatalf("应设置SDK日志目录并自动删除超量日志: %v", calls)
+	}
+	dir := calls[0].Args[1].(string)
+	if dir == "" {
+		t.Fatal("未指定目录时应使用临时目录")
+	}
+
+	// 模拟SDK写日志：最后一行不完整，暂不转发
+	path := filepath.Join(dir, "HCNetSDK.log")
+	if err := os.WriteFile(path, []byte("[2024-01-08 10:21:50][ERR] connect failed\r\n[2024-01-08 10:21:51][INF] part"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), "connect failed") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s := out.String(); !strings.Contains(s, `level=ERROR msg="connect failed" source=hcnetsdk file=HCNetSDK.log`) || strings.Contains(s, "part") {
+		t.Fatalf("转发的日志错误: %s", s)
+	}
+
+	// 补全最后一行，Cleanup 时转发剩余的日志并删除临时目录
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	file.WriteString("ial\n")
+	file.Close()
+	if err := Cleanup(); err != nil {
+		t.Fatalf("清理失败: %v", err)
+	}
+	if !strings.Contains(out.String(), `level=INFO msg=partial`) {
+		t.Errorf("Cleanup 时应转发剩余的日志: %s", out.String())
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Cleanup 后应删除临时目录: %v", err)
+	}
+}
+
+// waitLog 等待日志输出包含 want
+func waitLog(t *testing.T, out *lockedBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("日志中没有 %q", want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestLogForwardExistingDir 指定目录中已有的日志不转发，超长的行截断转发后继续读取之后的日志
+func TestLogForwardExistingDir(t *testing.T) {
+	useFake(t)
+	var out lockedBuffer
+	core.SetLogger(slog.New(slog.NewTextHandler(&out, nil)))
+	t.Cleanup(func() { core.SetLogger(nil) })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "HCNetSDK.log")
+	if err := os.WriteFile(path, []byte("[ERR] history\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultInitOptions()
+	opts.Log = &LogOptions{Level: 3, Dir: dir, Forward: true, ForwardInterval: 10 * time.Millisecond}
+	if err := Init(opts); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	file.WriteString("[ERR] started\n")
+	waitLog(t, &out, "started")
+	if strings.Contains(out.String(), "history") {
+		t.Errorf("启动前已有的日志不应转发: %s", out.String())
+	}
+
+	// 超过单次读取上限且没有换行符的内容截断转发，之后的行仍能读取
+	file.Write(bytes.Repeat([]byte{'x'}, maxLogReadSize+5))
+	file.WriteString("\n[ERR] after long line\n")
+	waitLog(t, &out, "after long line")
+}
@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+)
+
+// ConnectionState 设备连接状态
+type ConnectionState int
+
+const (
+	// StateConnected 已连接
+	StateConnected ConnectionState = iota
+	// StateReconnecting 会话失效，正在重新登录
+	StateReconnecting
+	// StateFailed 重新登录失败，已放弃（需要人工介入，如密码已修改）
+	StateFailed
+)
+
+// String 返回状态名称
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// StateChange 连接状态变化事件
+type StateChange struct {
+	State   ConnectionState // 新状态
+	LoginID int             // 当前登录ID（非连接状态下为-1）
+	Err     error           // 导致状态变化的错误（连接成功时为nil）
+	Time    time.Time       // 发生时间
+}
+
+// 保活参数默认值
+const (
+	DefaultCheckInterval  = 30 * time.Second // 默认会话检测间隔
+	DefaultInitialBackoff = time.Second      // 默认首次重连等待时间
+	DefaultMaxBackoff     = time.Minute      // 默认最大重连等待时间
+)
+
+// SupervisorOptions 会话保活参数
+type SupervisorOptions struct {
+	CheckInterval  time.Duration // 会话检测间隔，0表示使用默认值
+	InitialBackoff time.Duration // 首次重连等待时间，之后每次翻倍，0表示使用默认值
+	MaxBackoff     time.Duration // 最大重连等待时间，0表示使用默认值
+	MaxAttempts    int           // 最大连续重连次数，0表示不限
+}
+
+// Supervisor 会话保活监督器
+// 定期检测会话是否有效，失效后按指数退避重新登录，
+// 成功后更新设备的共享登录句柄并重新布防，状态变化通过 States() 通道发布
+type Supervisor struct {
+	dev    *Device
+	opts   SupervisorOptions
+	states chan StateChange // 状态变化通道
+	check  chan struct{}    // 立即检测信号
+	stop   chan struct{}    // 停止信号
+	done   chan struct{}    // 监督协程已退出
+
+	stopOnce sync.Once
+	mu       sync.Mutex
+	state    ConnectionState
+}
+
+// stateBufferSize 状态通道缓冲区大小，满时丢弃最旧的事件
+const stateBufferSize = 16
+
+// Supervise 启动会话保活
+// 同一设备重复调用返回同一个监督器；监督器已退出（如进入 StateFailed 或已 Stop）时启动新的监督器；
+// 设备 Close 时自动停止
+// 参数：
+//   - opts: 保活参数，nil表示全部使用默认值
+//
+// 返回值：
+//   - *Supervisor: 会话保活监督器
+func (d *Device) Supervise(opts *SupervisorOptions) *Supervisor {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.supervisor != nil && !d.supervisor.exited() {
+		return d.supervisor
+	}
+
+	var o SupervisorOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.CheckInterval <= 0 {
+		o.CheckInterval = DefaultCheckInterval
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = DefaultInitialBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+
+	s := &Supervisor{
+		dev:    d,
+		opts:   o,
+		states: make(chan StateChange, stateBufferSize),
+		check:  make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		state:  StateConnected,
+	}
+	d.supervisor = s
+
+	if d.closed {
+		// 设备已关闭，监督器直接结束
+		close(s.stop)
+		close(s.states)
+		close(s.done)
+		return s
+	}
+
+	go s.run()
+	return s
+}
+
+// States 返回状态变化通道
+// 通道在监督器停止（或进入 StateFailed）后关闭
+func (s *Supervisor) States() <-chan StateChange {
+	return s.states
+}
+
+// State 返回当前连接状态
+func (s *Supervisor) State() ConnectionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// CheckNow 立即检测一次会话状态
+// 适用于控制器返回网络错误（如错误码7）或用户不存在（错误码47）时主动触发
+func (s *Supervisor) CheckNow() {
+	select {
+	case s.check <- struct{}{}:
+	default:
+	}
+}
+
+// exited 监督协程是否已退出
+func (s *Supervisor) exited() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop 停止会话保活并等待监督协程退出
+func (s *Supervisor) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// run 监督协程主循环
+func (s *Supervisor) run() {
+	defer close(s.done)
+	defer close(s.states)
+
+	ticker := time.NewTicker(s.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.check:
+		}
+
+		backend := s.dev.backend
+		if backend.CheckUserStatus(s.dev.login.ID()) {
+			continue
+		}
+
+		cause := core.NewHKErrorFrom(backend, "检测会话状态")
+		s.dev.log().Warn("会话失效，开始重新登录", "error", cause)
+		if !s.reconnect(cause) {
+			return
+		}
+	}
+}
+
+// reconnect 按指数退避重新登录
+// 返回false表示监督器应退出（已停止或重连失败）
+func (s *Supervisor) reconnect(cause error) bool {
+	s.publish(StateReconnecting, -1, cause)
+
+	began := time.Now()
+	backoff := s.opts.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		loginID, err := s.dev.relogin()
+		if err == nil {
+			s.dev.log().Info("重新登录成功", "login_id", loginID, "attempts", attempt, "duration", time.Since(began))
+			s.publish(StateConnected, loginID, nil)
+			return true
+		}
+		if errors.Is(err, errDeviceClosed) {
+			return false
+		}
+
+		// 认证失败重试无意义，且可能导致账号被锁定
+		if core.IsAuthFailure(err) || (s.opts.MaxAttempts > 0 && attempt >= s.opts.MaxAttempts) {
+			s.dev.log().Error("重新登录失败，放弃重连", "attempts", attempt, "error", err)
+			s.publish(StateFailed, -1, err)
+			return false
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > s.opts.MaxBackoff {
+			backoff = s.opts.MaxBackoff
+		}
+	}
+}
+
+// publish 更新状态并发布事件
+// 通道已满时丢弃最旧的事件，保证监督协程不被慢速消费者阻塞
+func (s *Supervisor) publish(state ConnectionState, loginID int, err error) {
+	s.mu.Lock()
+	s.state = state
+	s.mu.Unlock()
+
+	change := StateChange{State: state, LoginID: loginID, Err: err, Time: time.Now()}
+	select {
+	case s.states <- change:
+		return
+	default:
+	}
+
+	select {
+	case <-s.states:
+	default:
+	}
+	select {
+	case s.states <- change:
+	default:
+	}
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// nextState 从状态通道读取下一个事件
+func nextState(t *testing.T, s *Supervisor) StateChange {
+	t.Helper()
+	select {
+	case change, ok := <-s.States():
+		if !ok {
+			t.Fatal("状态通道已关闭")
+		}
+		return change
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待状态变化超时")
+	}
+	return StateChange{}
+}
+
+// TestSupervisorRelogin 会话失效后重新登录，控制器透明使用新的登录ID并重新布防
+func TestSupervisorRelogin(t *testing.T) {
+	fake := useFake(t)
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "pwd"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	defer dev.Close()
+
+	ctrl := dev.PTZ(1)
+	if err := dev.Alarms().Start(); err != nil {
+		t.Fatalf("启动报警监听失败: %v", err)
+	}
+
+	oldID := dev.GetLoginID()
+	sup := dev.Supervise(&SupervisorOptions{CheckInterval: time.Hour, InitialBackoff: time.Millisecond})
+
+	// 模拟设备重启，第一次重新登录失败
+	fake.DropSession(oldID)
+	fake.FailOnce("LoginV40", sdk.NET_DVR_NETWORK_FAIL_CONNECT)
+	sup.CheckNow()
+
+	if change := nextState(t, sup); change.State != StateReconnecting {
+		t.Fatalf("应先进入重连状态，实际: %v", change.State)
+	}
+	change := nextState(t, sup)
+	if change.State != StateConnected || change.LoginID == oldID {
+		t.Fatalf("应使用新的登录ID重新连接，实际: %+v", change)
+	}
+	if n := len(fake.CallsTo("LoginV40")); n != 3 {
+		t.Errorf("应共登录3次（首次、失败重试、成功），实际: %d", n)
+	}
+
+	fake.ResetCalls()
+	if err := ctrl.StartUp(4); err != nil {
+		t.Fatalf("重新登录后控制失败: %v", err)
+	}
+	if calls := fake.CallsTo("PTZControlWithSpeedOther"); calls[0].Args[0] != change.LoginID {
+		t.Errorf("控制器应使用新的登录ID %d，实际: %v", change.LoginID, calls[0])
+	}
+	if !dev.Alarms().IsRunning() {
+		t.Error("重新登录后应重新布防")
+	}
+}
+
+// TestSupervisorPasswordChanged 密码错误时不再重试，进入失败状态
+func TestSupervisorPasswordChanged(t *testing.T) {
+	fake := useFake(t)
+
+	dev, err := Login(&Credentials{IP: "192.168.1.64", Port: 8000, Username: "admin", Password: "old"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	defer dev.Close()
+
+	sup := dev.Supervise(&SupervisorOptions{CheckInterval: time.Hour, InitialBackoff: time.Millisecond})
+	fake.DropSession(dev.GetLoginID())
+	fake.FailWith("LoginV40", sdk.NET_DVR_PASSWORD_ERROR)
+	sup.CheckNow()
+
+	nextState(t, sup)
+	if change := nextState(t, sup); change.State != StateFailed {
+		t.Fatalf("密码错误应进入失败状态，实际: %v", change.State)
+	}
+	if _, ok := <-sup.States(); ok {
+		t.Error("失败后状态通道应关闭")
+	}
+
+	// 失败后再次调用启动新的监督器，恢复保活
+	<-sup.done
+	fake.ClearFailures()
+	next := dev.Supervise(&SupervisorOptions{CheckInterval: time.Hour, InitialBackoff: time.Millisecond})
+	if next == sup {
+		t.Fatal("监督器退出后应返回新的监督器")
+	}
+	next.CheckNow()
+	nextState(t, next)
+	if change := nextState(t, next); change.State != StateConnected {
+		t.Fatalf("新的监督器应重新登录成功，实际: %v", change.State)
+	}
+}
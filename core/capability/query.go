@@ -0,0 +1,268 @@
+// Package capability 设备能力集查询
+//
+// 通过 NET_DVR_GetDeviceAbility 获取设备能力集XML，
+// 通过 NET_DVR_STDXMLConfig 透传 ISAPI 请求获取通道能力（云台、3D定位、智能事件），
+// 以便在调用相应功能之前判断设备是否支持
+package capability
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// 能力集类型（NET_DVR_GetDeviceAbility 的 dwAbilityType，常用取值）
+const (
+	SoftwareAbility = 0x001 // 软硬件能力
+	NetworkAbility  = 0x002 // 网络能力
+	EncodingAbility = 0x008 // 编码能力
+	IPViewAbility   = 0x009 // IP通道能力
+	AbilityInfo     = 0x011 // 通用能力（输入XML指定查询的能力，如 <PTZAbility><channelNO>1</channelNO></PTZAbility>）
+)
+
+// 能力集缓冲区大小
+const (
+	// DefaultBufferSize 初始缓冲区大小，不足时自动加倍
+	DefaultBufferSize = 64 * 1024
+	// MaxBufferSize 缓冲区上限
+	MaxBufferSize = 4 * 1024 * 1024
+)
+
+// statusBufferSize ISAPI响应状态缓冲区大小
+const statusBufferSize = 4 * 1024
+
+// ISAPI响应状态中表示设备不支持的取值
+const (
+	isapiInvalidOperation = 4            // statusCode：无效操作
+	isapiNotSupport       = "notSupport" // subStatusCode：不支持
+)
+
+// ResponseStatus ISAPI请求失败时设备返回的响应状态
+type ResponseStatus struct {
+	StatusCode    int    `xml:"statusCode"`    // 状态码（1-成功，4-无效操作，6-无效内容等）
+	StatusString  string `xml:"statusString"`  // 状态描述
+	SubStatusCode string `xml:"subStatusCode"` // 子状态码（如 notSupport、invalidID）
+}
+
+// Querier 设备能力集查询
+type Querier struct {
+	backend sdk.Backend       // SDK后端
+	login   *core.LoginHandle // 登录句柄
+}
+
+// NewQuerier 创建设备能力集查询（使用默认SDK后端）
+// 参数：
+//   - userID: 登录句柄
+func NewQuerier(userID int) *Querier {
+	return NewQuerierWithBackend(sdk.Default(), userID)
+}
+
+// NewQuerierWithBackend 使用指定SDK后端创建设备能力集查询
+// 参数：
+//   - backend: SDK后端（测试时可传入 sdk.NewFake()）
+//   - userID: 登录句柄
+func NewQuerierWithBackend(backend sdk.Backend, userID int) *Querier {
+	return NewQuerierWithHandle(backend, core.NewLoginHandle(userID))
+}
+
+// NewQuerierWithHandle 使用共享登录句柄创建设备能力集查询
+// 重新登录后更新句柄即可继续使用
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+func NewQuerierWithHandle(backend sdk.Backend, login *core.LoginHandle) *Querier {
+	return &Querier{
+		backend: backend,
+		login:   login,
+	}
+}
+
+// userID 获取有效的登录ID
+func (q *Querier) userID() (int, error) {
+	userID := q.login.ID()
+	if userID < 0 {
+		return -1, fmt.Errorf("无效的登录ID：%d", userID)
+	}
+	return userID, nil
+}
+
+// DeviceAbility 获取设备能力集XML（NET_DVR_GetDeviceAbility）
+// 参数：
+//   - abilityType: 能力集类型（SoftwareAbility、AbilityInfo 等）
+//   - input: 输入XML，不需要时传空字符串
+//
+// 返回值：
+//   - []byte: 能力集XML
+//   - error: 错误信息，成功时为nil
+func (q *Querier) DeviceAbility(abilityType int, input string) ([]byte, error) {
+	userID, err := q.userID()
+	if err != nil {
+		return nil, err
+	}
+
+	operation := fmt.Sprintf("获取设备能力集[类型:0x%03X]", abilityType)
+	for size := DefaultBufferSize; ; size *= 2 {
+		buf := make([]byte, size)
+		n, ok := q.backend.GetDeviceAbility(userID, abilityType, []byte(input), buf)
+		if ok {
+			return buf[:n], nil
+		}
+		if q.backend.GetLastError() != sdk.NET_DVR_NOENOUGH_BUF || size >= MaxBufferSize {
+			return nil, core.NewHKErrorFrom(q.backend, operation)
+		}
+	}
+}
+
+// ISAPI 透传ISAPI请求（NET_DVR_STDXMLConfig）
+// 请求失败时，设备返回的响应状态附加在错误消息中
+// 参数：
+//   - request: 请求信令，如 "GET /ISAPI/System/capabilities"
+//   - body: 请求报文，GET 请求传 nil
+//
+// 返回值：
+//   - []byte: 响应报文
+//   - error: 错误信息，成功时为nil
+func (q *Querier) ISAPI(request string, body []byte) ([]byte, error) {
+	userID, err := q.userID()
+	if err != nil {
+		return nil, err
+	}
+
+	operation := fmt.Sprintf("ISAPI请求[%s]", request)
+	status := make([]byte, statusBufferSize)
+	for size := DefaultBufferSize; ; size *= 2 {
+		buf := make([]byte, size)
+		n, statusLen, ok := q.backend.STDXMLConfig(userID, request, body, buf, status)
+		if ok {
+			return buf[:n], nil
+		}
+		if q.backend.GetLastError() == sdk.NET_DVR_NOENOUGH_BUF && size < MaxBufferSize {
+			continue
+		}
+
+		hkErr := core.NewHKErrorFrom(q.backend, operation)
+		if s, ok := parseStatus(status[:statusLen]); ok {
+			hkErr.WithISAPIStatus(s.StatusCode, s.SubStatusCode)
+		}
+		return nil, hkErr
+	}
+}
+
+// parseStatus 解析ISAPI响应状态
+func parseStatus(data []byte) (ResponseStatus, bool) {
+	var s ResponseStatus
+	if len(bytes.TrimSpace(data)) == 0 || xml.Unmarshal(data, &s) != nil {
+		return s, false
+	}
+	return s, true
+}
+
+// unsupported 判断ISAPI请求失败是否表示设备不支持
+// 除 NET_DVR_NOSUPPORT 外，部分设备以其他错误码失败，只在响应状态中返回
+// subStatusCode 为 notSupport 或 statusCode 为无效操作
+func unsupported(err error) bool {
+	if core.IsUnsupported(err) {
+		return true
+	}
+	var hkErr *core.HKError
+	return errors.As(err, &hkErr) &&
+		(hkErr.SubStatusCode == isapiNotSupport || hkErr.StatusCode == isapiInvalidOperation)
+}
+
+// basicEvents 普通（非智能）事件类型
+var basicEvents = map[string]bool{
+	"vmd":             true, // 移动侦测
+	"motiondetection": true,
+	"tamperdetection": true, // 遮挡报警
+	"shelteralarm":    true,
+	"videoloss":       true, // 视频丢失
+	"io":              true, // 报警输入
+}
+
+// ChannelCapabilities 通道能力
+type ChannelCapabilities struct {
+	Channel     int      // 通道号
+	PTZ         bool     // 是否支持云台控制
+	Position3D  bool     // 是否支持3D定位（ptz.Controller.CenterOn、ZoomToRect）
+	Events      []string // 支持的事件类型（ISAPI eventType，如 VMD、linedetection）
+	SmartEvents []string // 其中的智能事件类型（越界、区域入侵、人脸侦测等）
+}
+
+// SupportsEvent 是否支持指定事件类型（不区分大小写）
+func (c *ChannelCapabilities) SupportsEvent(eventType string) bool {
+	for _, e := range c.Events {
+		if strings.EqualFold(e, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// ptzChannelCap PTZ通道能力（GET /ISAPI/PTZCtrl/channels/<ID>/capabilities）
+type ptzChannelCap struct {
+	Position3D bool `xml:"isSupportPosition3D"`
+}
+
+// channelEventCap 通道事件能力（GET /ISAPI/Event/channels/<ID>/capabilities）
+type channelEventCap struct {
+	EventType struct {
+		Opt string `xml:"opt,attr"`
+	} `xml:"eventType"`
+}
+
+// Channel 查询通道能力
+// 设备不支持某项能力时对应字段为零值，不视为错误（判断方式见 unsupported）
+// 参数：
+//   - channel: 通道号
+//
+// 返回值：
+//   - *ChannelCapabilities: 通道能力
+//   - error: 错误信息，成功时为nil
+func (q *Querier) Channel(channel int) (*ChannelCapabilities, error) {
+	if channel < 1 {
+		return nil, fmt.Errorf("无效的通道号: %d", channel)
+	}
+	caps := &ChannelCapabilities{Channel: channel}
+
+	data, err := q.ISAPI(fmt.Sprintf("GET /ISAPI/PTZCtrl/channels/%d/capabilities", channel), nil)
+	switch {
+	case err == nil:
+		var ptz ptzChannelCap
+		if err := xml.Unmarshal(data, &ptz); err != nil {
+			return nil, fmt.Errorf("解析PTZ能力[通道:%d]失败: %w", channel, err)
+		}
+		caps.PTZ = true
+		caps.Position3D = ptz.Position3D
+	case !unsupported(err):
+		return nil, err
+	}
+
+	data, err = q.ISAPI(fmt.Sprintf("GET /ISAPI/Event/channels/%d/capabilities", channel), nil)
+	switch {
+	case err == nil:
+		var events channelEventCap
+		if err := xml.Unmarshal(data, &events); err != nil {
+			return nil, fmt.Errorf("解析事件能力[通道:%d]失败: %w", channel, err)
+		}
+		for _, e := range strings.Split(events.EventType.Opt, ",") {
+			if e = strings.TrimSpace(e); e == "" {
+				continue
+			}
+			caps.Events = append(caps.Events, e)
+			if !basicEvents[strings.ToLower(e)] {
+				caps.SmartEvents = append(caps.SmartEvents, e)
+			}
+		}
+	case !unsupported(err):
+		return nil, err
+	}
+
+	q.login.Logger().Debug("查询通道能力成功", "channel", channel,
+		"ptz", caps.PTZ, "position_3d", caps.Position3D, "smart_events", len(caps.SmartEvents))
+	return caps, nil
+}
@@ -0,0 +1,125 @@
+package capability
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// newTestQuerier 创建基于模拟后端的能力集查询
+func newTestQuerier(t *testing.T) (*Querier, *sdk.Fake) {
+	t.Helper()
+	fake, userID := sdk.NewLoggedInFake()
+	return NewQuerierWithBackend(fake, userID), fake
+}
+
+const ptzCaps = `<?xml version="1.0" encoding="UTF-8"?>
+<PTZChanelCap version="2.0" xmlns="http://www.hikvision.com/ver20/XMLSchema">
+<AbsolutePanTiltPositionSpace><XRange><min>0</min><max>3600</max></XRange></AbsolutePanTiltPositionSpace>
+<isSupportPosition3D>true</isSupportPosition3D>
+</PTZChanelCap>`
+
+const eventCaps = `<?xml version="1.0" encoding="UTF-8"?>
+<ChannelEventCap version="2.0" xmlns="http://www.hikvision.com/ver20/XMLSchema">
+<eventType opt="VMD,tamperdetection,videoloss,linedetection,fielddetection,facedetection"/>
+</ChannelEventCap>`
+
+// TestChannel 解析PTZ和事件能力，区分智能事件
+func TestChannel(t *testing.T) {
+	q, fake := newTestQuerier(t)
+	fake.SetISAPI("GET /ISAPI/PTZCtrl/channels/1/capabilities", []byte(ptzCaps))
+	fake.SetISAPI("GET /ISAPI/Event/channels/1/capabilities", []byte(eventCaps))
+
+	caps, err := q.Channel(1)
+	if err != nil {
+		t.Fatalf("查询通道能力失败: %v", err)
+	}
+	if !caps.PTZ || !caps.Position3D {
+		t.Errorf("应支持PTZ和3D定位: %+v", caps)
+	}
+	if len(caps.Events) != 6 || !slices.Equal(caps.SmartEvents, []string{"linedetection", "fielddetection", "facedetection"}) {
+		t.Errorf("事件能力错误: %+v", caps)
+	}
+	if !caps.SupportsEvent("vmd") || caps.SupportsEvent("regionEntrance") {
+		t.Error("SupportsEvent 判断错误")
+	}
+}
+
+// TestChannelNotSupported 设备不支持的能力为零值，其他错误照常返回
+func TestChannelNotSupported(t *testing.T) {
+	q, fake := newTestQuerier(t)
+	caps, err := q.Channel(2)
+	if err != nil {
+		t.Fatalf("设备不支持时不应返回错误: %v", err)
+	}
+	if caps.PTZ || caps.Position3D || len(caps.Events) != 0 {
+		t.Errorf("不支持的能力应为零值: %+v", caps)
+	}
+
+	fake.FailOnce("STDXMLConfig", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+	if _, err := q.Channel(2); err == nil {
+		t.Error("网络错误应返回错误")
+	}
+	if _, err := q.Channel(0); err == nil {
+		t.Error("无效的通道号应返回错误")
+	}
+}
+
+// TestChannelNotSupportedStatus 错误码不是 NET_DVR_NOSUPPORT 时，按响应状态判断设备是否不支持
+func TestChannelNotSupportedStatus(t *testing.T) {
+	const status = `<ResponseStatus version="2.0"><statusCode>%d</statusCode><subStatusCode>%s</subStatusCode></ResponseStatus>`
+	q, fake := newTestQuerier(t)
+	fake.SetISAPIError("GET /ISAPI/PTZCtrl/channels/1/capabilities", sdk.NET_DVR_PARAMETER_ERROR,
+		[]byte(fmt.Sprintf(status, 6, "notSupport")))
+	fake.SetISAPIError("GET /ISAPI/Event/channels/1/capabilities", sdk.NET_DVR_PARAMETER_ERROR,
+		[]byte(fmt.Sprintf(status, 4, "invalidOperation")))
+
+	caps, err := q.Channel(1)
+	if err != nil {
+		t.Fatalf("响应状态为不支持时不应返回错误: %v", err)
+	}
+	if caps.PTZ || len(caps.Events) != 0 {
+		t.Errorf("不支持的能力应为零值: %+v", caps)
+	}
+
+	fake.SetISAPIError("GET /ISAPI/PTZCtrl/channels/1/capabilities", sdk.NET_DVR_PARAMETER_ERROR,
+		[]byte(fmt.Sprintf(status, 6, "badXmlContent")))
+	if _, err := q.Channel(1); err == nil {
+		t.Error("其他响应状态应返回错误")
+	}
+}
+
+// TestISAPIStatus 请求失败时错误消息中包含设备返回的响应状态
+func TestISAPIStatus(t *testing.T) {
+	q, _ := newTestQuerier(t)
+	_, err := q.ISAPI("GET /ISAPI/System/capabilities", nil)
+	var hkErr *core.HKError
+	if !errors.As(err, &hkErr) || hkErr.Code != sdk.NET_DVR_NOSUPPORT || !strings.Contains(hkErr.Msg, "notSupport") {
+		t.Errorf("应返回包含响应状态的SDK错误，实际: %v", err)
+	}
+}
+
+// TestDeviceAbilityGrowBuffer 缓冲区不足时加倍重试
+func TestDeviceAbilityGrowBuffer(t *testing.T) {
+	q, fake := newTestQuerier(t)
+	input := "<PTZAbility><channelNO>1</channelNO></PTZAbility>"
+	ability := "<PTZAbility>" + strings.Repeat(" ", DefaultBufferSize) + "</PTZAbility>"
+	fake.SetAbility(AbilityInfo, input, []byte(ability))
+
+	data, err := q.DeviceAbility(AbilityInfo, input)
+	if err != nil || string(data) != ability {
+		t.Fatalf("获取能力集失败: %d字节 %v", len(data), err)
+	}
+	calls := fake.CallsTo("GetDeviceAbility")
+	if len(calls) != 2 || calls[1].Args[3] != 2*DefaultBufferSize {
+		t.Errorf("应加倍缓冲区重试一次: %v", len(calls))
+	}
+	if _, err := q.DeviceAbility(SoftwareAbility, ""); err == nil {
+		t.Error("未设置的能力集应返回错误")
+	}
+}
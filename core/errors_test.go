@@ -0,0 +1,125 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestHKErrorIs 错误码按类别匹配哨兵错误，包装后仍可判断
+func TestHKErrorIs(t *testing.T) {
+	err := fmt.Errorf("登录失败: %w", &HKError{Code: sdk.NET_DVR_PASSWORD_ERROR, Operation: "登录设备(V40)"})
+	if !errors.Is(err, ErrPasswordWrong) || errors.Is(err, ErrNetwork) {
+		t.Errorf("错误码1应属于 ErrPasswordWrong: %v", err)
+	}
+
+	timeout := &HKError{Code: sdk.NET_DVR_NETWORK_RECV_TIMEOUT}
+	if !errors.Is(timeout, ErrNetwork) || !errors.Is(timeout, ErrTimeout) {
+		t.Error("错误码10应同时属于 ErrNetwork 和 ErrTimeout")
+	}
+	if errors.Is(&HKError{Code: 9999}, ErrNetwork) {
+		t.Error("未知错误码不应属于任何类别")
+	}
+}
+
+// TestClassifiers 区分可重试、认证失败和不支持的错误
+func TestClassifiers(t *testing.T) {
+	tests := []struct {
+		code                                int
+		retryable, authFailure, unsupported bool
+	}{
+		{sdk.NET_DVR_PASSWORD_ERROR, false, true, false},
+		{sdk.NET_DVR_USER_LOCKED, false, true, false},
+		{sdk.NET_DVR_NOENOUGHPRI, false, false, false},
+		{sdk.NET_DVR_NETWORK_FAIL_CONNECT, true, false, false},
+		{sdk.NET_DVR_NETWORK_SEND_ERROR, true, false, false},
+		{sdk.NET_DVR_NETWORK_RECV_ERROR, true, false, false},
+		{sdk.NET_DVR_NETWORK_RECV_TIMEOUT, true, false, false},
+		{sdk.NET_DVR_NOSUPPORT, false, false, true},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("包装: %w", &HKError{Code: tt.code})
+		if IsRetryable(err) != tt.retryable || IsAuthFailure(err) != tt.authFailure || IsUnsupported(err) != tt.unsupported {
+			t.Errorf("错误码%d分类错误", tt.code)
+		}
+	}
+	if IsRetryable(errors.New("其他错误")) || IsAuthFailure(nil) {
+		t.Error("非 HKError 不应被分类")
+	}
+}
+
+// TestErrorMsgCatalog 错误码说明与官方文档一致，两种语言的目录一一对应
+func TestErrorMsgCatalog(t *testing.T) {
+	if msg := GetErrorMsgIn(sdk.NET_DVR_NOENOUGH_BUF, LocaleZhCN); msg != "缓冲区太小" {
+		t.Errorf("错误码43说明错误: %s", msg)
+	}
+	if msg := GetErrorMsgIn(sdk.NET_DVR_USERNOTEXIST, LocaleEnUS); msg != "user does not exist" {
+		t.Errorf("错误码47说明错误: %s", msg)
+	}
+	if msg := GetErrorMsgIn(9999, LocaleEnUS); msg != "unknown error code: 9999" {
+		t.Errorf("未知错误码说明错误: %s", msg)
+	}
+	for code := range zhCNMessages {
+		if _, ok := enUSMessages[code]; !ok {
+			t.Errorf("英文目录缺少错误码%d", code)
+		}
+	}
+	if len(zhCNMessages) != len(enUSMessages) {
+		t.Errorf("目录大小不一致: %d != %d", len(zhCNMessages), len(enUSMessages))
+	}
+}
+
+// TestSetLocale 切换语言后新建的错误使用对应语言
+func TestSetLocale(t *testing.T) {
+	t.Cleanup(func() { SetLocale(LocaleZhCN) })
+	if err := SetLocale("fr-FR"); err == nil || CurrentLocale() != LocaleZhCN {
+		t.Fatal("不支持的语言应返回错误且不改变当前语言")
+	}
+
+	fake := sdk.NewFake()
+	fake.FailWith("Init", sdk.NET_DVR_PASSWORD_ERROR)
+	fake.Init()
+	if err := SetLocale(LocaleEnUS); err != nil {
+		t.Fatal(err)
+	}
+	hkErr := NewHKErrorFrom(fake, "登录设备(V40)")
+	if got := hkErr.Error(); got != "登录设备(V40) failed, error code: 1, incorrect user name or password" {
+		t.Errorf("英文错误描述错误: %s", got)
+	}
+	if got := hkErr.ErrorIn(LocaleZhCN); got != "登录设备(V40)失败，错误码: 1, 用户名或密码错误" {
+		t.Errorf("指定语言的错误描述错误: %s", got)
+	}
+}
+
+// TestSDKErrorMsgFallback 目录未收录的错误码使用SDK返回的说明
+func TestSDKErrorMsgFallback(t *testing.T) {
+	fake := sdk.NewFake()
+	fake.FailWith("Init", 9001)
+	fake.SetErrorMsg(9001, "\xc9\xe8\xb1\xb8\xc0\xeb\xcf\xdf") // GBK编码的“设备离线”
+	fake.Init()
+	if hkErr := NewHKErrorFrom(fake, "登录设备(V40)"); hkErr.Msg != "设备离线" {
+		t.Errorf("应使用SDK返回的说明: %s", hkErr.Msg)
+	}
+
+	fake.FailWith("Init", 9002)
+	fake.Init()
+	if hkErr := NewHKErrorFrom(fake, "登录设备(V40)"); hkErr.Msg != "未知错误码: 9002" {
+		t.Errorf("SDK没有说明时应返回未知错误码: %s", hkErr.Msg)
+	}
+}
+
+// TestISAPIStatusMsg ISAPI子状态码按语言附加到错误消息
+func TestISAPIStatusMsg(t *testing.T) {
+	hkErr := (&HKError{Code: sdk.NET_DVR_NOSUPPORT, Msg: GetErrorMsg(sdk.NET_DVR_NOSUPPORT)}).WithISAPIStatus(4, "notSupport")
+	if hkErr.Msg != "设备不支持该功能（设备返回: notSupport, 不支持该功能）" {
+		t.Errorf("错误消息应附加子状态说明: %s", hkErr.Msg)
+	}
+	if got := hkErr.MessageIn(LocaleEnUS); got != "function not supported by the device (device returned: notSupport, not supported)" {
+		t.Errorf("英文错误消息错误: %s", got)
+	}
+	if got := GetISAPIStatusMsg(6, "someNewSubStatus", LocaleEnUS); got != "invalid XML content" {
+		t.Errorf("未收录的子状态码应使用状态码说明: %s", got)
+	}
+}
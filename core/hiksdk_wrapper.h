@@ -105,10 +105,22 @@ extern "C" {
 #define NET_DVR_NETWORK_SEND_ERROR  8   // 向服务器发送失败
 #define NET_DVR_NETWORK_RECV_ERROR  9   // 从服务器接收数据失败
 #define NET_DVR_NETWORK_RECV_TIMEOUT 10 // 从服务器接收数据超时
+#define NET_DVR_NOSUPPORT           23  // 设备不支持该功能
+#define NET_DVR_NOENOUGH_BUF        43  // 缓冲区太小
+
+// 远程控制命令
+#define NET_DVR_CHECK_USER_STATUS   20005 // 检测设备是否在线（会话保活）
 
 // 预览流类型（云台控制需要指定）
 #define STREAM_TYPE_MAIN            0    // 主码流
 #define STREAM_TYPE_SUB             1    // 子码流
+#define STREAM_TYPE_THIRD           2    // 三码流
+
+// 实时数据回调的数据类型（REALDATACALLBACK.dwDataType）
+#define NET_DVR_SYSHEAD             1    // 系统头数据（40字节的海康媒体头）
+#define NET_DVR_STREAMDATA          2    // 流数据（PS/RTP 封装的视频或复合流）
+#define NET_DVR_AUDIOSTREAMDATA     3    // 音频流数据
+#define NET_DVR_PRIVATE_DATA        112  // 私有数据（如智能信息帧）
 
 /* ========================================================================
  * 数据结构定义 - 登录相关
@@ -147,14 +159,27 @@ typedef struct tagNET_DVR_DEVICEINFO_V30 {
     BYTE  byRes2[2];                        // 保留
 } NET_DVR_DEVICEINFO_V30, *LPNET_DVR_DEVICEINFO_V30;
 
-// 设备信息 V40 版本（扩展V30）
+// 设备信息 V40 版本（扩展V30，布局与官方头文件一致）
 typedef struct tagNET_DVR_DEVICEINFO_V40 {
     NET_DVR_DEVICEINFO_V30 struDeviceV30;   // V30设备信息
-    BYTE  byRes1[4];                        // 保留
-    BYTE  byRes2[12];                       // 保留
-    BYTE  bySupport5;                       // 能力集扩展
-    BYTE  byLanguageTypeEx;                 // 语言类型扩展
-    BYTE  byRes3[54];                       // 保留
+    BYTE  bySupportLock;                    // 是否支持锁定功能，为1时 dwSurplusLockTime 和 byRetryLoginTime 有效
+    BYTE  byRetryLoginTime;                 // 剩余可尝试登录的次数（用户名、密码错误时有效）
+    BYTE  byPasswordLevel;                  // admin密码安全等级：0-无效，1-默认密码，2-有效密码，3-风险较高的密码
+    BYTE  byProxyType;                      // 代理类型：0-不使用代理，1-socks5代理，2-EHome代理
+    DWORD dwSurplusLockTime;                // 剩余锁定时间（秒），用户锁定时有效
+    BYTE  byCharEncodeType;                 // 字符编码类型
+    BYTE  bySupportDev5;                    // 是否支持V50版本的设备参数获取
+    BYTE  bySupport;                        // 能力集扩展
+    BYTE  byLoginMode;                      // 登录模式：0-Private，1-ISAPI
+    DWORD dwOEMCode;                        // OEM编码
+    int   iResidualValidity;                // 密码剩余有效天数，负值表示已超期
+    BYTE  byResidualValidity;               // iResidualValidity 是否有效
+    BYTE  bySingleStartDTalkChan;           // 独立音轨接入的起始对讲通道号
+    BYTE  bySingleDTalkChanNums;            // 独立音轨接入的对讲通道总数
+    BYTE  byPassWordResetLevel;             // 密码重置等级
+    BYTE  bySupportStreamEncrypt;           // 能力集扩展（码流加密）
+    BYTE  byMarketType;                     // 产品市场类型
+    BYTE  byRes2[238];                      // 保留
 } NET_DVR_DEVICEINFO_V40, *LPNET_DVR_DEVICEINFO_V40;
 
 // 用户登录信息
@@ -179,9 +204,8 @@ typedef struct tagNET_DVR_USER_LOGIN_INFO {
  * 数据结构定义 - 报警相关
  * ======================================================================== */
 
-// 报警设备信息
+// 报警设备信息（布局与官方头文件一致）
 typedef struct tagNET_DVR_ALARMER {
-    BYTE  sSerialNumber[MAX_SERIALNO_LEN];    // 序列号
     BYTE  byUserIDValid;                      // UserID是否有效
     BYTE  bySerialValid;                      // 序列号是否有效
     BYTE  byVersionValid;                     // 版本号是否有效
@@ -190,28 +214,109 @@ typedef struct tagNET_DVR_ALARMER {
     BYTE  byLinkPortValid;                    // 连接端口是否有效
     BYTE  byDeviceIPValid;                    // 设备IP是否有效
     BYTE  bySocketIPValid;                    // Socket IP是否有效
-    LONG  lUserID;                           // 用户ID
+    LONG  lUserID;                            // 用户ID
+    BYTE  sSerialNumber[MAX_SERIALNO_LEN];    // 序列号
+    DWORD dwDeviceVersion;                    // 版本信息（高16位主版本，低16位次版本）
     char  sDeviceName[MAX_DEVICE_NAME_LEN];   // 设备名字
     BYTE  byMacAddr[6];                       // MAC地址
-    BYTE  byRes[2];                           // 保留
-    DWORD dwDeviceIP;                         // 设备IP
-    DWORD dwSocketIP;                         // Socket IP
     WORD  wLinkPort;                          // 连接端口
-    BYTE  sSerialDevice[64];                  // 序列设备
-    BYTE  byIpProtocol;                       // IP协议
-    BYTE  byRes2[3];                          // 保留
+    char  sDeviceIP[128];                     // 设备IP（字符串）
+    char  sSocketIP[128];                     // Socket IP（字符串）
+    BYTE  byIpProtocol;                       // IP协议：0-IPv4，1-IPv6
+    BYTE  byRes1[2];                          // 保留
+    BYTE  bJSONBroken;                        // JSON断网续传标志
+    WORD  wSocketPort;                        // Socket端口
+    BYTE  byRes2[6];                          // 保留
 } NET_DVR_ALARMER, *LPNET_DVR_ALARMER;
 
-// 报警设置参数
+// 报警设置参数（布局与官方头文件一致）
 typedef struct tagNET_DVR_SETUPALARM_PARAM {
     DWORD dwSize;                             // 结构体大小
-    BYTE  byLevel;                            // 布防等级：0-一级，1-二级
+    BYTE  byLevel;                            // 布防优先级：0-一等级（高），1-二等级（中），2-三等级（低）
     BYTE  byAlarmInfoType;                    // 报警信息类型：0-老报警信息，1-新报警信息
+    BYTE  byRetAlarmTypeV40;                  // 设备支持时返回NET_DVR_ALARMINFO_V40：0-否，1-是
+    BYTE  byRetDevInfoVersion;                // CVR报警信息版本：0-COMM_ALARM_DEVICE，1-COMM_ALARM_DEVICE_V40
+    BYTE  byRetVQDAlarmType;                  // VQD报警上传类型
+    BYTE  byFaceAlarmDetection;               // 1-人脸侦测报警扩展，0-原先支持结构
+    BYTE  bySupport;                          // 按位：bit0-二级布防不上传图片，bit1-开启数据上传确认机制
+    BYTE  byBrokenNetHttp;                    // 断网续传类型（按位）
+    WORD  wTaskNo;                            // 任务处理号
+    BYTE  byDeployType;                       // 布防类型：0-客户端布防，1-实时布防
+    BYTE  bySubScription;                     // 订阅（按位），bit7-移动侦测人车分类是否传图
     BYTE  byRes1[2];                          // 保留
-    DWORD dwSubScribeType;                    // 订阅类型
-    BYTE  byRes[60];                          // 保留
+    BYTE  byAlarmTypeURL;                     // 图片数据采用URL传输（按位）
+    BYTE  byCustomCtrl;                       // 按位：bit0-上传副驾驶人脸子图
 } NET_DVR_SETUPALARM_PARAM, *LPNET_DVR_SETUPALARM_PARAM;
 
+// 报警消息类型（报警回调 lCommand）
+#define COMM_ALARM_RULE             0x1102 // 行为分析报警（NET_VCA_RULE_ALARM）
+#define COMM_ALARM_V30              0x4000 // V30报警信息（NET_DVR_ALARMINFO_V30）
+#define COMM_ALARM_V40              0x4007 // V40报警信息（NET_DVR_ALARMINFO_V40，可变长）
+
+// 时间参数（扩展）
+typedef struct tagNET_DVR_TIME_EX {
+    WORD  wYear;                              // 年
+    BYTE  byMonth;                            // 月
+    BYTE  byDay;                              // 日
+    BYTE  byHour;                             // 时
+    BYTE  byMinute;                           // 分
+    BYTE  bySecond;                           // 秒
+    BYTE  byRes;                              // 保留
+} NET_DVR_TIME_EX, *LPNET_DVR_TIME_EX;
+
+// V40报警信息固定部分（仅保留解码需要的联合体成员，联合体长度与官方头文件一致）
+typedef struct tagNET_DVR_ALRAM_FIXED_HEADER {
+    DWORD dwAlarmType;                        // 报警类型
+    NET_DVR_TIME_EX struAlarmTime;            // 发生报警的时间
+    union {
+        BYTE byUnionLen[116];                 // 联合体长度
+        struct {
+            DWORD dwAlarmInputNo;             // 报警输入号
+            DWORD dwTrigerAlarmOutNum;        // 触发的报警输出个数
+            DWORD dwTrigerRecordChanNum;      // 触发的录像通道个数
+        } struIOAlarm;                        // dwAlarmType为0时有效
+        struct {
+            DWORD dwAlarmChanNum;             // 报警通道个数
+            DWORD dwPicLen;                   // Jpeg图片长度
+            BYTE  byPicURL;                   // 图片数据方式：0-二进制，1-URL
+            BYTE  byTarget;                   // 识别目标：0-不区分，1-人，2-车
+            BYTE  byRes1[2];                  // 保留
+            char  *pDataBuff;                 // 报警图片或者图片URL
+        } struAlarmChannel;                   // dwAlarmType为2,3,6,9,10,13,28时有效
+        struct {
+            DWORD dwAlarmHardDiskNum;         // 报警硬盘个数
+        } struAlarmHardDisk;                  // dwAlarmType为1,4,5时有效
+    } uStruAlarm;
+    DWORD *pRes;                              // 用于兼容64位下结构体字节不对齐问题
+    BYTE  byTimeDiffFlag;                     // 时差字段是否有效
+    char  cTimeDifferenceH;                   // 与UTC的时差（小时）
+    char  cTimeDifferenceM;                   // 与UTC的时差（分钟）
+    BYTE  byRes;                              // 保留
+    WORD  wDevInfoIvmsChannel;                // 后端透传前端时的通道号
+    BYTE  byRes2[2];                          // 保留
+} NET_DVR_ALRAM_FIXED_HEADER, *LPNET_DVR_ALARM_FIXED_HEADER;
+
+// V40报警信息
+typedef struct tagNET_DVR_ALARMINFO_V40 {
+    NET_DVR_ALRAM_FIXED_HEADER struAlarmFixedHeader; // 报警固定部分
+    DWORD *pAlarmData;                        // 报警可变部分内容（DWORD数组）
+} NET_DVR_ALARMINFO_V40, *LPNET_DVR_ALARMINFO_V40;
+
+// hiksdk_alarm_v40_data_num 计算V40报警可变部分的DWORD个数
+static inline DWORD hiksdk_alarm_v40_data_num(const NET_DVR_ALARMINFO_V40 *info) {
+    const NET_DVR_ALRAM_FIXED_HEADER *h = &info->struAlarmFixedHeader;
+    switch (h->dwAlarmType) {
+    case 0:
+        return h->uStruAlarm.struIOAlarm.dwTrigerAlarmOutNum + h->uStruAlarm.struIOAlarm.dwTrigerRecordChanNum;
+    case 1: case 4: case 5:
+        return h->uStruAlarm.struAlarmHardDisk.dwAlarmHardDiskNum;
+    case 2: case 3: case 6: case 9: case 10: case 13: case 28:
+        return h->uStruAlarm.struAlarmChannel.dwAlarmChanNum;
+    default:
+        return 0;
+    }
+}
+
 /* ========================================================================
  * 数据结构定义 - 预览相关
  * ======================================================================== */
@@ -237,25 +342,296 @@ typedef struct tagNET_DVR_PREVIEWINFO {
 } NET_DVR_PREVIEWINFO, *LPNET_DVR_PREVIEWINFO;
 
 /* ========================================================================
- * 数据结构定义 - 设备配置相关（文档未涉及，保留注释供参考）
+ * 数据结构定义 - 设备配置相关（通道信息）
  * ======================================================================== */
 
-// 注：设备配置相关结构（NET_DVR_DEVICECFG, NET_DVR_PICCFG）已删除
-// 当前封装不涉及设备配置功能
+// 以下结构经由 NET_DVR_GetDVRConfig 以原始字节返回，由Go代码按偏移解析（core/auth/channels.go）
+
+// 参数配置命令
+#define NET_DVR_GET_IPPARACFG_V40   1062 // 获取IP接入配置（通道参数为组号，每组64个数字通道）
+#define NET_DVR_GET_PICCFG_V40      6179 // 获取图像参数（含通道名称）
+
+#define MAX_CHANNUM_V30             64   // 每组最大通道数
+#define MAX_IP_DEVICE_V40           64   // 每组最大IP设备数
+#define NAME_LEN                    32   // 用户名长度
+#define PASSWD_LEN                  16   // 密码长度
+#define MAX_DOMAIN_NAME             64   // 域名长度
+#define DEV_ID_LEN                  32   // 设备ID长度
+
+// IP地址
+typedef struct tagNET_DVR_IPADDR {
+    char  sIpV4[16];                          // IPv4地址
+    BYTE  byIPv6[128];                        // IPv6地址
+} NET_DVR_IPADDR, *LPNET_DVR_IPADDR;
+
+// 前端IP设备信息（296字节）
+typedef struct tagNET_DVR_IPDEVINFO_V31 {
+    BYTE  byEnable;                           // 该IP设备是否有效
+    BYTE  byProType;                          // 协议类型：0-私有协议，1-松下，2-索尼，其他见 NET_DVR_GetIPCProtoList
+    BYTE  byEnableQuickAdd;                   // 是否快捷添加
+    BYTE  byCameraType;                       // 通道接入的设备类型
+    BYTE  sUserName[NAME_LEN];                // 用户名
+    BYTE  sPassword[PASSWD_LEN];              // 密码
+    BYTE  byDomain[MAX_DOMAIN_NAME];          // 设备域名
+    NET_DVR_IPADDR struIP;                    // IP地址
+    WORD  wDVRPort;                           // 端口号
+    BYTE  szDeviceID[DEV_ID_LEN];             // 设备ID
+    BYTE  byEnableTiming;                     // 是否开启NVR对IPC自动校时
+    BYTE  byCertificateValidation;            // 证书验证
+} NET_DVR_IPDEVINFO_V31, *LPNET_DVR_IPDEVINFO_V31;
+
+// IP通道信息（直接从设备取流时的取流方式，36字节）
+typedef struct tagNET_DVR_IPCHANINFO {
+    BYTE  byEnable;                           // 该通道是否在线
+    BYTE  byIPID;                             // IP设备ID低8位（struIPDevInfo 下标+1）
+    BYTE  byChannel;                          // 前端设备的通道号
+    BYTE  byIPIDHigh;                         // IP设备ID高8位
+    BYTE  byTransProtocol;                    // 传输协议：0-TCP，1-UDP
+    BYTE  byGetStream;                        // 是否取流
+    BYTE  byres[30];                          // 保留
+} NET_DVR_IPCHANINFO, *LPNET_DVR_IPCHANINFO;
+
+// 取流方式（496字节）
+typedef struct tagNET_DVR_STREAM_MODE {
+    BYTE  byGetStreamType;                    // 取流方式：0-直接从设备取流（NET_DVR_IPCHANINFO），其他为流媒体、域名等方式
+    BYTE  byRes[3];                           // 保留
+    union {
+        NET_DVR_IPCHANINFO struChanInfo;      // 直接从设备取流
+        DWORD dwUnion[123];                   // 联合体大小（492字节）
+    } uGetStream;
+} NET_DVR_STREAM_MODE, *LPNET_DVR_STREAM_MODE;
+
+// IP接入配置（50792字节）
+typedef struct tagNET_DVR_IPPARACFG_V40 {
+    DWORD dwSize;                             // 结构体大小
+    DWORD dwGroupNum;                         // 设备支持的总组数
+    DWORD dwAChanNum;                         // 最大模拟通道个数
+    DWORD dwDChanNum;                         // 数字通道个数
+    DWORD dwStartDChan;                       // 起始数字通道
+    BYTE  byAnalogChanEnable[MAX_CHANNUM_V30];              // 模拟通道是否启用
+    NET_DVR_IPDEVINFO_V31 struIPDevInfo[MAX_IP_DEVICE_V40]; // IP设备
+    NET_DVR_STREAM_MODE struStreamMode[MAX_CHANNUM_V30];    // 各数字通道的取流方式
+    BYTE  byRes2[20];                         // 保留
+} NET_DVR_IPPARACFG_V40, *LPNET_DVR_IPPARACFG_V40;
+
+// 注：NET_DVR_PICCFG_V40 结构较大，只使用开头的通道名称（dwSize 之后的 sChanName[NAME_LEN]）
 
 /* ========================================================================
- * 数据结构定义 - PTZ相关（文档未涉及，保留注释供参考）
+ * 数据结构定义 - PTZ相关
  * ======================================================================== */
 
-// 注：PTZ位置信息结构（NET_DVR_PTZPOS, NET_DVR_PTZSCOPE）已删除
-// 当前封装仅使用基本PTZ控制命令，不涉及位置信息获取
+// 参数配置命令
+#define NET_DVR_SET_PTZPOS          292  // 云台设置PTZ位置
+#define NET_DVR_GET_PTZPOS          293  // 云台获取PTZ位置
+
+// 球机位置信息（各参数均为BCD编码，如 0x3599 表示 359.9）
+typedef struct tagNET_DVR_PTZPOS {
+    WORD  wAction;                            // 操作类型（获取时无效）：1-定位PTZ，2-定位P，3-定位T，4-定位Z，5-定位PT
+    WORD  wPanPos;                            // 水平参数
+    WORD  wTiltPos;                           // 垂直参数
+    WORD  wZoomPos;                           // 变倍参数
+} NET_DVR_PTZPOS, *LPNET_DVR_PTZPOS;
+
+// 云台区域选择放大缩小（3D定位，坐标基于255x255的画面）
+typedef struct tagNET_DVR_POINT_FRAME {
+    int xTop;                                 // 方框起始点的x坐标
+    int yTop;                                 // 方框起始点的y坐标
+    int xBottom;                              // 方框结束点的x坐标
+    int yBottom;                              // 方框结束点的y坐标
+    int bCounter;                             // 保留
+} NET_DVR_POINT_FRAME, *LPNET_DVR_POINT_FRAME;
+
+// 抓图参数
+typedef struct tagNET_DVR_JPEGPARA {
+    WORD wPicSize;                            // 图片尺寸：0-CIF，1-QCIF，2-D1，...，9-HD1080，0xff-Auto（使用当前码流分辨率）
+    WORD wPicQuality;                         // 图片质量：0-最好，1-较好，2-一般
+} NET_DVR_JPEGPARA, *LPNET_DVR_JPEGPARA;
+
+// 注：PTZ范围信息结构（NET_DVR_PTZSCOPE）未使用
 
 /* ========================================================================
  * 数据结构定义 - 其他（文档未涉及，保留注释供参考）
  * ======================================================================== */
 
-// 注：其他辅助结构（如 NET_DVR_TIME）已删除
-// 当前封装不涉及时间相关配置
+// 注：其他辅助结构已删除，仅保留录像查找和回放需要的时间结构
+
+/* ========================================================================
+ * 数据结构定义 - 初始化参数
+ * ======================================================================== */
+
+// 初始化参数类型（NET_DVR_SetSDKInitCfg）
+typedef enum tagNET_SDK_INIT_CFG_TYPE {
+    NET_SDK_INIT_CFG_TYPE_CHECK_MODULE_COM = 0, // 检查必需的组件
+    NET_SDK_INIT_CFG_ABILITY = 1,               // SDK支持的业务能力
+    NET_SDK_INIT_CFG_SDK_PATH = 2,              // 组件库（HCNetSDKCom）所在路径，NET_DVR_LOCAL_SDK_PATH
+    NET_SDK_INIT_CFG_LIBEAY_PATH = 3,           // libcrypto（libeay32）完整路径，char*
+    NET_SDK_INIT_CFG_SSLEAY_PATH = 4            // libssl（ssleay32）完整路径，char*
+} NET_SDK_INIT_CFG_TYPE;
+
+// 组件库路径
+typedef struct tagNET_DVR_LOCAL_SDK_PATH {
+    char  sPath[256];                         // 组件库所在目录
+    BYTE  byRes[128];                         // 保留
+} NET_DVR_LOCAL_SDK_PATH, *LPNET_DVR_LOCAL_SDK_PATH;
+
+/* ========================================================================
+ * 数据结构定义 - 能力集
+ * ======================================================================== */
+
+// 能力类型（NET_DVR_GetDeviceAbility dwAbilityType）
+#define DEVICE_SOFTHARDWARE_ABILITY 0x001 // 设备软硬件能力
+#define DEVICE_NETWORK_ABILITY      0x002 // 设备网络能力
+#define DEVICE_ENCODE_ALL_ABILITY   0x003 // 设备所有编码能力
+#define DEVICE_ALARM_ABILITY        0x008 // 报警能力
+#define DEVICE_DYNCHAN_ABILITY      0x009 // 数字通道能力
+#define DEVICE_USER_ABILITY         0x00c // 用户参数能力
+#define DEVICE_NETAPP_ABILITY       0x00d // 网络应用参数能力
+#define DEVICE_VIDEOPIC_ABILITY     0x00e // 图像参数能力
+#define DEVICE_JPEG_CAP_ABILITY     0x00f // JPEG抓图能力
+#define DEVICE_SERIAL_ABILITY       0x010 // RS232和RS485串口能力
+#define DEVICE_ABILITY_INFO         0x011 // 通用能力（输入XML指定查询的能力，如 PTZAbility）
+
+// ISAPI透传输入参数
+typedef struct tagNET_DVR_XML_CONFIG_INPUT {
+    DWORD dwSize;                             // 结构体大小
+    void  *lpRequestUrl;                      // 请求信令（如 "GET /ISAPI/System/capabilities"）
+    DWORD dwRequestUrlLen;                    // 请求信令长度
+    void  *lpInBuffer;                        // 输入参数（XML/JSON）
+    DWORD dwInBufferSize;                     // 输入参数长度
+    DWORD dwRecvTimeOut;                      // 接收超时（毫秒），0表示5000
+    BYTE  byForceEncrpt;                      // 是否强制加密
+    BYTE  byNumOfMultiPart;                   // 报文分段个数
+    BYTE  byRes[30];                          // 保留
+} NET_DVR_XML_CONFIG_INPUT, *LPNET_DVR_XML_CONFIG_INPUT;
+
+// ISAPI透传输出参数
+typedef struct tagNET_DVR_XML_CONFIG_OUTPUT {
+    DWORD dwSize;                             // 结构体大小
+    void  *lpOutBuffer;                       // 输出缓冲区
+    DWORD dwOutBufferSize;                    // 输出缓冲区大小
+    DWORD dwReturnedXMLSize;                  // 实际输出的长度
+    void  *lpStatusBuffer;                    // 响应状态（ResponseStatus XML），获取数据成功时不赋值
+    DWORD dwStatusSize;                       // 响应状态缓冲区大小
+    LPVOID lpDataBuffer;                      // 透传数据（分段时使用）
+    BYTE  byNumOfMultiPart;                   // 报文分段个数
+    BYTE  byRes[23];                          // 保留
+} NET_DVR_XML_CONFIG_OUTPUT, *LPNET_DVR_XML_CONFIG_OUTPUT;
+
+/* ========================================================================
+ * 数据结构定义 - 录像查找与下载
+ * ======================================================================== */
+
+#define CARDNUM_LEN_OUT             32   // 卡号长度
+#define GUID_LEN                    16   // GUID长度
+#define STREAM_ID_LEN               32   // 流ID长度
+#define MAX_FILENAME_LEN            100  // 录像文件名长度
+
+// 查找录像文件的返回状态（NET_DVR_FindNextFile_V40 返回值）
+#define NET_DVR_FILE_SUCCESS        1000 // 获取文件信息成功
+#define NET_DVR_FILE_NOFIND         1001 // 没有文件
+#define NET_DVR_ISFINDING           1002 // 正在查找，请等待
+#define NET_DVR_NOMOREFILE          1003 // 没有更多的文件，查找结束
+#define NET_DVR_FILE_EXCEPTION      1004 // 查找文件时异常
+
+// 回放控制命令（NET_DVR_PlayBackControl_V40）
+#define NET_DVR_PLAYSTART           1    // 开始播放（下载）
+#define NET_DVR_PLAYSTOP            2    // 停止播放
+#define NET_DVR_PLAYPAUSE           3    // 暂停播放
+#define NET_DVR_PLAYRESTART         4    // 恢复播放
+#define NET_DVR_PLAYFAST            5    // 快放（每次速度加倍，最快16倍）
+#define NET_DVR_PLAYSLOW            6    // 慢放（每次速度减半，最慢1/16）
+#define NET_DVR_PLAYNORMAL          7    // 正常速度
+#define NET_DVR_PLAYGETPOS          13   // 获取回放进度（输出int：0-100，200表示网络异常）
+#define NET_DVR_PLAYSETTIME         26   // 按绝对时间定位（输入NET_DVR_TIME）
+
+// 时间参数
+typedef struct tagNET_DVR_TIME {
+    DWORD dwYear;                             // 年
+    DWORD dwMonth;                            // 月
+    DWORD dwDay;                              // 日
+    DWORD dwHour;                             // 时
+    DWORD dwMinute;                           // 分
+    DWORD dwSecond;                           // 秒
+} NET_DVR_TIME, *LPNET_DVR_TIME;
+
+// 录像文件查找条件
+typedef struct tagNET_DVR_FILECOND_V40 {
+    LONG  lChannel;                           // 通道号
+    DWORD dwFileType;                         // 录像文件类型：0xff-全部，0-定时录像，1-移动侦测，2-报警触发，...
+    DWORD dwIsLocked;                         // 是否锁定：0-未锁定，1-锁定，0xff-全部
+    DWORD dwUseCardNo;                        // 是否带卡号查找
+    BYTE  sCardNumber[CARDNUM_LEN_OUT];       // 卡号
+    NET_DVR_TIME struStartTime;               // 开始时间
+    NET_DVR_TIME struStopTime;                // 结束时间
+    BYTE  byDrawFrame;                        // 是否抽帧：0-不抽帧，1-抽帧
+    BYTE  byFindType;                         // 0-查询普通卷，1-查询存档卷
+    BYTE  byQuickSearch;                      // 0-普通查询，1-快速查询
+    BYTE  bySpecialFindInfoType;              // 专有查询条件类型
+    DWORD dwVolumeNum;                        // 存档卷号
+    BYTE  byWorkingDeviceGUID[GUID_LEN];      // 工作机GUID
+    BYTE  uSpecialFindInfo[8];                // 专有查询条件（联合体，未使用）
+    BYTE  byStreamType;                       // 码流类型：0-主码流，1-子码流，2-三码流，0xff-全部
+    BYTE  byAudioFile;                        // 是否查找音频文件
+    BYTE  byRes2[30];                         // 保留
+} NET_DVR_FILECOND_V40, *LPNET_DVR_FILECOND_V40;
+
+// 录像文件信息
+typedef struct tagNET_DVR_FINDDATA_V40 {
+    char  sFileName[MAX_FILENAME_LEN];        // 文件名
+    NET_DVR_TIME struStartTime;               // 文件的开始时间
+    NET_DVR_TIME struStopTime;                // 文件的结束时间
+    DWORD dwFileSize;                         // 文件大小
+    char  sCardNum[32];                       // 卡号
+    BYTE  byLocked;                           // 是否锁定：1-锁定，0-未锁定
+    BYTE  byFileType;                         // 文件类型
+    BYTE  byQuickSearch;                      // 0-普通查询结果，1-快速查询结果
+    BYTE  byRes;                              // 保留
+    DWORD dwFileIndex;                        // 文件索引号
+    BYTE  byStreamType;                       // 码流类型
+    BYTE  byRes1[127];                        // 保留
+} NET_DVR_FINDDATA_V40, *LPNET_DVR_FINDDATA_V40;
+
+// 码流信息
+typedef struct tagNET_DVR_STREAM_INFO {
+    DWORD dwSize;                             // 结构体大小
+    BYTE  byID[STREAM_ID_LEN];                // 流ID
+    DWORD dwChannel;                          // 通道号
+    BYTE  byRes[32];                          // 保留
+} NET_DVR_STREAM_INFO, *LPNET_DVR_STREAM_INFO;
+
+// 按时间回放参数
+typedef struct tagNET_DVR_VOD_PARA {
+    DWORD dwSize;                             // 结构体大小
+    NET_DVR_STREAM_INFO struIDInfo;           // 码流信息
+    NET_DVR_TIME struBeginTime;               // 开始时间
+    NET_DVR_TIME struEndTime;                 // 结束时间
+    HWND  hWnd;                               // 播放窗口句柄（为NULL时不解码显示）
+    BYTE  byDrawFrame;                        // 是否抽帧
+    BYTE  byVolumeType;                       // 0-普通录像卷，1-存档卷
+    BYTE  byVolumeNum;                        // 卷号
+    BYTE  byStreamType;                       // 码流类型：0-主码流，1-子码流，2-三码流
+    DWORD dwFileIndex;                        // 存档卷上的录像文件索引
+    BYTE  byAudioFile;                        // 是否回放音频文件
+    BYTE  byCourseFile;                       // 课程文件
+    BYTE  byDownload;                         // 是否下载
+    BYTE  byOptimalStreamType;                // 是否按最优码流类型回放
+    BYTE  byRes2[20];                         // 保留
+} NET_DVR_VOD_PARA, *LPNET_DVR_VOD_PARA;
+
+// 按时间回放/下载条件
+typedef struct tagNET_DVR_PLAYCOND {
+    DWORD dwChannel;                          // 通道号
+    NET_DVR_TIME struStartTime;               // 开始时间
+    NET_DVR_TIME struStopTime;                // 结束时间
+    BYTE  byDrawFrame;                        // 是否抽帧
+    BYTE  byStreamType;                       // 码流类型：0-主码流，1-子码流，2-三码流
+    BYTE  byStreamID[STREAM_ID_LEN];          // 流ID
+    BYTE  byCourseFile;                       // 课程文件
+    BYTE  byDownload;                         // 是否下载
+    BYTE  byOptimalStreamType;                // 是否按最优码流类型回放
+    BYTE  byVODFileType;                      // 下载录像文件格式：0-PS，1-3GP
+    BYTE  byRes[26];                          // 保留
+} NET_DVR_PLAYCOND, *LPNET_DVR_PLAYCOND;
 
 /* ========================================================================
  * 回调函数类型定义
@@ -267,6 +643,9 @@ typedef void(CALLBACK *MSGCallBack)(LONG lCommand, NET_DVR_ALARMER *pAlarmer, ch
 // 实时数据回调函数（预览使用，云台控制需要）
 typedef void(CALLBACK *REALDATACALLBACK)(LONG lRealHandle, DWORD dwDataType, BYTE *pBuffer, DWORD dwBufSize, void *pUser);
 
+// 回放数据回调函数
+typedef void(CALLBACK *fPlayDataCallBack_V40)(LONG lPlayHandle, DWORD dwDataType, BYTE *pBuffer, DWORD dwBufSize, void *pUser);
+
 // 登录结果回调函数（异步登录）
 typedef void(CALLBACK *fLoginResultCallBack)(LONG lUserID, DWORD dwResult, LPNET_DVR_DEVICEINFO_V30 lpDeviceInfo, void *pUser);
 
@@ -277,7 +656,7 @@ typedef void(CALLBACK *fLoginResultCallBack)(LONG lUserID, DWORD dwResult, LPNET
 // 登录结果回调（异步登录使用）
 extern void GoLoginResultCallback(LONG lUserID, DWORD dwResult, LPNET_DVR_DEVICEINFO_V30 lpDeviceInfo, void *pUser);
 
-// 实时数据回调（预览使用，云台控制需要）
+// 实时数据回调（预览和回放共用，云台控制需要）
 extern void GoRealDataCallback(LONG lRealHandle, DWORD dwDataType, BYTE *pBuffer, DWORD dwBufSize, uintptr_t handle);
 
 /* ========================================================================
@@ -289,6 +668,8 @@ HIKSDK_API BOOL HIKSDK_CALL NET_DVR_Cleanup();
 HIKSDK_API BOOL HIKSDK_CALL NET_DVR_SetConnectTime(DWORD dwWaitTime, DWORD dwTryTimes); // 设置连接超时
 HIKSDK_API BOOL HIKSDK_CALL NET_DVR_SetReconnect(DWORD dwInterval, BOOL bEnableRecon);  // 设置重连
 HIKSDK_API BOOL HIKSDK_CALL NET_DVR_SetLogToFile(DWORD nLogLevel, char * strLogDir, BOOL bAutoDel); // 设置日志
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_SetRecvTimeOut(DWORD nRecvTimeOut);          // 设置接收超时（毫秒）
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_SetSDKInitCfg(NET_SDK_INIT_CFG_TYPE enumType, void * const lpInBuff); // 设置初始化参数（在 NET_DVR_Init 之前调用）
 
 /* ========================================================================
  * SDK函数声明 - 用户登录
@@ -325,11 +706,27 @@ HIKSDK_API BOOL HIKSDK_CALL NET_DVR_GetDVRIPByResolveSvr_EX(
 );
 
 /* ========================================================================
- * SDK函数声明 - 参数配置（文档未涉及，保留注释供参考）
+ * SDK函数声明 - 参数配置
  * ======================================================================== */
 
-// 注：设备配置函数（NET_DVR_GetDVRConfig, NET_DVR_SetDVRConfig）已删除
-// 当前封装不涉及设备配置功能
+// 获取设备配置
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_GetDVRConfig(
+    LONG lUserID,                            // 用户ID
+    DWORD dwCommand,                         // 配置命令
+    LONG lChannel,                           // 通道号（不需要通道号时为0xFFFFFFFF）
+    LPVOID lpOutBuffer,                      // 输出缓冲区
+    DWORD dwOutBufferSize,                   // 输出缓冲区大小
+    DWORD *lpBytesReturned                   // 实际返回的长度
+);
+
+// 设置设备配置
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_SetDVRConfig(
+    LONG lUserID,                            // 用户ID
+    DWORD dwCommand,                         // 配置命令
+    LONG lChannel,                           // 通道号（不需要通道号时为0xFFFFFFFF）
+    LPVOID lpInBuffer,                       // 输入缓冲区
+    DWORD dwInBufferSize                     // 输入缓冲区大小
+);
 
 /* ========================================================================
  * SDK函数声明 - 报警功能
@@ -455,6 +852,132 @@ HIKSDK_API BOOL HIKSDK_CALL NET_DVR_PTZTrack_Other(
     DWORD dwPTZTrackCmd                      // 轨迹命令
 );
 
+// 云台区域选择放大缩小（3D定位）
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_PTZSelZoomIn_EX(
+    LONG lUserID,                            // 用户ID
+    LONG lChannel,                           // 通道号
+    LPNET_DVR_POINT_FRAME pStruPointFrame    // 区域框
+);
+
+/* ========================================================================
+ * SDK函数声明 - 抓图
+ * ======================================================================== */
+
+// 设备抓图并保存为JPEG文件
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_CaptureJPEGPicture(
+    LONG lUserID,                            // 用户ID
+    LONG lChannel,                           // 通道号
+    LPNET_DVR_JPEGPARA lpJpegPara,           // 抓图参数
+    char *sPicFileName                       // 保存路径（含文件名）
+);
+
+// 设备抓图并写入内存缓冲区
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_CaptureJPEGPicture_NEW(
+    LONG lUserID,                            // 用户ID
+    LONG lChannel,                           // 通道号
+    LPNET_DVR_JPEGPARA lpJpegPara,           // 抓图参数
+    char *sJpegPicBuffer,                    // 图片缓冲区
+    DWORD dwPicSize,                         // 缓冲区大小
+    DWORD *lpSizeReturned                    // 实际图片大小
+);
+
+/* ========================================================================
+ * SDK函数声明 - 能力集
+ * ======================================================================== */
+
+// 获取设备能力集（输入、输出均为XML）
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_GetDeviceAbility(
+    LONG lUserID,                            // 用户ID
+    DWORD dwAbilityType,                     // 能力类型
+    char *pInBuf,                            // 输入参数（XML）
+    DWORD dwInLength,                        // 输入参数长度
+    char *pOutBuf,                           // 输出缓冲区（XML）
+    DWORD dwOutLength                        // 输出缓冲区大小
+);
+
+// ISAPI协议透传（如 GET /ISAPI/System/capabilities）
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_STDXMLConfig(
+    LONG lUserID,                            // 用户ID
+    LPNET_DVR_XML_CONFIG_INPUT lpInputParam, // 输入参数
+    LPNET_DVR_XML_CONFIG_OUTPUT lpOutputParam // 输出参数
+);
+
+/* ========================================================================
+ * SDK函数声明 - 录像查找与下载
+ * ======================================================================== */
+
+// 按条件查找录像文件，返回查找句柄
+HIKSDK_API LONG HIKSDK_CALL NET_DVR_FindFile_V40(
+    LONG lUserID,                            // 用户ID
+    LPNET_DVR_FILECOND_V40 pFindCond         // 查找条件
+);
+
+// 逐个获取查找到的文件信息，返回 NET_DVR_FILE_SUCCESS 等状态，失败返回-1
+HIKSDK_API LONG HIKSDK_CALL NET_DVR_FindNextFile_V40(
+    LONG lFindHandle,                        // 查找句柄
+    LPNET_DVR_FINDDATA_V40 lpFindData        // 文件信息
+);
+
+// 关闭文件查找，释放资源
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_FindClose_V30(
+    LONG lFindHandle                         // 查找句柄
+);
+
+// 按时间下载录像到文件，返回下载句柄（需调用 NET_DVR_PlayBackControl_V40 开始下载）
+HIKSDK_API LONG HIKSDK_CALL NET_DVR_GetFileByTime_V40(
+    LONG lUserID,                            // 用户ID
+    char *sSavedFileName,                    // 保存路径（含文件名）
+    LPNET_DVR_PLAYCOND pDownloadCond         // 下载条件
+);
+
+// 回放/下载控制
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_PlayBackControl_V40(
+    LONG lPlayHandle,                        // 回放或下载句柄
+    DWORD dwControlCode,                     // 控制命令
+    LPVOID lpInBuffer,                       // 输入参数
+    DWORD dwInLen,                           // 输入参数长度
+    LPVOID lpOutBuffer,                      // 输出参数
+    DWORD *lpOutLen                          // 输出参数长度
+);
+
+// 获取下载进度：0-100，200表示网络异常
+HIKSDK_API int HIKSDK_CALL NET_DVR_GetDownloadPos(
+    LONG lFileHandle                         // 下载句柄
+);
+
+// 停止下载
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_StopGetFile(
+    LONG lFileHandle                         // 下载句柄
+);
+
+/* ========================================================================
+ * SDK函数声明 - 录像回放
+ * ======================================================================== */
+
+// 按时间回放录像，返回回放句柄（需调用 NET_DVR_PlayBackControl_V40 开始播放）
+HIKSDK_API LONG HIKSDK_CALL NET_DVR_PlayBackByTime_V40(
+    LONG lUserID,                            // 用户ID
+    LPNET_DVR_VOD_PARA pVodPara              // 回放参数
+);
+
+// 注册回放数据回调
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_SetPlayDataCallBack_V40(
+    LONG lPlayHandle,                        // 回放句柄
+    fPlayDataCallBack_V40 fPlayDataCallBack, // 数据回调函数
+    void *pUser                              // 用户数据
+);
+
+// 获取回放画面的OSD时间
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_GetPlayBackOsdTime(
+    LONG lPlayHandle,                        // 回放句柄
+    LPNET_DVR_TIME lpOsdTime                 // OSD时间
+);
+
+// 停止回放
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_StopPlayBack(
+    LONG lPlayHandle                         // 回放句柄
+);
+
 /* ========================================================================
  * SDK函数声明 - 其他功能
  * ======================================================================== */
@@ -463,6 +986,14 @@ HIKSDK_API DWORD HIKSDK_CALL NET_DVR_GetLastError();     // 获取最后错误
 HIKSDK_API char* HIKSDK_CALL NET_DVR_GetErrorMsg(LONG *pErrorNo); // 获取错误信息
 HIKSDK_API DWORD HIKSDK_CALL NET_DVR_GetSDKVersion();    // 获取SDK版本
 
+// 远程控制（会话保活使用 NET_DVR_CHECK_USER_STATUS 命令）
+HIKSDK_API BOOL HIKSDK_CALL NET_DVR_RemoteControl(
+    LONG lUserID,                            // 用户ID
+    DWORD dwCommand,                         // 控制命令
+    LPVOID lpInBuffer,                       // 输入参数
+    DWORD dwInBufferSize                     // 输入参数长度
+);
+
 /* ========================================================================
  * C包装函数（用于简化CGO调用）
  * ======================================================================== */
@@ -472,6 +1003,11 @@ static inline LONG NET_DVR_RealPlay_V40_WithCallback(LONG lUserID, LPNET_DVR_PRE
     return NET_DVR_RealPlay_V40(lUserID, lpPreviewInfo, (REALDATACALLBACK)GoRealDataCallback, (void*)handle);
 }
 
+// 包装的回放数据回调注册函数，自动设置Go回调
+static inline BOOL NET_DVR_SetPlayDataCallBack_V40_WithCallback(LONG lPlayHandle, uintptr_t handle) {
+    return NET_DVR_SetPlayDataCallBack_V40(lPlayHandle, (fPlayDataCallBack_V40)GoRealDataCallback, (void*)handle);
+}
+
 #ifdef __cplusplus
 }
 #endif
@@ -0,0 +1,29 @@
+package core
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// discardLogger 丢弃所有日志的记录器
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// globalLogger 全局日志记录器
+var globalLogger atomic.Pointer[slog.Logger]
+
+// SetLogger 设置全局日志记录器
+// 未设置日志记录器的设备（见 LoginHandle.SetLogger）及不属于任何设备的操作（SDK初始化、报警监听服务等）使用该记录器。
+// 默认不输出任何日志
+// 参数：
+//   - logger: 日志记录器，nil表示恢复为不输出日志
+func SetLogger(logger *slog.Logger) {
+	globalLogger.Store(logger)
+}
+
+// Logger 获取全局日志记录器
+func Logger() *slog.Logger {
+	if l := globalLogger.Load(); l != nil {
+		return l
+	}
+	return discardLogger
+}
@@ -0,0 +1,379 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errShortData 数据不足
+var errShortData = errors.New("数据长度不足")
+
+// VideoConfig 视频参数（从参数集解析）
+type VideoConfig struct {
+	Codec  Codec  // 编码格式
+	Width  int    // 宽度（已去除裁剪区域）
+	Height int    // 高度（已去除裁剪区域）
+	VPS    []byte // H.265 视频参数集（不含起始码）
+	SPS    []byte // 序列参数集（不含起始码）
+	PPS    []byte // 图像参数集（不含起始码）
+
+	// 以下字段用于生成 MP4 的解码配置（avcC/hvcC）
+	chromaFormat     int    // 色度格式：0-单色，1-4:2:0，2-4:2:2，3-4:4:4
+	bitDepthLuma     int    // 亮度位深
+	bitDepthChroma   int    // 色度位深
+	maxSubLayers     int    // H.265 最大时域子层数
+	temporalIDNested bool   // H.265 时域子层嵌套标志
+	profileTierLevel []byte // H.265 general_profile_tier_level 的前12字节
+}
+
+// ParseVideoConfig 从关键帧的 Annex-B 数据中提取参数集并解析分辨率
+// 参数：
+//   - codec: CodecH264 或 CodecH265
+//   - data: 包含参数集的关键帧数据
+//
+// 返回值：
+//   - *VideoConfig: 视频参数
+//   - error: 缺少参数集或解析失败时返回错误
+func ParseVideoConfig(codec Codec, data []byte) (*VideoConfig, error) {
+	cfg := &VideoConfig{Codec: codec}
+	for _, nal := range SplitNALUnits(data) {
+		switch t := NALType(codec, nal); {
+		case codec == CodecH264 && t == h264NALSPS && cfg.SPS == nil:
+			cfg.SPS = nal
+		case codec == CodecH264 && t == h264NALPPS && cfg.PPS == nil:
+			cfg.PPS = nal
+		case codec == CodecH265 && t == h265NALVPS && cfg.VPS == nil:
+			cfg.VPS = nal
+		case codec == CodecH265 && t == h265NALSPS && cfg.SPS == nil:
+			cfg.SPS = nal
+		case codec == CodecH265 && t == h265NALPPS && cfg.PPS == nil:
+			cfg.PPS = nal
+		}
+	}
+
+	var err error
+	switch codec {
+	case CodecH264:
+		if cfg.SPS == nil || cfg.PPS == nil {
+			return nil, fmt.Errorf("关键帧中缺少 H.264 参数集（SPS/PPS）")
+		}
+		err = cfg.parseH264SPS()
+	case CodecH265:
+		if cfg.VPS == nil || cfg.SPS == nil || cfg.PPS == nil {
+			return nil, fmt.Errorf("关键帧中缺少 H.265 参数集（VPS/SPS/PPS）")
+		}
+		err = cfg.parseH265SPS()
+	default:
+		return nil, fmt.Errorf("不支持的视频编码: %s", codec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s SPS 失败: %w", codec, err)
+	}
+	return cfg, nil
+}
+
+// parseH264SPS 解析 H.264 SPS 中的分辨率和色度格式
+func (c *VideoConfig) parseH264SPS() error {
+	if len(c.SPS) < 4 {
+		return errShortData
+	}
+	br := newBitReader(removeEmulation(c.SPS[1:]))
+	profile := br.bits(8)
+	br.skip(16) // constraint_set_flags + level_idc
+	br.ue()     // seq_parameter_set_id
+
+	c.chromaFormat, c.bitDepthLuma, c.bitDepthChroma = 1, 8, 8
+	switch profile {
+	case 100, 110, 122, 244, 44, 83, 86, 118, 128, 138, 139, 134, 135:
+		c.chromaFormat = br.ue()
+		if c.chromaFormat == 3 {
+			br.skip(1) // separate_colour_plane_flag
+		}
+		c.bitDepthLuma = br.ue() + 8
+		c.bitDepthChroma = br.ue() + 8
+		br.skip(1) // qpprime_y_zero_transform_bypass_flag
+		if br.bit() {
+			// seq_scaling_matrix_present_flag
+			lists := 8
+			if c.chromaFormat == 3 {
+				lists = 12
+			}
+			for i := 0; i < lists; i++ {
+				if br.bit() {
+					size := 16
+					if i >= 6 {
+						size = 64
+					}
+					skipScalingList(br, size)
+				}
+			}
+		}
+	}
+
+	br.ue() // log2_max_frame_num_minus4
+	switch br.ue() {
+	case 0:
+		br.ue() // log2_max_pic_order_cnt_lsb_minus4
+	case 1:
+		br.skip(1) // delta_pic_order_always_zero_flag
+		br.se()    // offset_for_non_ref_pic
+		br.se()    // offset_for_top_to_bottom_field
+		for n := br.ue(); n > 0 && br.err == nil; n-- {
+			br.se()
+		}
+	}
+	br.ue()    // max_num_ref_frames
+	br.skip(1) // gaps_in_frame_num_value_allowed_flag
+
+	widthMbs := br.ue() + 1
+	heightMapUnits := br.ue() + 1
+	frameMbsOnly := 1
+	if !br.bit() {
+		frameMbsOnly = 0
+		br.skip(1) // mb_adaptive_frame_field_flag
+	}
+	br.skip(1) // direct_8x8_inference_flag
+
+	width := widthMbs * 16
+	height := (2 - frameMbsOnly) * heightMapUnits * 16
+	if br.bit() {
+		// frame_cropping_flag
+		left, right, top, bottom := br.ue(), br.ue(), br.ue(), br.ue()
+		cropX, cropY := 1, 2-frameMbsOnly
+		if c.chromaFormat == 1 || c.chromaFormat == 2 {
+			cropX = 2
+		}
+		if c.chromaFormat == 1 {
+			cropY *= 2
+		}
+		width -= (left + right) * cropX
+		height -= (top + bottom) * cropY
+	}
+
+	if br.err != nil {
+		return br.err
+	}
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("无效的分辨率 %dx%d", width, height)
+	}
+	c.Width, c.Height = width, height
+	return nil
+}
+
+// skipScalingList 跳过 H.264 SPS 中的缩放矩阵
+func skipScalingList(br *bitReader, size int) {
+	last, next := 8, 8
+	for i := 0; i < size && br.err == nil; i++ {
+		if next != 0 {
+			next = (last + br.se() + 256) % 256
+		}
+		if next != 0 {
+			last = next
+		}
+	}
+}
+
+// parseH265SPS 解析 H.265 SPS 中的档次级别、分辨率和色度格式
+func (c *VideoConfig) parseH265SPS() error {
+	if len(c.SPS) < 3 {
+		return errShortData
+	}
+	rbsp := removeEmulation(c.SPS[2:])
+	br := newBitReader(rbsp)
+	br.skip(4) // sps_video_parameter_set_id
+	c.maxSubLayers = br.bits(3) + 1
+	c.temporalIDNested = br.bit()
+
+	// general_profile_tier_level：profile_space..level_idc 共12字节
+	ptlStart := br.pos / 8
+	br.skip(96)
+	if br.err == nil {
+		c.profileTierLevel = append([]byte(nil), rbsp[ptlStart:ptlStart+12]...)
+	}
+
+	subLayers := c.maxSubLayers - 1
+	profilePresent := make([]bool, subLayers)
+	levelPresent := make([]bool, subLayers)
+	for i := 0; i < subLayers; i++ {
+		profilePresent[i] = br.bit()
+		levelPresent[i] = br.bit()
+	}
+	if subLayers > 0 {
+		br.skip(2 * (8 - subLayers)) // reserved_zero_2bits
+	}
+	for i := 0; i < subLayers; i++ {
+		if profilePresent[i] {
+			br.skip(88)
+		}
+		if levelPresent[i] {
+			br.skip(8)
+		}
+	}
+
+	br.ue() // sps_seq_parameter_set_id
+	c.chromaFormat = br.ue()
+	if c.chromaFormat == 3 {
+		br.skip(1) // separate_colour_plane_flag
+	}
+	width, height := br.ue(), br.ue()
+	if br.bit() {
+		// conformance_window_flag
+		left, right, top, bottom := br.ue(), br.ue(), br.ue(), br.ue()
+		subWidth, subHeight := 1, 1
+		if c.chromaFormat == 1 || c.chromaFormat == 2 {
+			subWidth = 2
+		}
+		if c.chromaFormat == 1 {
+			subHeight = 2
+		}
+		width -= (left + right) * subWidth
+		height -= (top + bottom) * subHeight
+	}
+	c.bitDepthLuma = br.ue() + 8
+	c.bitDepthChroma = br.ue() + 8
+
+	if br.err != nil {
+		return br.err
+	}
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("无效的分辨率 %dx%d", width, height)
+	}
+	c.Width, c.Height = width, height
+	return nil
+}
+
+// removeEmulation 去除 NAL 单元中的防竞争字节（00 00 03 -> 00 00）
+func removeEmulation(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	zeros := 0
+	for _, b := range data {
+		if zeros >= 2 && b == 3 {
+			zeros = 0
+			continue
+		}
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// bitReader 按位读取器（读越界后所有读取返回0，错误保存在 err 中）
+type bitReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func newBitReader(data []byte) *bitReader {
+	return &bitReader{data: data}
+}
+
+// bit 读取1位
+func (r *bitReader) bit() bool {
+	if r.pos >= len(r.data)*8 {
+		r.err = errShortData
+		return false
+	}
+	b := r.data[r.pos/8]>>(7-r.pos%8)&1 == 1
+	r.pos++
+	return b
+}
+
+// bits 读取n位（n<=32）
+func (r *bitReader) bits(n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		v <<= 1
+		if r.bit() {
+			v |= 1
+		}
+	}
+	return v
+}
+
+// skip 跳过n位
+func (r *bitReader) skip(n int) {
+	if r.pos+n > len(r.data)*8 {
+		r.pos = len(r.data) * 8
+		r.err = errShortData
+		return
+	}
+	r.pos += n
+}
+
+// ue 读取无符号指数哥伦布码
+func (r *bitReader) ue() int {
+	zeros := 0
+	for !r.bit() {
+		if r.err != nil || zeros > 31 {
+			r.err = errShortData
+			return 0
+		}
+		zeros++
+	}
+	return 1<<zeros - 1 + r.bits(zeros)
+}
+
+// se 读取有符号指数哥伦布码
+func (r *bitReader) se() int {
+	v := r.ue()
+	if v%2 == 1 {
+		return (v + 1) / 2
+	}
+	return -v / 2
+}
+
+// AudioConfig AAC 音频参数（从 ADTS 头解析）
+type AudioConfig struct {
+	ObjectType int // AAC 对象类型（2 表示 AAC-LC）
+	SampleRate int // 采样率
+	Channels   int // 声道数
+	freqIndex  int // 采样率索引
+}
+
+// aacSampleRates ADTS 采样率索引对应的采样率
+var aacSampleRates = []int{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+
+// AACSamplesPerFrame 每个 AAC 帧的采样数
+const AACSamplesPerFrame = 1024
+
+// ParseADTS 解析 ADTS 头
+// 返回值：
+//   - *AudioConfig: 音频参数
+//   - int: ADTS 头长度（7或9字节），其后为原始 AAC 数据
+//   - error: 不是有效的 ADTS 头时返回错误
+func ParseADTS(data []byte) (*AudioConfig, int, error) {
+	if len(data) < 7 || data[0] != 0xFF || data[1]&0xF0 != 0xF0 {
+		return nil, 0, fmt.Errorf("无效的 ADTS 头")
+	}
+	freqIndex := int(data[2]>>2) & 0x0F
+	if freqIndex >= len(aacSampleRates) {
+		return nil, 0, fmt.Errorf("无效的 AAC 采样率索引: %d", freqIndex)
+	}
+	headerLen := 7
+	if data[1]&0x01 == 0 {
+		// protection_absent=0 时带2字节CRC
+		headerLen = 9
+	}
+	if len(data) < headerLen {
+		return nil, 0, errShortData
+	}
+	return &AudioConfig{
+		ObjectType: int(data[2]>>6) + 1,
+		SampleRate: aacSampleRates[freqIndex],
+		Channels:   int(data[2]&0x01)<<2 | int(data[3]>>6),
+		freqIndex:  freqIndex,
+	}, headerLen, nil
+}
+
+// specificConfig 返回 AudioSpecificConfig（2字节）
+func (c *AudioConfig) specificConfig() []byte {
+	return []byte{
+		byte(c.ObjectType<<3) | byte(c.freqIndex>>1),
+		byte(c.freqIndex<<7) | byte(c.Channels<<3),
+	}
+}
@@ -0,0 +1,181 @@
+package media
+
+import (
+	"bytes"
+	"testing"
+)
+
+// bitWriter 构造参数集用的位写入器
+type bitWriter struct {
+	data []byte
+	n    int // 已写入的位数
+}
+
+func (w *bitWriter) bits(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		if v>>uint(i)&1 == 1 {
+			w.data[len(w.data)-1] |= 0x80 >> uint(w.n%8)
+		}
+		w.n++
+	}
+}
+
+func (w *bitWriter) ue(v int) {
+	x := uint64(v + 1)
+	size := 0
+	for t := x; t > 1; t >>= 1 {
+		size++
+	}
+	w.bits(0, size)
+	w.bits(x, size+1)
+}
+
+// nal 写入 rbsp_trailing_bits，加上防竞争字节并拼接 NAL 头
+func (w *bitWriter) nal(header ...byte) []byte {
+	w.bits(1, 1)
+	for w.n%8 != 0 {
+		w.bits(0, 1)
+	}
+	out := append([]byte(nil), header...)
+	zeros := 0
+	for _, b := range w.data {
+		if zeros >= 2 && b <= 3 {
+			out = append(out, 3)
+			zeros = 0
+		}
+		out = append(out, b)
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+	return out
+}
+
+// h264SPS 构造 1920x1080（1088 行裁剪8行）的 High Profile SPS
+func h264SPS() []byte {
+	var w bitWriter
+	w.bits(100, 8) // profile_idc
+	w.bits(0, 8)   // constraint_set_flags
+	w.bits(40, 8)  // level_idc
+	w.ue(0)        // seq_parameter_set_id
+	w.ue(1)        // chroma_format_idc
+	w.ue(0)        // bit_depth_luma_minus8
+	w.ue(0)        // bit_depth_chroma_minus8
+	w.bits(0, 2)   // qpprime_y_zero_transform_bypass_flag、seq_scaling_matrix_present_flag
+	w.ue(0)        // log2_max_frame_num_minus4
+	w.ue(2)        // pic_order_cnt_type
+	w.ue(1)        // max_num_ref_frames
+	w.bits(0, 1)   // gaps_in_frame_num_value_allowed_flag
+	w.ue(119)      // pic_width_in_mbs_minus1
+	w.ue(67)       // pic_height_in_map_units_minus1
+	w.bits(1, 1)   // frame_mbs_only_flag
+	w.bits(1, 1)   // direct_8x8_inference_flag
+	w.bits(1, 1)   // frame_cropping_flag
+	w.ue(0)
+	w.ue(0)
+	w.ue(0)
+	w.ue(4)
+	w.bits(0, 1) // vui_parameters_present_flag
+	return w.nal(0x67)
+}
+
+// h265SPS 构造 1920x1080 的 Main Profile SPS（general_profile_tier_level 中含连续的0字节）
+func h265SPS() []byte {
+	var w bitWriter
+	w.bits(0, 4) // sps_video_parameter_set_id
+	w.bits(0, 3) // sps_max_sub_layers_minus1
+	w.bits(1, 1) // sps_temporal_id_nesting_flag
+	for _, b := range []byte{0x01, 0x60, 0, 0, 0, 0x90, 0, 0, 0, 0, 0, 0x78} {
+		w.bits(uint64(b), 8)
+	}
+	w.ue(0)    // sps_seq_parameter_set_id
+	w.ue(1)    // chroma_format_idc
+	w.ue(1920) // pic_width_in_luma_samples
+	w.ue(1088) // pic_height_in_luma_samples
+	w.bits(1, 1)
+	w.ue(0)
+	w.ue(0)
+	w.ue(0)
+	w.ue(4)
+	w.ue(0) // bit_depth_luma_minus8
+	w.ue(0) // bit_depth_chroma_minus8
+	return w.nal(33<<1, 0x01)
+}
+
+// annexB 用4字节起始码拼接 NAL 单元
+func annexB(nals ...[]byte) []byte {
+	var out []byte
+	for _, n := range nals {
+		out = append(out, 0, 0, 0, 1)
+		out = append(out, n...)
+	}
+	return out
+}
+
+// TestParseVideoConfigH264 解析 H.264 SPS 的分辨率（含裁剪）
+func TestParseVideoConfigH264(t *testing.T) {
+	data := annexB([]byte{0x09, 0xF0}, h264SPS(), []byte{0x68, 0xCE, 0x3C, 0x80}, []byte{0x65, 0x88, 0x84})
+	cfg, err := ParseVideoConfig(CodecH264, data)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if cfg.Width != 1920 || cfg.Height != 1080 {
+		t.Errorf("分辨率错误: %dx%d", cfg.Width, cfg.Height)
+	}
+	if cfg.SPS[0] != 0x67 || !bytes.Equal(cfg.PPS, []byte{0x68, 0xCE, 0x3C, 0x80}) {
+		t.Errorf("参数集错误: SPS=% X PPS=% X", cfg.SPS, cfg.PPS)
+	}
+
+	if _, err := ParseVideoConfig(CodecH264, annexB([]byte{0x65, 0x88})); err == nil {
+		t.Error("缺少参数集时应返回错误")
+	}
+}
+
+// TestParseVideoConfigH265 解析 H.265 SPS 的档次级别和分辨率（含防竞争字节）
+func TestParseVideoConfigH265(t *testing.T) {
+	sps := h265SPS()
+	if !bytes.Contains(sps, []byte{0, 0, 3}) {
+		t.Fatal("构造的 SPS 应包含防竞争字节")
+	}
+	data := annexB([]byte{0x40, 0x01, 0x0C}, sps, []byte{0x44, 0x01, 0xC1}, []byte{0x26, 0x01, 0xAF})
+	cfg, err := ParseVideoConfig(CodecH265, data)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if cfg.Width != 1920 || cfg.Height != 1080 {
+		t.Errorf("分辨率错误: %dx%d", cfg.Width, cfg.Height)
+	}
+	want := []byte{0x01, 0x60, 0, 0, 0, 0x90, 0, 0, 0, 0, 0, 0x78}
+	if !bytes.Equal(cfg.profileTierLevel, want) || cfg.maxSubLayers != 1 || !cfg.temporalIDNested {
+		t.Errorf("档次级别错误: % X", cfg.profileTierLevel)
+	}
+}
+
+// adtsFrame 构造 AAC-LC 44.1kHz 双声道的 ADTS 帧
+func adtsFrame(payload int) []byte {
+	size := 7 + payload
+	f := []byte{0xFF, 0xF1, 0x50, 0x80 | byte(size>>11), byte(size >> 3), byte(size<<5) | 0x1F, 0xFC}
+	return append(f, bytes.Repeat([]byte{0x21}, payload)...)
+}
+
+// TestParseADTS 解析 ADTS 头并生成 AudioSpecificConfig
+func TestParseADTS(t *testing.T) {
+	cfg, headerLen, err := ParseADTS(adtsFrame(10))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if headerLen != 7 || cfg.ObjectType != 2 || cfg.SampleRate != 44100 || cfg.Channels != 2 {
+		t.Errorf("ADTS 解析错误: %+v 头长度 %d", cfg, headerLen)
+	}
+	if asc := cfg.specificConfig(); !bytes.Equal(asc, []byte{0x12, 0x10}) {
+		t.Errorf("AudioSpecificConfig 错误: % X", asc)
+	}
+	if _, _, err := ParseADTS([]byte{0xFF, 0x00, 0, 0, 0, 0, 0}); err == nil {
+		t.Error("无效的 ADTS 头应返回错误")
+	}
+}
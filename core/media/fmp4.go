@@ -0,0 +1,464 @@
+package media
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// fMP4 轨道ID
+const (
+	fmp4VideoTrack = 1
+	fmp4AudioTrack = 2
+)
+
+// trun 中的样本标志
+const (
+	sampleFlagsSync    = 0x02000000 // 不依赖其他样本（关键帧）
+	sampleFlagsNonSync = 0x01010000 // 依赖其他样本，非同步样本
+)
+
+// defaultFrameDuration 无法计算时长时使用的默认视频帧时长（25fps，90kHz）
+const defaultFrameDuration = ClockRate / 25
+
+// fmp4Sample 片段中的一个样本
+type fmp4Sample struct {
+	dts      int64  // 解码时间（轨道时间刻度，相对文件开始）
+	cto      int64  // 显示时间偏移（PTS-DTS）
+	duration int64  // 时长
+	key      bool   // 是否为同步样本
+	data     []byte // 样本数据（视频为长度前缀格式）
+}
+
+// FMP4Muxer 分片 MP4（fMP4）封装器
+// 视频支持 H.264/H.265，音频只支持 AAC（其他音频帧被忽略）。
+// 第一个视频关键帧时写入初始化段（ftyp+moov），此时已收到 AAC 音频才会包含音频轨道；
+// 之后每个关键帧开始一个新片段（moof+mdat），因此文件在任意时刻中断都可以播放到最后一个完整片段
+type FMP4Muxer struct {
+	w       io.Writer
+	video   *VideoConfig // 视频参数，第一个关键帧确定
+	audio   *AudioConfig // AAC 参数，没有音频轨道时为nil
+	started bool         // 是否已写入初始化段
+	seq     uint32       // 片段序号
+
+	firstDTS int64 // 第一个视频帧的 DTS（90kHz 原始值）
+	lastDTS  int64 // 上一个视频帧的 DTS（原始值）
+	lastRel  int64 // 上一个视频帧相对 firstDTS 的时间（已处理回绕）
+
+	videoSamples []fmp4Sample // 当前片段的视频样本
+	audioSamples []fmp4Sample // 当前片段的音频样本
+	lastDuration int64        // 上一个视频样本的时长
+}
+
+// NewFMP4Muxer 创建分片 MP4 封装器
+// 参数：
+//   - w: 输出
+func NewFMP4Muxer(w io.Writer) *FMP4Muxer {
+	return &FMP4Muxer{w: w, lastDuration: defaultFrameDuration}
+}
+
+// WriteFrame 写入一帧
+// 第一个视频关键帧之前的帧被忽略；关键帧缺少参数集时返回错误
+func (m *FMP4Muxer) WriteFrame(f *Frame) error {
+	if f.Codec.IsAudio() {
+		return m.writeAudio(f)
+	}
+	if !f.Codec.IsVideo() {
+		return fmt.Errorf("fMP4 不支持的视频编码: %s", f.Codec)
+	}
+
+	if !m.started {
+		if !f.Keyframe {
+			return nil
+		}
+		cfg, err := ParseVideoConfig(f.Codec, f.Data)
+		if err != nil {
+			return err
+		}
+		m.video = cfg
+		m.firstDTS, m.lastDTS = f.DTS, f.DTS
+		if _, err := m.w.Write(m.initSegment()); err != nil {
+			return err
+		}
+		m.started = true
+	} else if f.Codec != m.video.Codec {
+		return fmt.Errorf("视频编码从 %s 变为 %s", m.video.Codec, f.Codec)
+	}
+
+	rel := m.relative(f.DTS)
+	if n := len(m.videoSamples); n > 0 {
+		// 上一个样本的时长由当前帧的 DTS 确定
+		if d := rel - m.videoSamples[n-1].dts; d > 0 {
+			m.videoSamples[n-1].duration = d
+			m.lastDuration = d
+		}
+	}
+	if f.Keyframe && len(m.videoSamples) > 0 {
+		if err := m.flush(); err != nil {
+			return err
+		}
+	}
+
+	m.videoSamples = append(m.videoSamples, fmp4Sample{
+		dts:      rel,
+		cto:      tsDelta(f.PTS, f.DTS),
+		duration: m.lastDuration,
+		key:      f.Keyframe,
+		data:     annexBToLengthPrefixed(f.Codec, f.Data),
+	})
+	return nil
+}
+
+// writeAudio 写入 AAC 音频帧（一个 PES 可能包含多个 ADTS 帧）
+func (m *FMP4Muxer) writeAudio(f *Frame) error {
+	if f.Codec != CodecAAC {
+		return nil
+	}
+	if !m.started {
+		// 初始化段写入之前只记录音频参数
+		if m.audio == nil {
+			m.audio, _, _ = ParseADTS(f.Data)
+		}
+		return nil
+	}
+	if m.audio == nil {
+		return nil
+	}
+
+	// 音频时间换算为音频采样率刻度，相对第一个视频帧
+	rel := m.lastRel + tsDelta(f.PTS, m.lastDTS)
+	if rel < 0 {
+		return nil
+	}
+	dts := rel * int64(m.audio.SampleRate) / ClockRate
+	for data := f.Data; len(data) > 0; {
+		_, headerLen, err := ParseADTS(data)
+		if err != nil {
+			return nil
+		}
+		frameLen := int(data[3]&0x03)<<11 | int(data[4])<<3 | int(data[5]>>5)
+		if frameLen < headerLen || frameLen > len(data) {
+			return nil
+		}
+		m.audioSamples = append(m.audioSamples, fmp4Sample{
+			dts:      dts,
+			duration: AACSamplesPerFrame,
+			key:      true,
+			data:     data[headerLen:frameLen],
+		})
+		dts += AACSamplesPerFrame
+		data = data[frameLen:]
+	}
+	return nil
+}
+
+// Close 写出最后一个片段
+func (m *FMP4Muxer) Close() error {
+	if len(m.videoSamples) == 0 {
+		return nil
+	}
+	return m.flush()
+}
+
+// relative 计算视频 DTS 相对第一个视频帧的时间（处理33位回绕）
+func (m *FMP4Muxer) relative(dts int64) int64 {
+	m.lastRel += tsDelta(dts, m.lastDTS)
+	m.lastDTS = dts
+	return m.lastRel
+}
+
+// tsDelta 计算两个33位时间戳的差（a-b），考虑回绕
+func tsDelta(a, b int64) int64 {
+	const wrap = 1 << 33
+	d := (a - b) % wrap
+	if d > wrap/2 {
+		d -= wrap
+	} else if d < -wrap/2 {
+		d += wrap
+	}
+	return d
+}
+
+// flush 写出当前片段（moof+mdat）
+func (m *FMP4Muxer) flush() error {
+	m.seq++
+	tracks := []struct {
+		id      uint32
+		samples []fmp4Sample
+	}{{fmp4VideoTrack, m.videoSamples}}
+	if m.audio != nil && len(m.audioSamples) > 0 {
+		tracks = append(tracks, struct {
+			id      uint32
+			samples []fmp4Sample
+		}{fmp4AudioTrack, m.audioSamples})
+	}
+
+	// 先以0偏移生成 moof 计算长度，再填入实际的数据偏移
+	build := func(offsets []int) []byte {
+		trafs := [][]byte{fullBox("mfhd", 0, 0, u32(m.seq))}
+		for i, t := range tracks {
+			trafs = append(trafs, traf(t.id, t.samples, offsets[i]))
+		}
+		return box("moof", trafs...)
+	}
+	offsets := make([]int, len(tracks))
+	moofSize := len(build(offsets))
+
+	var mdat []byte
+	for i, t := range tracks {
+		offsets[i] = moofSize + 8 + len(mdat)
+		for _, s := range t.samples {
+			mdat = append(mdat, s.data...)
+		}
+	}
+
+	m.videoSamples = m.videoSamples[:0]
+	m.audioSamples = m.audioSamples[:0]
+	if _, err := m.w.Write(build(offsets)); err != nil {
+		return err
+	}
+	_, err := m.w.Write(box("mdat", mdat))
+	return err
+}
+
+// traf 生成轨道片段
+func traf(trackID uint32, samples []fmp4Sample, dataOffset int) []byte {
+	const trunFlags = 0x000001 | 0x000100 | 0x000200 | 0x000400 | 0x000800
+	trun := u32(uint32(len(samples)))
+	trun = append(trun, u32(uint32(dataOffset))...)
+	for _, s := range samples {
+		flags := uint32(sampleFlagsNonSync)
+		if s.key {
+			flags = sampleFlagsSync
+		}
+		trun = append(trun, u32(uint32(s.duration))...)
+		trun = append(trun, u32(uint32(len(s.data)))...)
+		trun = append(trun, u32(flags)...)
+		trun = append(trun, u32(uint32(int32(s.cto)))...)
+	}
+
+	return box("traf",
+		fullBox("tfhd", 0, 0x020000, u32(trackID)), // default-base-is-moof
+		fullBox("tfdt", 1, 0, u64(uint64(samples[0].dts))),
+		fullBox("trun", 1, trunFlags, trun),
+	)
+}
+
+// initSegment 生成初始化段（ftyp+moov）
+func (m *FMP4Muxer) initSegment() []byte {
+	ftyp := box("ftyp", []byte("iso5"), u32(0x200), []byte("iso5iso6mp41"))
+
+	nextTrack := uint32(fmp4VideoTrack + 1)
+	traks := [][]byte{mvhd(0), m.videoTrak()}
+	if m.audio != nil {
+		traks = append(traks, m.audioTrak())
+		nextTrack = fmp4AudioTrack + 1
+	}
+	traks[0] = mvhd(nextTrack)
+
+	trex := [][]byte{trexBox(fmp4VideoTrack)}
+	if m.audio != nil {
+		trex = append(trex, trexBox(fmp4AudioTrack))
+	}
+	traks = append(traks, box("mvex", trex...))
+
+	return append(ftyp, box("moov", traks...)...)
+}
+
+// videoTrak 生成视频轨道
+func (m *FMP4Muxer) videoTrak() []byte {
+	v := m.video
+	var entry []byte
+	if v.Codec == CodecH265 {
+		entry = visualSampleEntry("hvc1", v, box("hvcC", hvcC(v)))
+	} else {
+		entry = visualSampleEntry("avc1", v, box("avcC", avcC(v)))
+	}
+	vmhd := fullBox("vmhd", 0, 1, make([]byte, 8))
+	return trak(fmp4VideoTrack, "vide", ClockRate, v.Width, v.Height, vmhd, entry)
+}
+
+// audioTrak 生成音频轨道
+func (m *FMP4Muxer) audioTrak() []byte {
+	a := m.audio
+	entry := make([]byte, 28)
+	binary.BigEndian.PutUint16(entry[6:], 1) // data_reference_index
+	binary.BigEndian.PutUint16(entry[16:], uint16(a.Channels))
+	binary.BigEndian.PutUint16(entry[18:], 16) // samplesize
+	binary.BigEndian.PutUint32(entry[24:], uint32(a.SampleRate)<<16)
+	mp4a := box("mp4a", entry, esds(a))
+	smhd := fullBox("smhd", 0, 0, make([]byte, 4))
+	return trak(fmp4AudioTrack, "soun", a.SampleRate, 0, 0, smhd, mp4a)
+}
+
+// trak 生成轨道（样本表为空，样本都在片段中）
+func trak(trackID uint32, handler string, timescale, width, height int, mediaHeader, sampleEntry []byte) []byte {
+	tkhd := make([]byte, 80)
+	binary.BigEndian.PutUint32(tkhd[8:], trackID)
+	if handler == "soun" {
+		binary.BigEndian.PutUint16(tkhd[32:], 0x0100) // volume
+	}
+	copy(tkhd[36:], unityMatrix())
+	binary.BigEndian.PutUint32(tkhd[72:], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[76:], uint32(height)<<16)
+
+	mdhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mdhd[8:], uint32(timescale))
+	binary.BigEndian.PutUint16(mdhd[16:], 0x55C4) // und
+
+	hdlr := make([]byte, 20)
+	copy(hdlr[4:], handler)
+	hdlr = append(hdlr, "hiksdk\x00"...)
+
+	dref := fullBox("dref", 0, 0, u32(1), fullBox("url ", 0, 1))
+	stbl := box("stbl",
+		fullBox("stsd", 0, 0, u32(1), sampleEntry),
+		fullBox("stts", 0, 0, u32(0)),
+		fullBox("stsc", 0, 0, u32(0)),
+		fullBox("stsz", 0, 0, u32(0), u32(0)),
+		fullBox("stco", 0, 0, u32(0)),
+	)
+
+	return box("trak",
+		fullBox("tkhd", 0, 3, tkhd),
+		box("mdia",
+			fullBox("mdhd", 0, 0, mdhd),
+			fullBox("hdlr", 0, 0, hdlr),
+			box("minf", mediaHeader, box("dinf", dref), stbl),
+		),
+	)
+}
+
+// mvhd 生成影片头
+func mvhd(nextTrackID uint32) []byte {
+	b := make([]byte, 96)
+	binary.BigEndian.PutUint32(b[8:], 1000)        // timescale
+	binary.BigEndian.PutUint32(b[16:], 0x00010000) // rate
+	binary.BigEndian.PutUint16(b[20:], 0x0100)     // volume
+	copy(b[32:], unityMatrix())
+	binary.BigEndian.PutUint32(b[92:], nextTrackID)
+	return fullBox("mvhd", 0, 0, b)
+}
+
+// trexBox 生成轨道片段默认值
+func trexBox(trackID uint32) []byte {
+	return fullBox("trex", 0, 0, u32(trackID), u32(1), u32(0), u32(0), u32(0))
+}
+
+// unityMatrix 单位变换矩阵
+func unityMatrix() []byte {
+	m := make([]byte, 36)
+	binary.BigEndian.PutUint32(m[0:], 0x00010000)
+	binary.BigEndian.PutUint32(m[16:], 0x00010000)
+	binary.BigEndian.PutUint32(m[32:], 0x40000000)
+	return m
+}
+
+// visualSampleEntry 生成视频样本描述
+func visualSampleEntry(typ string, v *VideoConfig, config []byte) []byte {
+	b := make([]byte, 78)
+	binary.BigEndian.PutUint16(b[6:], 1) // data_reference_index
+	binary.BigEndian.PutUint16(b[24:], uint16(v.Width))
+	binary.BigEndian.PutUint16(b[26:], uint16(v.Height))
+	binary.BigEndian.PutUint32(b[28:], 0x00480000) // 72dpi
+	binary.BigEndian.PutUint32(b[32:], 0x00480000)
+	binary.BigEndian.PutUint16(b[40:], 1) // frame_count
+	binary.BigEndian.PutUint16(b[74:], 0x0018)
+	binary.BigEndian.PutUint16(b[76:], 0xFFFF)
+	return box(typ, b, config)
+}
+
+// avcC 生成 H.264 解码配置
+func avcC(v *VideoConfig) []byte {
+	b := []byte{1, v.SPS[1], v.SPS[2], v.SPS[3], 0xFF, 0xE1}
+	b = binary.BigEndian.AppendUint16(b, uint16(len(v.SPS)))
+	b = append(b, v.SPS...)
+	b = append(b, 1)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(v.PPS)))
+	return append(b, v.PPS...)
+}
+
+// hvcC 生成 H.265 解码配置
+func hvcC(v *VideoConfig) []byte {
+	b := []byte{1}
+	b = append(b, v.profileTierLevel...)
+	b = append(b,
+		0xF0, 0x00, // min_spatial_segmentation_idc
+		0xFC,                          // parallelismType
+		0xFC|byte(v.chromaFormat),     // chroma_format_idc
+		0xF8|byte(v.bitDepthLuma-8),   // bit_depth_luma_minus8
+		0xF8|byte(v.bitDepthChroma-8), // bit_depth_chroma_minus8
+		0x00, 0x00,                    // avgFrameRate
+	)
+	nested := byte(0)
+	if v.temporalIDNested {
+		nested = 1
+	}
+	b = append(b, byte(v.maxSubLayers)<<3|nested<<2|0x03, 3)
+	for _, nal := range [][]byte{v.VPS, v.SPS, v.PPS} {
+		b = append(b, 0x80|byte(NALType(CodecH265, nal)))
+		b = binary.BigEndian.AppendUint16(b, 1)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(nal)))
+		b = append(b, nal...)
+	}
+	return b
+}
+
+// esds 生成 AAC 的 ES 描述符
+func esds(a *AudioConfig) []byte {
+	asc := a.specificConfig()
+	decSpecific := append([]byte{0x05, byte(len(asc))}, asc...)
+	decConfig := []byte{0x40, 0x15, 0, 0, 0}
+	decConfig = append(decConfig, make([]byte, 8)...) // maxBitrate、avgBitrate
+	decConfig = append(decConfig, decSpecific...)
+	decConfig = append([]byte{0x04, byte(len(decConfig))}, decConfig...)
+	es := append([]byte{0, 0, 0}, decConfig...)
+	es = append(es, 0x06, 0x01, 0x02) // SLConfigDescriptor
+	es = append([]byte{0x03, byte(len(es))}, es...)
+	return fullBox("esds", 0, 0, es)
+}
+
+// annexBToLengthPrefixed 将 Annex-B 数据转换为4字节长度前缀格式（去掉访问单元分隔符）
+func annexBToLengthPrefixed(codec Codec, data []byte) []byte {
+	out := make([]byte, 0, len(data)+16)
+	for _, nal := range SplitNALUnits(data) {
+		if len(nal) == 0 {
+			continue
+		}
+		if t := NALType(codec, nal); (codec == CodecH264 && t == 9) || (codec == CodecH265 && t == 35) {
+			continue
+		}
+		out = binary.BigEndian.AppendUint32(out, uint32(len(nal)))
+		out = append(out, nal...)
+	}
+	return out
+}
+
+// box 生成 MP4 box
+func box(typ string, payload ...[]byte) []byte {
+	size := 8
+	for _, p := range payload {
+		size += len(p)
+	}
+	b := make([]byte, 8, size)
+	binary.BigEndian.PutUint32(b, uint32(size))
+	copy(b[4:], typ)
+	for _, p := range payload {
+		b = append(b, p...)
+	}
+	return b
+}
+
+// fullBox 生成带版本和标志的 MP4 box
+func fullBox(typ string, version byte, flags uint32, payload ...[]byte) []byte {
+	header := []byte{version, byte(flags >> 16), byte(flags >> 8), byte(flags)}
+	return box(typ, append([][]byte{header}, payload...)...)
+}
+
+func u32(v uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, v)
+}
+
+func u64(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
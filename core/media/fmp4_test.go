@@ -0,0 +1,163 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// mp4Box 解析后的 MP4 box
+type mp4Box struct {
+	typ    string
+	offset int    // 在文件中的偏移
+	body   []byte // 不含8字节头
+}
+
+// parseBoxes 解析一层 box
+func parseBoxes(t *testing.T, data []byte, base int) []mp4Box {
+	t.Helper()
+	var boxes []mp4Box
+	for pos := 0; pos < len(data); {
+		if len(data)-pos < 8 {
+			t.Fatalf("偏移 %d 处 box 头不完整", base+pos)
+		}
+		size := int(binary.BigEndian.Uint32(data[pos:]))
+		if size < 8 || pos+size > len(data) {
+			t.Fatalf("偏移 %d 处 box 长度错误: %d", base+pos, size)
+		}
+		boxes = append(boxes, mp4Box{typ: string(data[pos+4 : pos+8]), offset: base + pos, body: data[pos+8 : pos+size]})
+		pos += size
+	}
+	return boxes
+}
+
+// findBox 按路径查找 box（只解析容器类型的 box）
+func findBox(t *testing.T, b mp4Box, path ...string) mp4Box {
+	t.Helper()
+	for _, typ := range path {
+		found := false
+		for _, child := range parseBoxes(t, b.body, b.offset+8) {
+			if child.typ == typ {
+				b, found = child, true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("%s 中没有 %s", b.typ, typ)
+		}
+	}
+	return b
+}
+
+// fmp4Frames 构造 2 个 GOP 的 H.264 帧（每个 GOP 3帧）及 AAC 音频帧
+func fmp4Frames() []*Frame {
+	sps, pps := h264SPS(), []byte{0x68, 0xCE, 0x3C, 0x80}
+	var frames []*Frame
+	for i := 0; i < 6; i++ {
+		pts := int64(1000 + i*3600)
+		f := &Frame{Codec: CodecH264, PTS: pts + 3600, DTS: pts, Data: annexB([]byte{0x41, byte(i), 0x10})}
+		if i%3 == 0 {
+			f.Keyframe = true
+			f.Data = annexB([]byte{0x09, 0x10}, sps, pps, []byte{0x65, byte(i), 0x20, 0x30})
+		}
+		frames = append(frames, f, &Frame{Codec: CodecAAC, PTS: pts, DTS: pts, Data: adtsFrame(6)})
+	}
+	return frames
+}
+
+// TestFMP4Muxer 检查初始化段和片段的结构、数据偏移和时间
+func TestFMP4Muxer(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewFMP4Muxer(&buf)
+	// 关键帧之前的音频只用于确定音频参数
+	m.WriteFrame(&Frame{Codec: CodecAAC, PTS: 0, DTS: 0, Data: adtsFrame(6)})
+	for _, f := range fmp4Frames() {
+		if err := m.WriteFrame(f); err != nil {
+			t.Fatalf("写入 %s 失败: %v", f, err)
+		}
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("关闭失败: %v", err)
+	}
+
+	data := buf.Bytes()
+	file := mp4Box{typ: "file", offset: -8, body: data}
+	var types []string
+	for _, b := range parseBoxes(t, data, 0) {
+		types = append(types, b.typ)
+	}
+	if want := "ftyp moov moof mdat moof mdat"; strings.Join(types, " ") != want {
+		t.Fatalf("box 顺序错误: %v，期望 %s", types, want)
+	}
+
+	avc1 := findBox(t, file, "moov", "trak", "mdia", "minf", "stbl", "stsd")
+	entry := parseBoxes(t, avc1.body[8:], avc1.offset+16)[0]
+	if entry.typ != "avc1" || binary.BigEndian.Uint16(entry.body[24:]) != 1920 || binary.BigEndian.Uint16(entry.body[26:]) != 1080 {
+		t.Errorf("视频样本描述错误: %s", entry.typ)
+	}
+	avcC := parseBoxes(t, entry.body[78:], entry.offset+86)[0]
+	if avcC.typ != "avcC" || avcC.body[1] != 100 || !bytes.Contains(avcC.body, h264SPS()) {
+		t.Errorf("avcC 错误: % X", avcC.body)
+	}
+	if traks := countBoxes(t, findBox(t, file, "moov"), "trak"); traks != 2 {
+		t.Errorf("应有视频和音频2个轨道，实际: %d", traks)
+	}
+
+	boxes := parseBoxes(t, data, 0)
+	for i, moof := range []mp4Box{boxes[2], boxes[4]} {
+		mdat := boxes[3+2*i]
+		tfdt := findBox(t, moof, "traf", "tfdt")
+		if base := binary.BigEndian.Uint64(tfdt.body[4:]); base != uint64(i*3*3600) {
+			t.Errorf("片段%d的 tfdt=%d，期望 %d", i, base, i*3*3600)
+		}
+
+		trun := findBox(t, moof, "traf", "trun")
+		count := int(binary.BigEndian.Uint32(trun.body[4:]))
+		offset := int(binary.BigEndian.Uint32(trun.body[8:]))
+		if count != 3 || moof.offset+offset != mdat.offset+8 {
+			t.Fatalf("片段%d的 trun 错误: %d个样本，偏移 %d", i, count, offset)
+		}
+		// 第一个样本：时长、关键帧标志、CTS 偏移
+		sample := trun.body[12:]
+		if d := binary.BigEndian.Uint32(sample); d != 3600 {
+			t.Errorf("片段%d的样本时长错误: %d", i, d)
+		}
+		if flags := binary.BigEndian.Uint32(sample[8:]); flags != sampleFlagsSync {
+			t.Errorf("片段%d的第一个样本应为同步样本: %#x", i, flags)
+		}
+		if cto := binary.BigEndian.Uint32(sample[12:]); cto != 3600 {
+			t.Errorf("片段%d的 CTS 偏移错误: %d", i, cto)
+		}
+		// 视频样本转换为长度前缀格式，且去掉了访问单元分隔符
+		size := binary.BigEndian.Uint32(sample[4:])
+		first := mdat.body[:size]
+		if n := binary.BigEndian.Uint32(first); int(n) != len(h264SPS()) || first[4] != 0x67 {
+			t.Errorf("片段%d的关键帧应以 SPS 开头: % X", i, first[:8])
+		}
+		if countBoxes(t, moof, "traf") != 2 {
+			t.Errorf("片段%d应包含音频轨道", i)
+		}
+	}
+}
+
+// TestFMP4MuxerMissingSPS 关键帧缺少参数集时返回错误
+func TestFMP4MuxerMissingSPS(t *testing.T) {
+	m := NewFMP4Muxer(&bytes.Buffer{})
+	err := m.WriteFrame(&Frame{Codec: CodecH264, Keyframe: true, Data: annexB([]byte{0x65, 0x88})})
+	if err == nil {
+		t.Error("缺少参数集时应返回错误")
+	}
+}
+
+// countBoxes 统计直接子 box 中指定类型的个数
+func countBoxes(t *testing.T, b mp4Box, typ string) int {
+	t.Helper()
+	n := 0
+	for _, child := range parseBoxes(t, b.body, b.offset+8) {
+		if child.typ == typ {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,170 @@
+package media
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockRate PS/TS 时间戳的时钟频率（90kHz）
+const ClockRate = 90000
+
+// Codec 编码格式
+type Codec int
+
+const (
+	CodecUnknown   Codec = iota // 未知
+	CodecH264                   // H.264
+	CodecH265                   // H.265
+	CodecAAC                    // AAC（ADTS 封装）
+	CodecG711A                  // G.711 A律
+	CodecG711U                  // G.711 μ律
+	CodecG722                   // G.722.1
+	CodecG726                   // G.726
+	CodecMPEGAudio              // MPEG 音频
+)
+
+// String 返回编码格式名称
+func (c Codec) String() string {
+	switch c {
+	case CodecH264:
+		return "H.264"
+	case CodecH265:
+		return "H.265"
+	case CodecAAC:
+		return "AAC"
+	case CodecG711A:
+		return "G.711A"
+	case CodecG711U:
+		return "G.711U"
+	case CodecG722:
+		return "G.722.1"
+	case CodecG726:
+		return "G.726"
+	case CodecMPEGAudio:
+		return "MPEG Audio"
+	default:
+		return fmt.Sprintf("未知编码(%d)", int(c))
+	}
+}
+
+// IsVideo 是否为视频编码
+func (c Codec) IsVideo() bool {
+	return c == CodecH264 || c == CodecH265
+}
+
+// IsAudio 是否为音频编码
+func (c Codec) IsAudio() bool {
+	return c >= CodecAAC
+}
+
+// Frame 解封装得到的一帧数据
+type Frame struct {
+	Codec    Codec  // 编码格式
+	PTS      int64  // 显示时间戳（90kHz，33位，可能回绕）
+	DTS      int64  // 解码时间戳（90kHz），码流中没有时等于 PTS
+	Keyframe bool   // 是否为关键帧（H.264 IDR / H.265 IRAP），音频帧总是为 true
+	Data     []byte // 视频为 Annex-B 格式的 NAL 单元；音频为原始帧（AAC 为 ADTS）
+}
+
+// Time 返回 PTS 对应的时间
+func (f *Frame) Time() time.Duration {
+	return time.Duration(f.PTS) * time.Second / ClockRate
+}
+
+// String 返回便于调试的帧描述
+func (f *Frame) String() string {
+	key := ""
+	if f.Keyframe && f.Codec.IsVideo() {
+		key = " 关键帧"
+	}
+	return fmt.Sprintf("%s PTS:%d DTS:%d %d字节%s", f.Codec, f.PTS, f.DTS, len(f.Data), key)
+}
+
+// NAL 单元类型（仅列出判断关键帧和参数集需要的类型）
+const (
+	h264NALIDR = 5 // H.264 IDR 图像
+	h264NALSPS = 7 // H.264 序列参数集
+	h264NALPPS = 8 // H.264 图像参数集
+
+	h265NALIRAPMin = 16 // H.265 IRAP 图像（BLA/IDR/CRA）类型下限
+	h265NALIRAPMax = 21 // H.265 IRAP 图像类型上限
+	h265NALVPS     = 32 // H.265 视频参数集
+	h265NALSPS     = 33 // H.265 序列参数集
+	h265NALPPS     = 34 // H.265 图像参数集
+)
+
+// SplitNALUnits 将 Annex-B 格式的数据拆分为 NAL 单元（不含起始码）
+func SplitNALUnits(data []byte) [][]byte {
+	var units [][]byte
+	start := -1
+	for i := 0; i+2 < len(data); {
+		if data[i] != 0 || data[i+1] != 0 || data[i+2] != 1 {
+			i++
+			continue
+		}
+		if start >= 0 {
+			units = append(units, trimZeros(data[start:i]))
+		}
+		i += 3
+		start = i
+	}
+	if start >= 0 && start < len(data) {
+		units = append(units, data[start:])
+	}
+	return units
+}
+
+// trimZeros 去掉 NAL 单元末尾的0（4字节起始码的首字节或尾随零）
+func trimZeros(nal []byte) []byte {
+	for len(nal) > 0 && nal[len(nal)-1] == 0 {
+		nal = nal[:len(nal)-1]
+	}
+	return nal
+}
+
+// NALType 返回 NAL 单元的类型
+func NALType(codec Codec, nal []byte) int {
+	if len(nal) == 0 {
+		return -1
+	}
+	if codec == CodecH265 {
+		return int(nal[0]>>1) & 0x3F
+	}
+	return int(nal[0] & 0x1F)
+}
+
+// isKeyframe 判断 Annex-B 数据是否包含关键帧图像
+func isKeyframe(codec Codec, data []byte) bool {
+	for _, nal := range SplitNALUnits(data) {
+		t := NALType(codec, nal)
+		switch codec {
+		case CodecH264:
+			if t == h264NALIDR {
+				return true
+			}
+		case CodecH265:
+			if t >= h265NALIRAPMin && t <= h265NALIRAPMax {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sniffVideoCodec 根据第一个参数集 NAL 单元猜测视频编码
+// 码流中既没有节目流映射也没有海康媒体头时使用
+func sniffVideoCodec(data []byte) Codec {
+	for _, nal := range SplitNALUnits(data) {
+		if len(nal) < 2 {
+			continue
+		}
+		// H.265 NAL 头为2字节，forbidden_zero_bit 为0且 nuh_layer_id 通常为0
+		if t := int(nal[0]>>1) & 0x3F; nal[0]&0x81 == 0 && (t == h265NALVPS || t == h265NALSPS || t == h265NALPPS) {
+			return CodecH265
+		}
+		if t := int(nal[0] & 0x1F); nal[0]&0x80 == 0 && (t == h264NALSPS || t == h264NALPPS || t == h264NALIDR) {
+			return CodecH264
+		}
+	}
+	return CodecUnknown
+}
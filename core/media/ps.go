@@ -0,0 +1,495 @@
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// HikHeaderSize 海康媒体头（NET_DVR_SYSHEAD 回调的数据）大小
+const HikHeaderSize = 40
+
+// hikMagic 海康媒体头标识
+var hikMagic = []byte("IMKH")
+
+// 海康媒体头中的封装格式（system_format）
+const (
+	HikSystemHik = 0x0001 // 海康私有格式
+	HikSystemPS  = 0x0002 // MPEG-2 PS
+	HikSystemTS  = 0x0003 // MPEG-2 TS
+	HikSystemRTP = 0x0004 // RTP
+)
+
+// 海康媒体头中的视频编码（video_format）
+const (
+	HikVideoHik264 = 0x0001 // 海康H.264
+	HikVideoH265   = 0x0005 // H.265
+	HikVideoH264   = 0x0100 // 标准H.264
+)
+
+// 海康媒体头中的音频编码（audio_format）
+const (
+	HikAudioMPEG   = 0x2000 // MPEG 音频
+	HikAudioAAC    = 0x2001 // AAC
+	HikAudioG711U  = 0x7110 // G.711 μ律
+	HikAudioG711A  = 0x7111 // G.711 A律
+	HikAudioG722_1 = 0x7221 // G.722.1
+	HikAudioG726   = 0x7260 // G.726
+)
+
+// PS 流的起始码（00 00 01 之后的一个字节）
+const (
+	psEndCode       = 0xB9 // 结束码
+	psPackHeader    = 0xBA // 包头
+	psSystemHeader  = 0xBB // 系统头
+	psStreamMap     = 0xBC // 节目流映射（PSM）
+	psPrivateStream = 0xBD // 私有流1（海康私有数据）
+	psAudioMin      = 0xC0 // 音频流ID下限
+	psAudioMax      = 0xDF // 音频流ID上限
+	psVideoMin      = 0xE0 // 视频流ID下限
+	psVideoMax      = 0xEF // 视频流ID上限
+)
+
+// PSM 中的流类型（stream_type）
+const (
+	streamTypeMPEG1Audio = 0x03
+	streamTypeMPEG2Audio = 0x04
+	streamTypeAAC        = 0x0F
+	streamTypeH264       = 0x1B
+	streamTypeH265       = 0x24
+	streamTypeG711A      = 0x90
+	streamTypeG711U      = 0x91
+	streamTypeG722_1     = 0x92
+	streamTypeG726       = 0x96
+)
+
+// maxResync 查找起始码时最多跳过的字节数，超过后认为数据不是 PS 流
+const maxResync = 1 << 20
+
+// ErrNotPS 数据不是 MPEG-2 PS 封装
+var ErrNotPS = errors.New("数据不是 MPEG-2 PS 封装")
+
+// MediaInfo 海康媒体头（HKM_MEDIA_INFO）
+type MediaInfo struct {
+	Version         uint16 // 媒体头版本
+	DeviceID        uint16 // 设备ID
+	SystemFormat    uint16 // 封装格式，见 HikSystem*
+	VideoFormat     uint16 // 视频编码，见 HikVideo*
+	AudioFormat     uint16 // 音频编码，见 HikAudio*
+	AudioChannels   int    // 音频声道数
+	AudioBits       int    // 音频采样位数
+	AudioSampleRate int    // 音频采样率
+	AudioBitrate    int    // 音频码率
+}
+
+// ParseMediaInfo 解析40字节的海康媒体头
+// 参数：
+//   - data: NET_DVR_SYSHEAD 回调的数据
+//
+// 返回值：
+//   - *MediaInfo: 媒体头信息
+//   - error: 数据不足或标识不正确时返回错误
+func ParseMediaInfo(data []byte) (*MediaInfo, error) {
+	if len(data) < HikHeaderSize {
+		return nil, fmt.Errorf("海康媒体头长度不足: %d字节（需要 %d字节）", len(data), HikHeaderSize)
+	}
+	if !bytes.Equal(data[:4], hikMagic) {
+		return nil, fmt.Errorf("无效的海康媒体头标识: % X", data[:4])
+	}
+
+	le := binary.LittleEndian
+	return &MediaInfo{
+		Version:         le.Uint16(data[4:]),
+		DeviceID:        le.Uint16(data[6:]),
+		SystemFormat:    le.Uint16(data[8:]),
+		VideoFormat:     le.Uint16(data[10:]),
+		AudioFormat:     le.Uint16(data[12:]),
+		AudioChannels:   int(data[14]),
+		AudioBits:       int(data[15]),
+		AudioSampleRate: int(le.Uint32(data[16:])),
+		AudioBitrate:    int(le.Uint32(data[20:])),
+	}, nil
+}
+
+// VideoCodec 返回媒体头中的视频编码
+func (m *MediaInfo) VideoCodec() Codec {
+	switch m.VideoFormat {
+	case HikVideoH264, HikVideoHik264:
+		return CodecH264
+	case HikVideoH265:
+		return CodecH265
+	default:
+		return CodecUnknown
+	}
+}
+
+// AudioCodec 返回媒体头中的音频编码
+func (m *MediaInfo) AudioCodec() Codec {
+	switch m.AudioFormat {
+	case HikAudioAAC:
+		return CodecAAC
+	case HikAudioG711A:
+		return CodecG711A
+	case HikAudioG711U:
+		return CodecG711U
+	case HikAudioG722_1:
+		return CodecG722
+	case HikAudioG726:
+		return CodecG726
+	case HikAudioMPEG:
+		return CodecMPEGAudio
+	default:
+		return CodecUnknown
+	}
+}
+
+// Demuxer PS 流解封装器
+// 从 io.Reader（如 preview.Stream）读取海康媒体头和 PS 流，输出带时间戳的音视频帧。
+// 一个视频帧可能被拆分为多个 PES 包，遇到下一帧（PTS 变化）时才能确定当前帧结束，
+// 因此视频帧会延迟一帧输出；读到流末尾时输出最后一帧。
+// 损坏的包（如网络丢包导致的无效 PES 包头）连同正在组装的视频帧一起丢弃，之后从下一个起始码重新同步
+type Demuxer struct {
+	r       *bufio.Reader
+	started bool           // 是否已检查过海康媒体头
+	info    *MediaInfo     // 海康媒体头，没有时为nil
+	streams map[byte]Codec // PES 流ID -> 编码格式（来自PSM）
+	video   *Frame         // 正在组装的视频帧
+	ready   []*Frame       // 已完成、等待输出的帧
+	skipped int64          // 重新同步时跳过的字节数
+	corrupt int64          // 丢弃的损坏包数
+	err     error          // 读取出错后保存错误，已完成的帧输出完后返回
+}
+
+// NewDemuxer 创建 PS 流解封装器
+// 参数：
+//   - r: 数据源，开头可以带40字节的海康媒体头
+func NewDemuxer(r io.Reader) *Demuxer {
+	return &Demuxer{
+		r:       bufio.NewReaderSize(r, 64*1024),
+		streams: make(map[byte]Codec),
+	}
+}
+
+// MediaInfo 返回海康媒体头，码流不带媒体头或尚未读到时返回nil
+func (d *Demuxer) MediaInfo() *MediaInfo {
+	return d.info
+}
+
+// Skipped 返回查找起始码时跳过的无效字节数
+func (d *Demuxer) Skipped() int64 {
+	return d.skipped
+}
+
+// Corrupted 返回因数据损坏而丢弃的包数
+func (d *Demuxer) Corrupted() int64 {
+	return d.corrupt
+}
+
+// ReadFrame 读取下一帧
+// 单个损坏的包不会返回错误（见 Corrupted）；返回错误后解封装结束，之后每次调用都返回同一个错误
+// 返回值：
+//   - *Frame: 音频或视频帧
+//   - error: 读到流末尾时返回 io.EOF；最后一个包不完整时返回 io.ErrUnexpectedEOF
+//     （不完整的最后一帧被丢弃）；数据源读取失败时返回其错误；
+//     海康媒体头声明的不是 PS 封装或长时间找不到起始码时返回 ErrNotPS
+func (d *Demuxer) ReadFrame() (*Frame, error) {
+	for len(d.ready) == 0 {
+		if d.err != nil {
+			return nil, d.err
+		}
+		if err := d.readPacket(); err != nil {
+			d.err = err
+			if errors.Is(err, io.EOF) {
+				d.finishVideo()
+			} else {
+				d.video = nil
+			}
+		}
+	}
+
+	frame := d.ready[0]
+	d.ready = d.ready[1:]
+	return frame, nil
+}
+
+// readPacket 读取并处理一个 PS 包（或海康媒体头）
+func (d *Demuxer) readPacket() error {
+	if !d.started {
+		d.started = true
+		if err := d.readMediaInfo(); err != nil {
+			return err
+		}
+	}
+
+	id, err := d.nextStartCode()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case id == psEndCode:
+		return nil
+	case id == psPackHeader:
+		return d.skipPackHeader()
+	case id == psStreamMap:
+		body, err := d.readBody()
+		if err != nil {
+			return err
+		}
+		if err := d.parseStreamMap(body); err != nil {
+			d.corrupt++ // 沿用之前的流映射
+		}
+		return nil
+	case id >= psAudioMin && id <= psVideoMax:
+		body, err := d.readBody()
+		if err != nil {
+			return err
+		}
+		if err := d.handlePES(id, body); err != nil {
+			// 正在组装的视频帧缺少这部分数据，一并丢弃
+			d.corrupt++
+			d.video = nil
+		}
+		return nil
+	case id > psEndCode:
+		// 系统头、私有流、填充流等带长度字段的包直接跳过
+		_, err := d.readBody()
+		return err
+	default:
+		// PS 流中不应出现的起始码（如裸 NAL 单元），重新同步
+		return nil
+	}
+}
+
+// readMediaInfo 读取开头的海康媒体头（如果有）
+func (d *Demuxer) readMediaInfo() error {
+	magic, err := d.r.Peek(len(hikMagic))
+	if err != nil || !bytes.Equal(magic, hikMagic) {
+		return nil
+	}
+
+	header := make([]byte, HikHeaderSize)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return unexpectedEOF(err)
+	}
+	info, err := ParseMediaInfo(header)
+	if err != nil {
+		return err
+	}
+	if info.SystemFormat != HikSystemPS {
+		return fmt.Errorf("%w（海康媒体头封装格式: 0x%04X）", ErrNotPS, info.SystemFormat)
+	}
+	d.info = info
+	return nil
+}
+
+// nextStartCode 查找下一个 00 00 01 起始码，返回其后的流ID
+func (d *Demuxer) nextStartCode() (byte, error) {
+	var skipped int64
+	for {
+		b, err := d.r.Peek(4)
+		if len(b) < 4 {
+			if err == nil {
+				err = io.EOF
+			}
+			d.skipped += skipped + int64(len(b))
+			d.r.Discard(len(b))
+			return 0, err
+		}
+		if b[0] == 0 && b[1] == 0 && b[2] == 1 {
+			d.skipped += skipped
+			d.r.Discard(4)
+			return b[3], nil
+		}
+
+		d.r.Discard(1)
+		skipped++
+		if skipped > maxResync {
+			return 0, fmt.Errorf("%w（%d字节内未找到起始码）", ErrNotPS, maxResync)
+		}
+	}
+}
+
+// skipPackHeader 跳过包头（起始码之后的部分）
+func (d *Demuxer) skipPackHeader() error {
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return unexpectedEOF(err)
+	}
+	if header[0]&0xC0 != 0x40 {
+		// 不是 MPEG-2 包头（数据损坏或 MPEG-1 封装），从下一个起始码重新同步
+		d.corrupt++
+		return nil
+	}
+
+	// pack_stuffing_length
+	stuffing := int(header[9] & 0x07)
+	if _, err := d.r.Discard(stuffing); err != nil {
+		return unexpectedEOF(err)
+	}
+	return nil
+}
+
+// readBody 读取长度字段及其后的包内容
+func (d *Demuxer) readBody() ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	body := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(d.r, body); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return body, nil
+}
+
+// parseStreamMap 解析节目流映射，记录各流的编码格式
+func (d *Demuxer) parseStreamMap(body []byte) error {
+	if len(body) < 4 {
+		return fmt.Errorf("节目流映射长度不足: %d字节", len(body))
+	}
+	infoLen := int(binary.BigEndian.Uint16(body[2:]))
+	pos := 4 + infoLen
+	if pos+2 > len(body) {
+		return fmt.Errorf("节目流映射长度不足: %d字节", len(body))
+	}
+	mapLen := int(binary.BigEndian.Uint16(body[pos:]))
+	pos += 2
+
+	end := min(pos+mapLen, len(body))
+	for pos+4 <= end {
+		streamType, id := body[pos], body[pos+1]
+		esInfoLen := int(binary.BigEndian.Uint16(body[pos+2:]))
+		if codec := codecFromStreamType(streamType); codec != CodecUnknown {
+			d.streams[id] = codec
+		}
+		pos += 4 + esInfoLen
+	}
+	return nil
+}
+
+// handlePES 处理音视频 PES 包
+func (d *Demuxer) handlePES(id byte, body []byte) error {
+	if len(body) < 3 || body[0]&0xC0 != 0x80 {
+		return fmt.Errorf("无效的 PES 包头（流ID: 0x%02X）", id)
+	}
+	flags := body[1]
+	headerLen := int(body[2])
+	if 3+headerLen > len(body) {
+		return fmt.Errorf("PES 包头长度越界（流ID: 0x%02X）", id)
+	}
+	optional := body[3 : 3+headerLen]
+	payload := body[3+headerLen:]
+
+	var pts, dts int64
+	hasPTS := flags&0x80 != 0
+	if hasPTS {
+		if len(optional) < 5 {
+			return fmt.Errorf("PES 时间戳长度不足（流ID: 0x%02X）", id)
+		}
+		pts = parseTimestamp(optional)
+		dts = pts
+		if flags&0x40 != 0 && len(optional) >= 10 {
+			dts = parseTimestamp(optional[5:])
+		}
+	}
+
+	if id >= psVideoMin {
+		d.pushVideo(id, hasPTS, pts, dts, payload)
+		return nil
+	}
+
+	// 音频 PES 包通常包含完整的音频帧，直接输出
+	if len(payload) == 0 || !hasPTS {
+		return nil
+	}
+	codec := d.streams[id]
+	if codec == CodecUnknown && d.info != nil {
+		codec = d.info.AudioCodec()
+	}
+	d.ready = append(d.ready, &Frame{
+		Codec:    codec,
+		PTS:      pts,
+		DTS:      dts,
+		Keyframe: true,
+		Data:     payload,
+	})
+	return nil
+}
+
+// pushVideo 将视频 PES 数据追加到当前帧，PTS 变化时输出上一帧
+func (d *Demuxer) pushVideo(id byte, hasPTS bool, pts, dts int64, payload []byte) {
+	if hasPTS && d.video != nil && d.video.PTS != pts {
+		d.finishVideo()
+	}
+	if d.video == nil {
+		if !hasPTS {
+			// 从帧中间开始接收，无法确定时间戳，丢弃
+			return
+		}
+		codec := d.streams[id]
+		if codec == CodecUnknown && d.info != nil {
+			codec = d.info.VideoCodec()
+		}
+		d.video = &Frame{Codec: codec, PTS: pts, DTS: dts}
+	}
+	d.video.Data = append(d.video.Data, payload...)
+}
+
+// finishVideo 完成当前视频帧
+func (d *Demuxer) finishVideo() {
+	frame := d.video
+	d.video = nil
+	if frame == nil || len(frame.Data) == 0 {
+		return
+	}
+	if frame.Codec == CodecUnknown {
+		frame.Codec = sniffVideoCodec(frame.Data)
+	}
+	frame.Keyframe = isKeyframe(frame.Codec, frame.Data)
+	d.ready = append(d.ready, frame)
+}
+
+// codecFromStreamType 将 PSM 流类型转换为编码格式
+func codecFromStreamType(streamType byte) Codec {
+	switch streamType {
+	case streamTypeH264:
+		return CodecH264
+	case streamTypeH265:
+		return CodecH265
+	case streamTypeAAC:
+		return CodecAAC
+	case streamTypeG711A:
+		return CodecG711A
+	case streamTypeG711U:
+		return CodecG711U
+	case streamTypeG722_1:
+		return CodecG722
+	case streamTypeG726:
+		return CodecG726
+	case streamTypeMPEG1Audio, streamTypeMPEG2Audio:
+		return CodecMPEGAudio
+	default:
+		return CodecUnknown
+	}
+}
+
+// parseTimestamp 解析 PES 包头中5字节的 PTS/DTS
+func parseTimestamp(b []byte) int64 {
+	return int64(b[0]>>1&0x07)<<30 |
+		int64(b[1])<<22 |
+		int64(b[2]>>1)<<15 |
+		int64(b[3])<<7 |
+		int64(b[4]>>1)
+}
+
+// unexpectedEOF 包内容不完整时将 io.EOF 转换为 io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,274 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"testing/iotest"
+)
+
+// readSample 读取 testdata 中的样例文件（由 testdata/gen.go 生成）
+//
+// 注意：样例是按解封装器所依据的同一份格式理解合成的，并非设备录制的码流，
+// 因此只能验证解封装逻辑自洽（拆包组帧、重新同步、截断等），无法发现对格式本身的误读，
+// 包括：海康媒体头各字段的实际偏移和取值、设备实际发送的节目流映射（描述符、CRC）、
+// 包头填充字节、接近 64KB 的 PES 包、私有数据包的内容、SEI/AUD 等真实 NAL 单元以及时间戳回绕。
+// 设备实录片段尚未补充：将 H.264 和 H.265 设备的实时预览数据分别保存为
+// testdata/recorded_h264.ps 和 testdata/recorded_h265.ps 后，TestDemuxRecorded 会自动对其校验
+func readSample(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("读取样例文件失败: %v", err)
+	}
+	return data
+}
+
+// readAll 读取全部帧，返回最后的错误
+func readAll(d *Demuxer) ([]*Frame, error) {
+	var frames []*Frame
+	for {
+		f, err := d.ReadFrame()
+		if err != nil {
+			return frames, err
+		}
+		frames = append(frames, f)
+	}
+}
+
+// split 按音视频拆分帧
+func split(frames []*Frame) (video, audio []*Frame) {
+	for _, f := range frames {
+		if f.Codec.IsVideo() {
+			video = append(video, f)
+		} else {
+			audio = append(audio, f)
+		}
+	}
+	return video, audio
+}
+
+// TestDemuxH264Sample 解析带海康媒体头的 H.264 + G.711A 样例
+func TestDemuxH264Sample(t *testing.T) {
+	d := NewDemuxer(bytes.NewReader(readSample(t, "h264.ps")))
+	frames, err := readAll(d)
+	if err != io.EOF {
+		t.Fatalf("应以 io.EOF 结束，实际: %v", err)
+	}
+
+	info := d.MediaInfo()
+	if info == nil || info.VideoCodec() != CodecH264 || info.AudioCodec() != CodecG711A || info.AudioSampleRate != 8000 {
+		t.Fatalf("海康媒体头解析错误: %+v", info)
+	}
+
+	video, audio := split(frames)
+	if len(video) != 5 || len(audio) != 5 {
+		t.Fatalf("应有5个视频帧和5个音频帧，实际: %d/%d", len(video), len(audio))
+	}
+
+	sizes := []int{4 + 13 + 4 + 5 + 4 + 3001, 4 + 901, 4 + 801, 4 + 701, 4 + 13 + 4 + 5 + 4 + 2001}
+	keys := []bool{true, false, false, false, true}
+	for i, f := range video {
+		if f.Codec != CodecH264 || f.Keyframe != keys[i] || len(f.Data) != sizes[i] {
+			t.Errorf("视频帧%d错误: %s，期望 %d字节 关键帧=%v", i, f, sizes[i], keys[i])
+		}
+		if pts := int64(900000 + i*3600); f.PTS != pts || f.DTS != pts-3600 {
+			t.Errorf("视频帧%d时间戳错误: PTS=%d DTS=%d", i, f.PTS, f.DTS)
+		}
+	}
+	if !bytes.HasPrefix(video[0].Data, []byte{0, 0, 0, 1, 0x67}) {
+		t.Errorf("视频帧应为 Annex-B 格式: % X", video[0].Data[:8])
+	}
+	for i, f := range audio {
+		if f.Codec != CodecG711A || len(f.Data) != 320 || f.PTS != int64(900000+i*3600) || f.PTS != f.DTS {
+			t.Errorf("音频帧%d错误: %s", i, f)
+		}
+	}
+	if d.Skipped() != 0 {
+		t.Errorf("完整的样例不应跳过数据，实际跳过 %d字节", d.Skipped())
+	}
+}
+
+// TestDemuxH265Sample 解析不带海康媒体头的 H.265 样例，编码格式来自节目流映射
+func TestDemuxH265Sample(t *testing.T) {
+	d := NewDemuxer(bytes.NewReader(readSample(t, "h265.ps")))
+	frames, err := readAll(d)
+	if err != io.EOF {
+		t.Fatalf("应以 io.EOF 结束，实际: %v", err)
+	}
+	if d.MediaInfo() != nil {
+		t.Error("没有海康媒体头时 MediaInfo 应为nil")
+	}
+
+	keys := []bool{true, false, true}
+	if len(frames) != len(keys) {
+		t.Fatalf("应有3帧，实际: %v", frames)
+	}
+	for i, f := range frames {
+		if f.Codec != CodecH265 || f.Keyframe != keys[i] || f.PTS != int64(3600*(i+1)) {
+			t.Errorf("帧%d错误: %s", i, f)
+		}
+	}
+
+	var types []int
+	for _, nal := range SplitNALUnits(frames[0].Data) {
+		types = append(types, NALType(CodecH265, nal))
+	}
+	if want := []int{32, 33, 34, 19}; len(types) != 4 || types[0] != want[0] || types[3] != want[3] {
+		t.Errorf("NAL 单元类型错误: %v，期望 %v", types, want)
+	}
+}
+
+// TestDemuxRecorded 解析设备实录片段（文件不存在时跳过）
+func TestDemuxRecorded(t *testing.T) {
+	for name, codec := range map[string]Codec{
+		"recorded_h264.ps": CodecH264,
+		"recorded_h265.ps": CodecH265,
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile("testdata/" + name)
+			if errors.Is(err, os.ErrNotExist) {
+				t.Skipf("缺少设备实录片段 %s", name)
+			}
+			if err != nil {
+				t.Fatalf("读取实录片段失败: %v", err)
+			}
+
+			d := NewDemuxer(bytes.NewReader(data))
+			frames, err := readAll(d)
+			if err != io.EOF && err != io.ErrUnexpectedEOF {
+				t.Fatalf("解析实录片段出错: %v", err)
+			}
+			if n := d.Corrupted(); n != 0 {
+				t.Errorf("实录片段中有 %d 个包被判定为损坏", n)
+			}
+
+			video, _ := split(frames)
+			if len(video) == 0 || !video[0].Keyframe {
+				t.Fatalf("实录片段应以关键帧开头，共 %d 个视频帧", len(video))
+			}
+			var last int64 = -1
+			for i, f := range video {
+				if f.Codec != codec {
+					t.Fatalf("视频帧%d编码为 %s，期望 %s", i, f.Codec, codec)
+				}
+				if f.DTS < last {
+					t.Errorf("视频帧%d的DTS回退: %d < %d", i, f.DTS, last)
+				}
+				last = f.DTS
+			}
+		})
+	}
+}
+
+// TestDemuxChunked 数据被任意切分（如逐字节到达）时结果相同
+func TestDemuxChunked(t *testing.T) {
+	data := readSample(t, "h264.ps")
+	want, _ := readAll(NewDemuxer(bytes.NewReader(data)))
+	got, err := readAll(NewDemuxer(iotest.OneByteReader(bytes.NewReader(data))))
+	if err != io.EOF || len(got) != len(want) {
+		t.Fatalf("逐字节读取结果不同: %d帧 %v，期望 %d帧", len(got), err, len(want))
+	}
+	for i := range want {
+		if got[i].PTS != want[i].PTS || !bytes.Equal(got[i].Data, want[i].Data) {
+			t.Errorf("帧%d不同: %s，期望 %s", i, got[i], want[i])
+		}
+	}
+}
+
+// TestDemuxResync 开头有无效数据时跳过并重新同步
+func TestDemuxResync(t *testing.T) {
+	garbage := []byte{0x12, 0x34, 0x00, 0x00, 0x56, 0x00}
+	data := append(garbage, readSample(t, "h265.ps")...)
+
+	d := NewDemuxer(bytes.NewReader(data))
+	frames, err := readAll(d)
+	if err != io.EOF || len(frames) != 3 {
+		t.Fatalf("重新同步后应得到3帧，实际: %d帧 %v", len(frames), err)
+	}
+	if d.Skipped() != int64(len(garbage)) {
+		t.Errorf("应跳过 %d字节，实际: %d", len(garbage), d.Skipped())
+	}
+}
+
+// TestDemuxCorruptPES 损坏的 PES 包连同正在组装的帧被丢弃，之后的帧继续输出
+func TestDemuxCorruptPES(t *testing.T) {
+	data := readSample(t, "h265.ps")
+	want, _ := readAll(NewDemuxer(bytes.NewReader(data)))
+
+	// 破坏第二帧（样例中第3个视频 PES 包，单独成帧）的包头标志
+	pos := -1
+	for range 3 {
+		next := bytes.Index(data[pos+1:], []byte{0x00, 0x00, 0x01, 0xE0})
+		if next < 0 {
+			t.Fatal("样例中的视频 PES 包不足3个")
+		}
+		pos += next + 1
+	}
+	data = bytes.Clone(data)
+	data[pos+6] = 0x00
+
+	d := NewDemuxer(bytes.NewReader(data))
+	frames, err := readAll(d)
+	if err != io.EOF {
+		t.Fatalf("损坏的包不应结束解封装，实际: %v", err)
+	}
+	if d.Corrupted() != 1 {
+		t.Errorf("应丢弃1个损坏的包，实际: %d", d.Corrupted())
+	}
+	// 第一帧在遇到第二帧时才完成组装，与第二帧一起丢弃
+	want = want[2:]
+	if len(frames) != len(want) {
+		t.Fatalf("应只输出第三帧，实际: %v", frames)
+	}
+	for i, f := range frames {
+		if f.PTS != want[i].PTS || !bytes.Equal(f.Data, want[i].Data) {
+			t.Errorf("帧%d不同: %s，期望 %s", i, f, want[i])
+		}
+	}
+}
+
+// TestDemuxTruncated 最后一个包不完整时输出已完成的帧，再返回 io.ErrUnexpectedEOF
+func TestDemuxTruncated(t *testing.T) {
+	data := readSample(t, "h265.ps")
+	frames, err := readAll(NewDemuxer(bytes.NewReader(data[:len(data)-100])))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("应返回 io.ErrUnexpectedEOF，实际: %v", err)
+	}
+	if len(frames) != 2 {
+		t.Errorf("应输出前2个完整的帧，实际: %v", frames)
+	}
+}
+
+// TestDemuxNotPS 海康媒体头声明的封装格式不是 PS 时返回错误
+func TestDemuxNotPS(t *testing.T) {
+	header := readSample(t, "h264.ps")[:HikHeaderSize]
+	header[8] = 0x04 // RTP
+	_, err := NewDemuxer(bytes.NewReader(header)).ReadFrame()
+	if !errors.Is(err, ErrNotPS) {
+		t.Errorf("应返回 ErrNotPS，实际: %v", err)
+	}
+
+	if _, err := ParseMediaInfo([]byte("IMKH")); err == nil {
+		t.Error("长度不足的媒体头应返回错误")
+	}
+}
+
+// TestSniffVideoCodec 没有节目流映射和媒体头时根据参数集猜测编码
+func TestSniffVideoCodec(t *testing.T) {
+	cases := []struct {
+		data []byte
+		want Codec
+	}{
+		{[]byte{0, 0, 0, 1, 0x67, 0x42, 0, 0, 1, 0x68, 0xCE}, CodecH264},
+		{[]byte{0, 0, 0, 1, 0x40, 0x01, 0x0C, 0, 0, 1, 0x42, 0x01}, CodecH265},
+		{[]byte{0, 0, 1, 0x41, 0x9A}, CodecUnknown},
+	}
+	for _, c := range cases {
+		if got := sniffVideoCodec(c.data); got != c.want {
+			t.Errorf("sniffVideoCodec(% X) = %s，期望 %s", c.data, got, c.want)
+		}
+	}
+}
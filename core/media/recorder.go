@@ -0,0 +1,234 @@
+package media
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Format 录像文件格式
+type Format int
+
+const (
+	FormatFMP4 Format = iota // 分片 MP4（.mp4）
+	FormatTS                 // MPEG-TS（.ts）
+)
+
+// Ext 返回文件扩展名
+func (f Format) Ext() string {
+	if f == FormatTS {
+		return ".ts"
+	}
+	return ".mp4"
+}
+
+// ErrRecorderClosed 录像已结束
+var ErrRecorderClosed = errors.New("录像已结束")
+
+// RecordOptions 录像参数
+type RecordOptions struct {
+	Dir          string             // 输出目录，不存在时自动创建
+	Prefix       string             // 文件名前缀，默认为 "record"
+	Format       Format             // 文件格式
+	MaxDuration  time.Duration      // 单个文件的最大时长，0表示不限
+	MaxSize      int64              // 单个文件的最大字节数，0表示不限
+	OnFileClosed func(f RecordFile) // 文件完成时的回调（可选，持有锁调用，不能在回调中调用 Recorder 的方法）
+}
+
+// RecordFile 一个已完成的录像文件
+type RecordFile struct {
+	Path     string        // 文件路径
+	Start    time.Time     // 开始录制的时间
+	Duration time.Duration // 录像时长（按视频时间戳计算）
+	Size     int64         // 文件大小
+	Frames   int           // 写入的视频帧数
+}
+
+// Recorder 录像写入器
+// 从第一个视频关键帧开始写入，达到时长或大小上限后在下一个关键帧处切换新文件，
+// 因此每个文件都以关键帧开头，可以单独播放；Close 完成当前文件。
+// 所有方法都是并发安全的
+type Recorder struct {
+	mu     sync.Mutex
+	opts   RecordOptions
+	seq    int      // 文件序号
+	file   *os.File // 当前文件，未开始时为nil
+	buf    *bufio.Writer
+	count  *countWriter
+	muxer  Muxer
+	cur    RecordFile   // 当前文件的信息
+	first  int64        // 当前文件第一帧的 DTS
+	last   int64        // 当前文件最后一帧的 DTS
+	files  []RecordFile // 已完成的文件
+	closed bool
+}
+
+// countWriter 统计写入字节数
+type countWriter struct {
+	w *os.File
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// NewRecorder 创建录像写入器
+// 参数：
+//   - opts: 录像参数
+//
+// 返回值：
+//   - *Recorder: 录像写入器
+//   - error: 错误信息，成功时为nil
+func NewRecorder(opts RecordOptions) (*Recorder, error) {
+	if opts.MaxDuration < 0 || opts.MaxSize < 0 {
+		return nil, fmt.Errorf("无效的切分参数: 时长 %v，大小 %d", opts.MaxDuration, opts.MaxSize)
+	}
+	if opts.Format != FormatFMP4 && opts.Format != FormatTS {
+		return nil, fmt.Errorf("无效的录像格式: %d", opts.Format)
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "record"
+	}
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建录像目录失败: %w", err)
+	}
+	return &Recorder{opts: opts}, nil
+}
+
+// WriteFrame 写入一帧
+// 第一个视频关键帧之前的帧被忽略
+func (r *Recorder) WriteFrame(f *Frame) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrRecorderClosed
+	}
+	if f.Codec.IsVideo() && f.Keyframe {
+		if r.file != nil && r.shouldRotate(f) {
+			if err := r.finish(); err != nil {
+				return err
+			}
+		}
+		if r.file == nil {
+			if err := r.open(f); err != nil {
+				return err
+			}
+		}
+	}
+	if r.file == nil {
+		return nil
+	}
+
+	if err := r.muxer.WriteFrame(f); err != nil {
+		return err
+	}
+	if f.Codec.IsVideo() {
+		r.cur.Frames++
+		r.last = f.DTS
+	}
+	return nil
+}
+
+// shouldRotate 是否达到切分条件（调用方必须持有锁）
+func (r *Recorder) shouldRotate(f *Frame) bool {
+	if r.opts.MaxSize > 0 && r.count.n+int64(r.buf.Buffered()) >= r.opts.MaxSize {
+		return true
+	}
+	if r.opts.MaxDuration > 0 {
+		elapsed := time.Duration(tsDelta(f.DTS, r.first)) * time.Second / ClockRate
+		return elapsed >= r.opts.MaxDuration
+	}
+	return false
+}
+
+// open 以关键帧 f 开始一个新文件（调用方必须持有锁）
+func (r *Recorder) open(f *Frame) error {
+	now := time.Now()
+	r.seq++
+	name := fmt.Sprintf("%s_%s_%03d%s", r.opts.Prefix, now.Format("20060102_150405"), r.seq, r.opts.Format.Ext())
+	path := filepath.Join(r.opts.Dir, name)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("创建录像文件失败: %w", err)
+	}
+	r.file = file
+	r.count = &countWriter{w: file}
+	r.buf = bufio.NewWriterSize(r.count, 256*1024)
+	if r.opts.Format == FormatTS {
+		r.muxer = NewTSMuxer(r.buf)
+	} else {
+		r.muxer = NewFMP4Muxer(r.buf)
+	}
+	r.cur = RecordFile{Path: path, Start: now}
+	r.first, r.last = f.DTS, f.DTS
+	return nil
+}
+
+// finish 完成当前文件：写出缓存、同步到磁盘并关闭（调用方必须持有锁）
+func (r *Recorder) finish() error {
+	err := r.muxer.Close()
+	if e := r.buf.Flush(); err == nil {
+		err = e
+	}
+	if e := r.file.Sync(); err == nil {
+		err = e
+	}
+	if e := r.file.Close(); err == nil {
+		err = e
+	}
+
+	info := r.cur
+	info.Size = r.count.n
+	if info.Frames > 0 {
+		// 最后一帧按一帧的时长计算
+		frame := time.Duration(tsDelta(r.last, r.first)) * time.Second / ClockRate / time.Duration(max(info.Frames-1, 1))
+		info.Duration = time.Duration(tsDelta(r.last, r.first))*time.Second/ClockRate + frame
+	}
+	r.file, r.buf, r.count, r.muxer = nil, nil, nil, nil
+	if err != nil {
+		return fmt.Errorf("完成录像文件 %s 失败: %w", info.Path, err)
+	}
+
+	r.files = append(r.files, info)
+	if r.opts.OnFileClosed != nil {
+		r.opts.OnFileClosed(info)
+	}
+	return nil
+}
+
+// Files 返回已完成的录像文件
+func (r *Recorder) Files() []RecordFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordFile(nil), r.files...)
+}
+
+// Close 完成当前文件并结束录像
+// 重复调用是安全的
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if r.file == nil {
+		return nil
+	}
+	return r.finish()
+}
@@ -0,0 +1,106 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRecorderRotateByDuration 达到时长上限后在下一个关键帧处切换文件
+func TestRecorderRotateByDuration(t *testing.T) {
+	var closed []RecordFile
+	rec, err := NewRecorder(RecordOptions{
+		Dir:          filepath.Join(t.TempDir(), "ch1"),
+		Prefix:       "ch1",
+		Format:       FormatTS,
+		MaxDuration:  100 * time.Millisecond,
+		OnFileClosed: func(f RecordFile) { closed = append(closed, f) },
+	})
+	if err != nil {
+		t.Fatalf("创建录像失败: %v", err)
+	}
+
+	// 样例：5帧（40ms间隔），第0帧和第4帧为关键帧
+	muxSample(t, rec, "h264.ps")
+	if err := rec.WriteFrame(&Frame{Codec: CodecH264}); !errors.Is(err, ErrRecorderClosed) {
+		t.Errorf("关闭后写入应返回 ErrRecorderClosed，实际: %v", err)
+	}
+
+	files := rec.Files()
+	if len(files) != 2 || len(closed) != 2 {
+		t.Fatalf("应切分为2个文件，实际: %+v", files)
+	}
+	if files[0].Frames != 4 || files[1].Frames != 1 || files[0].Duration != 160*time.Millisecond {
+		t.Errorf("文件信息错误: %+v", files)
+	}
+	for _, f := range files {
+		info, err := os.Stat(f.Path)
+		if err != nil || info.Size() != f.Size || f.Size%tsPacketSize != 0 {
+			t.Errorf("文件 %s 大小错误: %d %v", f.Path, f.Size, err)
+		}
+		if name := filepath.Base(f.Path); !strings.HasPrefix(name, "ch1_") || filepath.Ext(name) != ".ts" {
+			t.Errorf("文件名错误: %s", name)
+		}
+	}
+	// 每个文件都以 PAT 开头，可以单独播放
+	data, _ := os.ReadFile(files[1].Path)
+	if packets := parseTS(t, data); packets[0].pid != tsPIDPAT {
+		t.Error("切分后的文件应以 PAT 开头")
+	}
+}
+
+// TestRecorderRotateBySize 达到大小上限后切换文件，fMP4 每个文件都有初始化段
+func TestRecorderRotateBySize(t *testing.T) {
+	rec, err := NewRecorder(RecordOptions{Dir: t.TempDir(), Format: FormatFMP4, MaxSize: 1})
+	if err != nil {
+		t.Fatalf("创建录像失败: %v", err)
+	}
+	for _, f := range fmp4Frames() {
+		if err := rec.WriteFrame(f); err != nil {
+			t.Fatalf("写入 %s 失败: %v", f, err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("关闭失败: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Errorf("重复关闭不应报错: %v", err)
+	}
+
+	files := rec.Files()
+	if len(files) != 2 {
+		t.Fatalf("每个 GOP 应切分为一个文件，实际: %+v", files)
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f.Path)
+		if err != nil || !bytes.Equal(data[4:8], []byte("ftyp")) || filepath.Ext(f.Path) != ".mp4" {
+			t.Errorf("文件 %s 应以 ftyp 开头: %v", f.Path, err)
+		}
+		if f.Frames != 3 {
+			t.Errorf("文件 %s 应有3帧，实际: %d", f.Path, f.Frames)
+		}
+	}
+}
+
+// TestRecorderInvalidOptions 无效参数返回错误
+func TestRecorderInvalidOptions(t *testing.T) {
+	if _, err := NewRecorder(RecordOptions{Dir: t.TempDir(), MaxSize: -1}); err == nil {
+		t.Error("负数的大小上限应返回错误")
+	}
+	if _, err := NewRecorder(RecordOptions{Dir: t.TempDir(), Format: Format(9)}); err == nil {
+		t.Error("无效的格式应返回错误")
+	}
+
+	// 没有关键帧时不创建文件
+	dir := t.TempDir()
+	rec, _ := NewRecorder(RecordOptions{Dir: dir})
+	rec.WriteFrame(&Frame{Codec: CodecH264, Data: []byte{0, 0, 1, 0x41}})
+	rec.Close()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 || len(rec.Files()) != 0 {
+		t.Errorf("没有关键帧时不应创建文件: %v", entries)
+	}
+}
@@ -0,0 +1,226 @@
+//go:build ignore
+
+// gen 生成 PS 解封装测试用的样例文件
+//
+// 样例按海康设备实时预览的数据格式构造：开头为40字节的海康媒体头（h264.ps），
+// 关键帧前带包头、系统头和节目流映射，大帧拆分为多个 PES 包且只有第一个包带 PTS，
+// 并穿插音频 PES 包和海康私有数据包。
+// 样例是合成的，并非设备实录，覆盖范围见 ps_test.go 中 readSample 的说明；
+// 设备实录片段（recorded_*.ps）不由本程序生成，需从设备保存后放入本目录。
+//
+// 用法：
+//
+//	cd core/media/testdata && go run gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"os"
+)
+
+// maxPayload 每个 PES 包的最大负载（设备实际使用约 64KB，这里取小值以覆盖拆包逻辑）
+const maxPayload = 1400
+
+// frameDuration 25fps 的帧间隔（90kHz）
+const frameDuration = 3600
+
+type frame struct {
+	nals [][]byte
+	key  bool
+}
+
+func main() {
+	writeH264()
+	writeH265()
+}
+
+// writeH264 H.264 + G.711A，带海康媒体头
+func writeH264() {
+	var buf bytes.Buffer
+	buf.Write(hikHeader(0x0100, 0x7111))
+
+	frames := []frame{
+		{nals: [][]byte{nal(0x67, 12), nal(0x68, 4), nal(0x65, 3000)}, key: true},
+		{nals: [][]byte{nal(0x41, 900)}},
+		{nals: [][]byte{nal(0x41, 800)}},
+		{nals: [][]byte{nal(0x41, 700)}},
+		{nals: [][]byte{nal(0x67, 12), nal(0x68, 4), nal(0x65, 2000)}, key: true},
+	}
+	pts := int64(900000)
+	for i, f := range frames {
+		writeFrame(&buf, 0x1B, 0x90, f, pts)
+		// 每帧之后一个 G.711 音频包（40ms，320字节）
+		pack(&buf, pts)
+		pes(&buf, 0xC0, pts, -1, payload(320, byte(i)))
+		if i == 2 {
+			private(&buf)
+		}
+		pts += frameDuration
+	}
+	write("h264.ps", buf.Bytes())
+}
+
+// writeH265 H.265，无海康媒体头，视频 PES 带 DTS
+func writeH265() {
+	var buf bytes.Buffer
+
+	frames := []frame{
+		{nals: [][]byte{nal2(32, 20), nal2(33, 30), nal2(34, 6), nal2(19, 2500)}, key: true},
+		{nals: [][]byte{nal2(1, 600)}},
+		{nals: [][]byte{nal2(21, 1500)}, key: true},
+	}
+	pts := int64(3600)
+	for _, f := range frames {
+		writeFrame(&buf, 0x24, 0, f, pts)
+		pts += frameDuration
+	}
+	buf.Write([]byte{0, 0, 1, 0xB9})
+	write("h265.ps", buf.Bytes())
+}
+
+// writeFrame 写入一帧视频：包头、（关键帧）系统头和PSM、拆分后的 PES 包
+func writeFrame(buf *bytes.Buffer, videoType, audioType byte, f frame, pts int64) {
+	pack(buf, pts)
+	if f.key {
+		systemHeader(buf)
+		streamMap(buf, videoType, audioType)
+	}
+
+	var es []byte
+	for _, n := range f.nals {
+		es = append(es, 0, 0, 0, 1)
+		es = append(es, n...)
+	}
+	for first := true; len(es) > 0; first = false {
+		n := min(len(es), maxPayload)
+		if first {
+			pes(buf, 0xE0, pts, pts-frameDuration, es[:n])
+		} else {
+			pesNoPTS(buf, 0xE0, es[:n])
+		}
+		es = es[n:]
+	}
+}
+
+// hikHeader 海康媒体头（PS 封装，8kHz 单声道16位音频）
+func hikHeader(video, audio uint16) []byte {
+	h := make([]byte, 40)
+	copy(h, "IMKH")
+	le := binary.LittleEndian
+	le.PutUint16(h[4:], 0x0101)
+	le.PutUint16(h[8:], 0x0002)
+	le.PutUint16(h[10:], video)
+	le.PutUint16(h[12:], audio)
+	h[14] = 1
+	h[15] = 16
+	le.PutUint32(h[16:], 8000)
+	le.PutUint32(h[20:], 64000)
+	return h
+}
+
+// pack MPEG-2 包头，SCR 取 PTS，带2字节填充
+func pack(buf *bytes.Buffer, scr int64) {
+	buf.Write([]byte{0, 0, 1, 0xBA})
+	buf.Write([]byte{
+		0x44 | byte(scr>>27&0x38) | byte(scr>>28&0x03),
+		byte(scr >> 20),
+		byte(scr>>12&0xF8) | 0x04 | byte(scr>>13&0x03),
+		byte(scr >> 5),
+		byte(scr<<3&0xF8) | 0x04,
+		0x01,
+		0x01, 0x86, 0xA3, // program_mux_rate
+		0xFA, // reserved + pack_stuffing_length=2
+		0xFF, 0xFF,
+	})
+}
+
+// systemHeader 系统头
+func systemHeader(buf *bytes.Buffer) {
+	body := []byte{0x80, 0xC3, 0x51, 0x04, 0xE1, 0x7F, 0xE0, 0xE0, 0x80, 0xC0, 0xC0, 0x08}
+	buf.Write([]byte{0, 0, 1, 0xBB})
+	binary.Write(buf, binary.BigEndian, uint16(len(body)))
+	buf.Write(body)
+}
+
+// streamMap 节目流映射
+func streamMap(buf *bytes.Buffer, videoType, audioType byte) {
+	var es []byte
+	es = append(es, videoType, 0xE0, 0, 0)
+	if audioType != 0 {
+		es = append(es, audioType, 0xC0, 0, 0)
+	}
+	body := []byte{0xE0, 0xFF, 0, 0, byte(len(es) >> 8), byte(len(es))}
+	body = append(body, es...)
+	body = append(body, 0, 0, 0, 0) // CRC32（解封装不校验）
+	buf.Write([]byte{0, 0, 1, 0xBC})
+	binary.Write(buf, binary.BigEndian, uint16(len(body)))
+	buf.Write(body)
+}
+
+// pes 带 PTS（dts>=0 时同时带 DTS）的 PES 包
+func pes(buf *bytes.Buffer, id byte, pts, dts int64, data []byte) {
+	header := []byte{0x80, 0x80, 5}
+	ts := timestamp(0x20, pts)
+	if dts >= 0 {
+		header = []byte{0x80, 0xC0, 10}
+		ts = append(timestamp(0x30, pts), timestamp(0x10, dts)...)
+	}
+	header = append(header, ts...)
+	writePES(buf, id, header, data)
+}
+
+// pesNoPTS 不带时间戳的续包，包头带3字节填充
+func pesNoPTS(buf *bytes.Buffer, id byte, data []byte) {
+	writePES(buf, id, []byte{0x80, 0x00, 3, 0xFF, 0xFF, 0xFF}, data)
+}
+
+func writePES(buf *bytes.Buffer, id byte, header, data []byte) {
+	buf.Write([]byte{0, 0, 1, id})
+	binary.Write(buf, binary.BigEndian, uint16(len(header)+len(data)))
+	buf.Write(header)
+	buf.Write(data)
+}
+
+// private 海康私有数据包
+func private(buf *bytes.Buffer) {
+	writePES(buf, 0xBD, []byte{0x80, 0x00, 0}, payload(64, 0x5A))
+}
+
+// timestamp 5字节的 PTS/DTS 编码
+func timestamp(prefix byte, ts int64) []byte {
+	return []byte{
+		prefix | byte(ts>>29&0x0E) | 0x01,
+		byte(ts >> 22),
+		byte(ts>>14&0xFE) | 0x01,
+		byte(ts >> 7),
+		byte(ts<<1&0xFE) | 0x01,
+	}
+}
+
+// nal H.264 NAL 单元
+func nal(header byte, size int) []byte {
+	return append([]byte{header}, payload(size, header)...)
+}
+
+// nal2 H.265 NAL 单元（2字节头）
+func nal2(typ byte, size int) []byte {
+	return append([]byte{typ << 1, 0x01}, payload(size, typ)...)
+}
+
+// payload 不含0字节的伪随机数据，避免出现起始码
+func payload(size int, seed byte) []byte {
+	p := make([]byte, size)
+	for i := range p {
+		p[i] = byte(1 + (i*7+int(seed))%254)
+	}
+	return p
+}
+
+func write(name string, data []byte) {
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s: %d 字节", name, len(data))
+}
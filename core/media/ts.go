@@ -0,0 +1,269 @@
+package media
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Muxer 封装器
+// 将解封装得到的帧写入标准容器；Close 只完成封装（写出缓存的数据），不关闭底层的 io.Writer
+type Muxer interface {
+	// WriteFrame 写入一帧
+	WriteFrame(f *Frame) error
+	// Close 完成封装
+	Close() error
+}
+
+// MPEG-TS 参数
+const (
+	tsPacketSize = 188
+	tsPIDPAT     = 0x0000
+	tsPIDPMT     = 0x1000
+	tsPIDVideo   = 0x0100
+	tsPIDAudio   = 0x0101
+)
+
+// tsStreamTypes TS 支持的编码及其 stream_type
+var tsStreamTypes = map[Codec]byte{
+	CodecH264: streamTypeH264,
+	CodecH265: streamTypeH265,
+	CodecAAC:  streamTypeAAC,
+}
+
+// TSMuxer MPEG-TS 封装器
+// 视频支持 H.264/H.265，音频只支持 AAC（其他音频帧被忽略）；
+// 从第一个视频关键帧开始写入，每个关键帧前重复写入 PAT/PMT，便于从任意关键帧开始播放
+type TSMuxer struct {
+	w          *bufio.Writer
+	videoCodec Codec          // 视频编码，第一个关键帧确定
+	audioCodec Codec          // 音频编码，未知或不支持时为 CodecUnknown
+	started    bool           // 是否已写入第一个关键帧
+	cc         map[uint16]int // PID -> 连续计数器
+	packet     [tsPacketSize]byte
+}
+
+// NewTSMuxer 创建 MPEG-TS 封装器
+// 参数：
+//   - w: 输出
+func NewTSMuxer(w io.Writer) *TSMuxer {
+	return &TSMuxer{
+		w:  bufio.NewWriterSize(w, 64*1024),
+		cc: make(map[uint16]int),
+	}
+}
+
+// WriteFrame 写入一帧
+// 第一个视频关键帧之前的帧被忽略
+func (m *TSMuxer) WriteFrame(f *Frame) error {
+	if f.Codec.IsAudio() {
+		if _, ok := tsStreamTypes[f.Codec]; !ok {
+			return nil
+		}
+		if m.audioCodec == CodecUnknown && !m.started {
+			// 音频编码只在写入 PMT 之前确定
+			m.audioCodec = f.Codec
+		}
+		if !m.started || f.Codec != m.audioCodec {
+			return nil
+		}
+		return m.writePES(tsPIDAudio, 0xC0, f, false)
+	}
+
+	if _, ok := tsStreamTypes[f.Codec]; !ok {
+		return fmt.Errorf("TS 不支持的视频编码: %s", f.Codec)
+	}
+	if !m.started {
+		if !f.Keyframe {
+			return nil
+		}
+		m.videoCodec = f.Codec
+		m.started = true
+	}
+	if f.Codec != m.videoCodec {
+		return fmt.Errorf("视频编码从 %s 变为 %s", m.videoCodec, f.Codec)
+	}
+	if f.Keyframe {
+		if err := m.writeTables(); err != nil {
+			return err
+		}
+	}
+	return m.writePES(tsPIDVideo, 0xE0, f, true)
+}
+
+// Close 写出缓存的数据
+func (m *TSMuxer) Close() error {
+	return m.w.Flush()
+}
+
+// writeTables 写入 PAT 和 PMT
+func (m *TSMuxer) writeTables() error {
+	// PAT：节目1 -> PMT
+	pat := []byte{0x00, 0x01, 0xE0 | tsPIDPMT>>8, tsPIDPMT & 0xFF}
+	if err := m.writeSection(tsPIDPAT, 0x00, 0x0001, pat); err != nil {
+		return err
+	}
+
+	// PMT：PCR 在视频PID上
+	pmt := []byte{0xE0 | tsPIDVideo>>8, tsPIDVideo & 0xFF, 0xF0, 0x00}
+	pmt = append(pmt, tsStreamTypes[m.videoCodec], 0xE0|tsPIDVideo>>8, tsPIDVideo&0xFF, 0xF0, 0x00)
+	if m.audioCodec != CodecUnknown {
+		pmt = append(pmt, tsStreamTypes[m.audioCodec], 0xE0|tsPIDAudio>>8, tsPIDAudio&0xFF, 0xF0, 0x00)
+	}
+	return m.writeSection(tsPIDPMT, 0x02, 0x0001, pmt)
+}
+
+// writeSection 写入一个 PSI 段（单个TS包）
+func (m *TSMuxer) writeSection(pid uint16, tableID byte, tableIDExt uint16, body []byte) error {
+	length := 5 + len(body) + 4
+	section := []byte{
+		tableID,
+		0xB0 | byte(length>>8), byte(length),
+		byte(tableIDExt >> 8), byte(tableIDExt),
+		0xC1, // version 0, current_next_indicator 1
+		0x00, 0x00,
+	}
+	section = append(section, body...)
+	section = binary.BigEndian.AppendUint32(section, crc32MPEG(section))
+
+	p := m.header(pid, true)
+	p[4] = 0x00 // pointer_field
+	n := copy(p[5:], section)
+	for i := 5 + n; i < tsPacketSize; i++ {
+		p[i] = 0xFF
+	}
+	_, err := m.w.Write(p)
+	return err
+}
+
+// writePES 将一帧打包为 PES 并拆分为 TS 包
+func (m *TSMuxer) writePES(pid uint16, streamID byte, f *Frame, video bool) error {
+	header := []byte{0x00, 0x00, 0x01, streamID, 0, 0, 0x80, 0x80, 5}
+	if f.DTS != f.PTS {
+		header[7], header[8] = 0xC0, 10
+		header = append(header, encodeTimestamp(0x30, f.PTS)...)
+		header = append(header, encodeTimestamp(0x10, f.DTS)...)
+	} else {
+		header = append(header, encodeTimestamp(0x20, f.PTS)...)
+	}
+	// 视频 PES 长度可以为0（不限长度），音频必须填写
+	if length := len(header) - 6 + len(f.Data); !video || length <= 0xFFFF {
+		binary.BigEndian.PutUint16(header[4:], uint16(length))
+	}
+
+	payload := append(header, f.Data...)
+	for first := true; len(payload) > 0; first = false {
+		p := m.header(pid, first)
+
+		// 自适应字段：第一个包携带 PCR 和随机访问标志，最后一个包用于填充
+		var adaptation []byte
+		if first && video {
+			flags := byte(0x10) // PCR_flag
+			if f.Keyframe {
+				flags |= 0x40 // random_access_indicator
+			}
+			adaptation = append([]byte{flags}, encodePCR(f.DTS)...)
+		} else if first && f.Keyframe {
+			adaptation = []byte{0x40}
+		}
+		room := tsPacketSize - 4
+		if adaptation != nil {
+			room -= 1 + len(adaptation)
+		}
+		if len(payload) < room {
+			// 填充：自适应字段至少占1字节（长度字段）
+			if adaptation == nil {
+				adaptation = []byte{}
+				room--
+			}
+			if len(adaptation) == 0 && room > len(payload) {
+				adaptation = append(adaptation, 0x00)
+				room--
+			}
+			for room > len(payload) {
+				adaptation = append(adaptation, 0xFF)
+				room--
+			}
+		}
+
+		pos := 4
+		if adaptation != nil {
+			p[3] |= 0x20 // adaptation_field_control：带自适应字段
+			p[4] = byte(len(adaptation))
+			copy(p[5:], adaptation)
+			pos = 5 + len(adaptation)
+		}
+		n := copy(p[pos:], payload)
+		payload = payload[n:]
+
+		if _, err := m.w.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// header 填写 TS 包头（带负载），返回整个包的缓冲区
+func (m *TSMuxer) header(pid uint16, start bool) []byte {
+	p := m.packet[:]
+	p[0] = 0x47
+	p[1] = byte(pid>>8) & 0x1F
+	if start {
+		p[1] |= 0x40 // payload_unit_start_indicator
+	}
+	p[2] = byte(pid)
+	p[3] = 0x10 | byte(m.cc[pid]&0x0F)
+	m.cc[pid]++
+	return p
+}
+
+// encodeTimestamp 编码5字节的 PTS/DTS
+func encodeTimestamp(prefix byte, ts int64) []byte {
+	return []byte{
+		prefix | byte(ts>>29&0x0E) | 0x01,
+		byte(ts >> 22),
+		byte(ts>>14&0xFE) | 0x01,
+		byte(ts >> 7),
+		byte(ts<<1&0xFE) | 0x01,
+	}
+}
+
+// encodePCR 编码6字节的 PCR（扩展部分为0）
+func encodePCR(base int64) []byte {
+	base &= 1<<33 - 1
+	return []byte{
+		byte(base >> 25),
+		byte(base >> 17),
+		byte(base >> 9),
+		byte(base >> 1),
+		byte(base<<7) | 0x7E,
+		0x00,
+	}
+}
+
+// crcTable MPEG-2 CRC32 查找表（多项式 0x04C11DB7，不反转）
+var crcTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// crc32MPEG 计算 PSI 段的 CRC32
+func crc32MPEG(data []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc = crc<<8 ^ crcTable[byte(crc>>24)^b]
+	}
+	return crc
+}
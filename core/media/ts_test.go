@@ -0,0 +1,131 @@
+package media
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// tsPacket 解析后的 TS 包
+type tsPacket struct {
+	pid     uint16
+	start   bool
+	cc      int
+	adapt   []byte // 自适应字段（不含长度字节）
+	payload []byte
+}
+
+// parseTS 拆分 TS 包
+func parseTS(t *testing.T, data []byte) []tsPacket {
+	t.Helper()
+	if len(data)%tsPacketSize != 0 {
+		t.Fatalf("TS 长度 %d 不是188的整数倍", len(data))
+	}
+	var packets []tsPacket
+	for ; len(data) > 0; data = data[tsPacketSize:] {
+		p := data[:tsPacketSize]
+		if p[0] != 0x47 {
+			t.Fatalf("同步字节错误: %02X", p[0])
+		}
+		pkt := tsPacket{
+			pid:   uint16(p[1]&0x1F)<<8 | uint16(p[2]),
+			start: p[1]&0x40 != 0,
+			cc:    int(p[3] & 0x0F),
+		}
+		pos := 4
+		if p[3]&0x20 != 0 {
+			pkt.adapt = p[5 : 5+int(p[4])]
+			pos = 5 + int(p[4])
+		}
+		pkt.payload = p[pos:]
+		packets = append(packets, pkt)
+	}
+	return packets
+}
+
+// muxSample 将样例文件的所有帧写入封装器
+func muxSample(t *testing.T, m Muxer, name string) {
+	t.Helper()
+	frames, err := readAll(NewDemuxer(bytes.NewReader(readSample(t, name))))
+	if err != io.EOF {
+		t.Fatalf("解封装失败: %v", err)
+	}
+	for _, f := range frames {
+		if err := m.WriteFrame(f); err != nil {
+			t.Fatalf("写入 %s 失败: %v", f, err)
+		}
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("关闭封装器失败: %v", err)
+	}
+}
+
+// TestTSMuxer 检查 PAT/PMT、PES 时间戳、PCR 和连续计数器
+func TestTSMuxer(t *testing.T) {
+	var buf bytes.Buffer
+	muxSample(t, NewTSMuxer(&buf), "h264.ps")
+	packets := parseTS(t, buf.Bytes())
+
+	if packets[0].pid != tsPIDPAT || packets[1].pid != tsPIDPMT {
+		t.Fatalf("应以 PAT、PMT 开头，实际 PID: %#x %#x", packets[0].pid, packets[1].pid)
+	}
+	pmt := packets[1].payload[1:] // 跳过 pointer_field
+	length := int(pmt[1]&0x0F)<<8 | int(pmt[2])
+	if crc32MPEG(pmt[:3+length]) != 0 {
+		t.Error("PMT 的 CRC 校验失败")
+	}
+	// G.711 不能写入 TS，PMT 中只有视频
+	if streams := pmt[12 : 3+length-4]; len(streams) != 5 || streams[0] != streamTypeH264 {
+		t.Errorf("PMT 节目流错误: % X", streams)
+	}
+
+	cc := map[uint16]int{}
+	tables, starts := 0, 0
+	for _, p := range packets {
+		if want, ok := cc[p.pid]; ok && p.cc != want {
+			t.Fatalf("PID %#x 连续计数器错误: %d，期望 %d", p.pid, p.cc, want)
+		}
+		cc[p.pid] = (p.cc + 1) & 0x0F
+
+		switch p.pid {
+		case tsPIDPAT:
+			tables++
+		case tsPIDAudio:
+			t.Fatal("不应写入 G.711 音频")
+		case tsPIDVideo:
+			if !p.start {
+				continue
+			}
+			if len(p.adapt) < 7 || p.adapt[0]&0x10 == 0 {
+				t.Fatal("视频帧的第一个包应携带 PCR")
+			}
+			pts := parseTimestamp(p.payload[9:])
+			if want := int64(900000 + starts*3600); pts != want {
+				t.Errorf("第%d帧 PTS=%d，期望 %d", starts, pts, want)
+			}
+			if key := p.adapt[0]&0x40 != 0; key != (starts == 0 || starts == 4) {
+				t.Errorf("第%d帧随机访问标志错误", starts)
+			}
+			starts++
+		}
+	}
+	if starts != 5 || tables != 2 {
+		t.Errorf("应有5个视频帧和2组 PAT/PMT（每个关键帧前），实际: %d/%d", starts, tables)
+	}
+}
+
+// TestTSMuxerWaitKeyframe 第一个关键帧之前的帧被忽略
+func TestTSMuxerWaitKeyframe(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewTSMuxer(&buf)
+	m.WriteFrame(&Frame{Codec: CodecH264, PTS: 1, DTS: 1, Data: []byte{0, 0, 1, 0x41}})
+	m.WriteFrame(&Frame{Codec: CodecAAC, PTS: 1, DTS: 1, Data: adtsFrame(4)})
+	m.Close()
+	if buf.Len() != 0 {
+		t.Errorf("关键帧之前不应输出数据，实际 %d字节", buf.Len())
+	}
+
+	if err := m.WriteFrame(&Frame{Codec: CodecUnknown}); err == nil {
+		t.Error("未知编码应返回错误")
+	}
+}
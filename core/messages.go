@@ -0,0 +1,264 @@
+package core
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Locale 错误消息的语言
+type Locale string
+
+// 支持的语言
+const (
+	LocaleZhCN Locale = "zh-CN" // 简体中文（默认）
+	LocaleEnUS Locale = "en-US" // 英文
+)
+
+// catalog 一种语言的消息目录
+type catalog struct {
+	errors         map[int]string    // SDK错误码 -> 说明
+	isapiStatus    map[int]string    // ISAPI statusCode -> 说明
+	isapiSubStatus map[string]string // ISAPI subStatusCode -> 说明
+	failed         string            // HKError.Error() 的格式：操作、错误码、说明
+	unknown        string            // 未知错误码的格式
+	deviceStatus   string            // 附加ISAPI响应状态的格式：说明、子状态码、子状态说明
+}
+
+// catalogs 各语言的消息目录
+var catalogs = map[Locale]*catalog{
+	LocaleZhCN: {
+		errors:         zhCNMessages,
+		isapiStatus:    zhCNISAPIStatus,
+		isapiSubStatus: zhCNISAPISubStatus,
+		failed:         "%s失败，错误码: %d, %s",
+		unknown:        "未知错误码: %d",
+		deviceStatus:   "%s（设备返回: %s, %s）",
+	},
+	LocaleEnUS: {
+		errors:         enUSMessages,
+		isapiStatus:    enUSISAPIStatus,
+		isapiSubStatus: enUSISAPISubStatus,
+		failed:         "%s failed, error code: %d, %s",
+		unknown:        "unknown error code: %d",
+		deviceStatus:   "%s (device returned: %s, %s)",
+	},
+}
+
+// currentLocale 当前语言
+var currentLocale atomic.Value
+
+func init() {
+	currentLocale.Store(LocaleZhCN)
+}
+
+// SetLocale 设置全局错误消息语言
+// 之后创建的 HKError 及 GetErrorMsg 均使用该语言，默认为简体中文
+// 参数：
+//   - locale: 语言（LocaleZhCN 或 LocaleEnUS）
+//
+// 返回值：
+//   - error: 不支持的语言返回错误
+func SetLocale(locale Locale) error {
+	if _, ok := catalogs[locale]; !ok {
+		return fmt.Errorf("不支持的语言：%s", locale)
+	}
+	currentLocale.Store(locale)
+	return nil
+}
+
+// CurrentLocale 获取当前错误消息语言
+func CurrentLocale() Locale {
+	return currentLocale.Load().(Locale)
+}
+
+// catalogFor 获取指定语言的消息目录，不支持的语言使用简体中文
+func catalogFor(locale Locale) *catalog {
+	if c, ok := catalogs[locale]; ok {
+		return c
+	}
+	return catalogs[LocaleZhCN]
+}
+
+// lookupErrorMsg 在消息目录中查找错误码说明
+func lookupErrorMsg(code int, locale Locale) (string, bool) {
+	msg, ok := catalogFor(locale).errors[code]
+	return msg, ok
+}
+
+// GetErrorMsg 根据错误码获取错误消息（使用当前语言）
+// 参数：
+//   - code: 错误码
+//
+// 返回值：
+//   - string: 错误消息描述
+func GetErrorMsg(code int) string {
+	return GetErrorMsgIn(code, CurrentLocale())
+}
+
+// GetErrorMsgIn 根据错误码获取指定语言的错误消息
+// 参数：
+//   - code: 错误码
+//   - locale: 语言，不支持的语言使用简体中文
+//
+// 返回值：
+//   - string: 错误消息描述
+func GetErrorMsgIn(code int, locale Locale) string {
+	if msg, ok := lookupErrorMsg(code, locale); ok {
+		return msg
+	}
+	return fmt.Sprintf(catalogFor(locale).unknown, code)
+}
+
+// GetISAPIStatusMsg 获取ISAPI响应状态的说明
+// 优先按子状态码查找，未收录时使用状态码的说明，都未收录时返回子状态码本身
+// 参数：
+//   - statusCode: 状态码（ResponseStatus.statusCode）
+//   - subStatusCode: 子状态码（ResponseStatus.subStatusCode，如 notSupport）
+//   - locale: 语言，不支持的语言使用简体中文
+//
+// 返回值：
+//   - string: 响应状态说明
+func GetISAPIStatusMsg(statusCode int, subStatusCode string, locale Locale) string {
+	c := catalogFor(locale)
+	if msg, ok := c.isapiSubStatus[subStatusCode]; ok {
+		return msg
+	}
+	if msg, ok := c.isapiStatus[statusCode]; ok {
+		return msg
+	}
+	return subStatusCode
+}
+
+// zhCNISAPIStatus ISAPI状态码说明（简体中文）
+var zhCNISAPIStatus = map[int]string{
+	1: "成功",
+	2: "设备忙",
+	3: "设备错误",
+	4: "无效操作",
+	5: "无效的XML格式",
+	6: "无效的XML内容",
+	7: "需要重启设备",
+}
+
+// enUSISAPIStatus ISAPI状态码说明（英文）
+var enUSISAPIStatus = map[int]string{
+	1: "OK",
+	2: "device busy",
+	3: "device error",
+	4: "invalid operation",
+	5: "invalid XML format",
+	6: "invalid XML content",
+	7: "reboot required",
+}
+
+// zhCNISAPISubStatus 常见ISAPI子状态码说明（简体中文）
+var zhCNISAPISubStatus = map[string]string{
+	"ok":                              "成功",
+	"noMemory":                        "内存不足",
+	"serviceUnavailable":              "服务不可用",
+	"upgrading":                       "设备正在升级",
+	"deviceBusy":                      "设备忙",
+	"reConnectIpc":                    "正在重连IPC",
+	"deviceError":                     "设备硬件错误",
+	"badFlash":                        "Flash操作失败",
+	"28181Uninitialized":              "28181配置未初始化",
+	"notSupport":                      "不支持该功能",
+	"lowPrivilege":                    "权限不足",
+	"badAuthorization":                "认证失败",
+	"methodNotAllowed":                "不允许的HTTP方法",
+	"notSetHdiskRedund":               "未设置冗余硬盘",
+	"invalidOperation":                "无效操作",
+	"notActivated":                    "设备未激活",
+	"hasActivated":                    "设备已激活",
+	"certificateExist":                "证书已存在",
+	"badXmlFormat":                    "XML格式错误",
+	"badParameters":                   "参数错误",
+	"badHostAddress":                  "主机地址错误",
+	"badXmlContent":                   "XML内容错误",
+	"badIPv4Address":                  "IPv4地址错误",
+	"badIPv6Address":                  "IPv6地址错误",
+	"conflictIPv4Address":             "IPv4地址冲突",
+	"conflictIPv6Address":             "IPv6地址冲突",
+	"badDomainName":                   "域名错误",
+	"connectSreverFail":               "连接服务器失败",
+	"conflictDomainName":              "域名冲突",
+	"badPort":                         "端口错误",
+	"portError":                       "端口错误",
+	"exportErrorData":                 "导出数据错误",
+	"badNetMask":                      "子网掩码错误",
+	"badVersion":                      "版本不匹配",
+	"badDevType":                      "设备类型不匹配",
+	"badLanguage":                     "语言不匹配",
+	"incorrentUserNameOrPasswd":       "用户名或密码错误",
+	"invalidStoragePoolOfCloudServer": "云存储资源池无效",
+	"noFreeSpaceOfStoragePool":        "云存储资源池空间不足",
+	"riskPassword":                    "风险密码",
+	"UnSupportCapture":                "不支持抓图",
+	"userPwdLenUnder8":                "密码长度不足8位",
+	"userPwdNameSame":                 "密码与用户名相同",
+	"userPwdNameMirror":               "密码为用户名的倒序",
+	"beyondARGSRangeLimit":            "参数超出范围",
+	"invalidID":                       "ID无效",
+	"invalidChannel":                  "通道号无效",
+	"userNotExist":                    "用户不存在",
+	"userExist":                       "用户已存在",
+	"passwordError":                   "密码错误",
+	"adminUserNotAllowedModify":       "不允许修改管理员用户",
+	"rebootRequired":                  "需要重启设备",
+}
+
+// enUSISAPISubStatus 常见ISAPI子状态码说明（英文）
+var enUSISAPISubStatus = map[string]string{
+	"ok":                              "OK",
+	"noMemory":                        "insufficient memory",
+	"serviceUnavailable":              "service unavailable",
+	"upgrading":                       "device is upgrading",
+	"deviceBusy":                      "device busy",
+	"reConnectIpc":                    "reconnecting to IPC",
+	"deviceError":                     "device hardware error",
+	"badFlash":                        "flash operation failed",
+	"28181Uninitialized":              "28181 configuration not initialized",
+	"notSupport":                      "not supported",
+	"lowPrivilege":                    "insufficient permission",
+	"badAuthorization":                "authentication failed",
+	"methodNotAllowed":                "HTTP method not allowed",
+	"notSetHdiskRedund":               "redundant hard disk not set",
+	"invalidOperation":                "invalid operation",
+	"notActivated":                    "device not activated",
+	"hasActivated":                    "device already activated",
+	"certificateExist":                "certificate already exists",
+	"badXmlFormat":                    "invalid XML format",
+	"badParameters":                   "invalid parameters",
+	"badHostAddress":                  "invalid host address",
+	"badXmlContent":                   "invalid XML content",
+	"badIPv4Address":                  "invalid IPv4 address",
+	"badIPv6Address":                  "invalid IPv6 address",
+	"conflictIPv4Address":             "IPv4 address conflict",
+	"conflictIPv6Address":             "IPv6 address conflict",
+	"badDomainName":                   "invalid domain name",
+	"connectSreverFail":               "failed to connect to server",
+	"conflictDomainName":              "domain name conflict",
+	"badPort":                         "invalid port",
+	"portError":                       "port error",
+	"exportErrorData":                 "export data error",
+	"badNetMask":                      "invalid subnet mask",
+	"badVersion":                      "version mismatch",
+	"badDevType":                      "device type mismatch",
+	"badLanguage":                     "language mismatch",
+	"incorrentUserNameOrPasswd":       "incorrect user name or password",
+	"invalidStoragePoolOfCloudServer": "invalid cloud storage pool",
+	"noFreeSpaceOfStoragePool":        "insufficient cloud storage pool space",
+	"riskPassword":                    "risky password",
+	"UnSupportCapture":                "capture not supported",
+	"userPwdLenUnder8":                "password shorter than 8 characters",
+	"userPwdNameSame":                 "password is the same as the user name",
+	"userPwdNameMirror":               "password is the user name reversed",
+	"beyondARGSRangeLimit":            "parameter out of range",
+	"invalidID":                       "invalid ID",
+	"invalidChannel":                  "invalid channel",
+	"userNotExist":                    "user does not exist",
+	"userExist":                       "user already exists",
+	"passwordError":                   "incorrect password",
+	"adminUserNotAllowedModify":       "admin user cannot be modified",
+	"rebootRequired":                  "reboot required",
+}
@@ -0,0 +1,284 @@
+package core
+
+// enUSMessages 英文错误码说明
+// 与 zhCNMessages 一一对应
+var enUSMessages = map[int]string{
+	// 网络通讯库错误码
+	0:   "no error",                                                                // NET_DVR_NOERROR
+	1:   "incorrect user name or password",                                         // NET_DVR_PASSWORD_ERROR
+	2:   "insufficient permission",                                                 // NET_DVR_NOENOUGHPRI
+	3:   "SDK not initialized",                                                     // NET_DVR_NOINIT
+	4:   "invalid channel number",                                                  // NET_DVR_CHANNEL_ERROR
+	5:   "number of connected users exceeds the maximum",                           // NET_DVR_OVER_MAXLINK
+	6:   "version mismatch",                                                        // NET_DVR_VERSIONNOMATCH
+	7:   "failed to connect to the device",                                         // NET_DVR_NETWORK_FAIL_CONNECT
+	8:   "failed to send data to the device",                                       // NET_DVR_NETWORK_SEND_ERROR
+	9:   "failed to receive data from the device",                                  // NET_DVR_NETWORK_RECV_ERROR
+	10:  "timed out receiving data from the device",                                // NET_DVR_NETWORK_RECV_TIMEOUT
+	11:  "transmitted data is invalid",                                             // NET_DVR_NETWORK_ERRORDATA
+	12:  "incorrect calling order",                                                 // NET_DVR_ORDER_ERROR
+	13:  "no permission for this operation",                                        // NET_DVR_OPERNOPERMIT
+	14:  "device command execution timed out",                                      // NET_DVR_COMMANDTIMEOUT
+	15:  "invalid serial port number",                                              // NET_DVR_ERRORSERIALPORT
+	16:  "invalid alarm port",                                                      // NET_DVR_ERRORALARMPORT
+	17:  "invalid parameter",                                                       // NET_DVR_PARAMETER_ERROR
+	18:  "device channel is in an error state",                                     // NET_DVR_CHAN_EXCEPTION
+	19:  "device has no hard disk",                                                 // NET_DVR_NODISK
+	20:  "invalid hard disk number",                                                // NET_DVR_ERRORDISKNUM
+	21:  "device hard disk is full",                                                // NET_DVR_DISK_FULL
+	22:  "device hard disk error",                                                  // NET_DVR_DISK_ERROR
+	23:  "function not supported by the device",                                    // NET_DVR_NOSUPPORT
+	24:  "device is busy",                                                          // NET_DVR_BUSY
+	25:  "device modification failed",                                              // NET_DVR_MODIFY_FAIL
+	26:  "invalid password format",                                                 // NET_DVR_PASSWORD_FORMAT_ERROR
+	27:  "hard disk is formatting, operation cannot start",                         // NET_DVR_DISK_FORMATING
+	28:  "insufficient device resources",                                           // NET_DVR_DVRNORESOURCE
+	29:  "device operation failed",                                                 // NET_DVR_DVROPRATEFAILED
+	30:  "failed to capture local audio or open audio output",                      // NET_DVR_OPENHOSTSOUND_FAIL
+	31:  "device voice talk is occupied",                                           // NET_DVR_DVRVOICEOPENED
+	32:  "invalid time input",                                                      // NET_DVR_TIMEINPUTERROR
+	33:  "specified file not found on the device for playback",                     // NET_DVR_NOSPECFILE
+	34:  "failed to create file",                                                   // NET_DVR_CREATEFILE_ERROR
+	35:  "failed to open file",                                                     // NET_DVR_FILEOPENFAIL
+	36:  "previous operation has not finished",                                     // NET_DVR_OPERNOTFINISH
+	37:  "failed to get the current playback time",                                 // NET_DVR_GETPLAYTIMEFAIL
+	38:  "playback failed",                                                         // NET_DVR_PLAYFAIL
+	39:  "invalid file format",                                                     // NET_DVR_FILEFORMAT_ERROR
+	40:  "invalid path",                                                            // NET_DVR_DIR_ERROR
+	41:  "SDK resource allocation failed",                                          // NET_DVR_ALLOC_RESOURCE_ERROR
+	42:  "sound card mode error",                                                   // NET_DVR_AUDIO_MODE_ERROR
+	43:  "buffer too small",                                                        // NET_DVR_NOENOUGH_BUF
+	44:  "failed to create socket",                                                 // NET_DVR_CREATESOCKET_ERROR
+	45:  "failed to set socket",                                                    // NET_DVR_SETSOCKET_ERROR
+	46:  "maximum number reached",                                                  // NET_DVR_MAX_NUM
+	47:  "user does not exist",                                                     // NET_DVR_USERNOTEXIST
+	48:  "failed to write flash",                                                   // NET_DVR_WRITEFLASHERROR
+	49:  "device upgrade failed",                                                   // NET_DVR_UPGRADEFAIL
+	50:  "decoding card already initialized",                                       // NET_DVR_CARDHAVEINIT
+	51:  "player library function call failed",                                     // NET_DVR_PLAYERFAILED
+	52:  "number of logged-in users reached the maximum",                           // NET_DVR_MAX_USERNUM
+	53:  "failed to get local PC IP or MAC address",                                // NET_DVR_GETLOCALIPANDMACFAIL
+	54:  "encoding is not enabled on this channel",                                 // NET_DVR_NOENCODEING
+	55:  "IP address mismatch",                                                     // NET_DVR_IPMISMATCH
+	56:  "MAC address mismatch",                                                    // NET_DVR_MACMISMATCH
+	57:  "upgrade file language mismatch",                                          // NET_DVR_UPGRADELANGMISMATCH
+	58:  "number of player channels reached the maximum",                           // NET_DVR_MAX_PLAYERPORT
+	59:  "insufficient space on the backup device",                                 // NET_DVR_NOSPACEBACKUP
+	60:  "specified backup device not found",                                       // NET_DVR_NODEVICEBACKUP
+	61:  "image bit depth mismatch, 24-bit color only",                             // NET_DVR_PICTURE_BITS_ERROR
+	62:  "image dimensions exceed the limit of 128*256",                            // NET_DVR_PICTURE_DIMENSION_ERROR
+	63:  "image size exceeds the limit of 100K",                                    // NET_DVR_PICTURE_SIZ_ERROR
+	64:  "failed to load Player SDK from the current directory",                    // NET_DVR_LOADPLAYERSDKFAILED
+	65:  "function entry not found in Player SDK",                                  // NET_DVR_LOADPLAYERSDKPROC_ERROR
+	66:  "failed to load DsSdk from the current directory",                         // NET_DVR_LOADDSSDKFAILED
+	67:  "function entry not found in DsSdk",                                       // NET_DVR_LOADDSSDKPROC_ERROR
+	68:  "hardware decoding library DsSdk function call failed",                    // NET_DVR_DSSDK_ERROR
+	69:  "sound card is in exclusive use",                                          // NET_DVR_VOICEMONOPOLIZE
+	70:  "failed to join multicast group",                                          // NET_DVR_JOINMULTICASTFAILED
+	71:  "failed to create log directory",                                          // NET_DVR_CREATEDIR_ERROR
+	72:  "failed to bind socket",                                                   // NET_DVR_BINDSOCKET_ERROR
+	73:  "socket connection interrupted",                                           // NET_DVR_SOCKETCLOSE_ERROR
+	74:  "user ID is in use during logout",                                         // NET_DVR_USERID_ISUSING
+	75:  "listen failed",                                                           // NET_DVR_SOCKETLISTEN_ERROR
+	76:  "program exception",                                                       // NET_DVR_PROGRAM_EXCEPTION
+	77:  "failed to write file",                                                    // NET_DVR_WRITEFILE_FAILED
+	78:  "cannot format a read-only hard disk",                                     // NET_DVR_FORMAT_READONLY
+	79:  "duplicate user name in remote user configuration",                        // NET_DVR_WITHSAMEUSERNAME
+	80:  "device model mismatch when importing parameters",                         // NET_DVR_DEVICETYPE_ERROR
+	81:  "language mismatch when importing parameters",                             // NET_DVR_LANGUAGE_ERROR
+	82:  "software version mismatch when importing parameters",                     // NET_DVR_PARAVERSION_ERROR
+	83:  "external IP channel is offline",                                          // NET_DVR_IPCHAN_NOTALIVE
+	84:  "failed to load standard protocol library StreamTransClient",              // NET_DVR_RTSP_SDK_ERROR
+	85:  "failed to load stream conversion library",                                // NET_DVR_CONVERT_SDK_ERROR
+	86:  "maximum number of IP channels exceeded",                                  // NET_DVR_IPC_COUNT_OVERFLOW
+	87:  "maximum number of record tags or similar items exceeded",                 // NET_DVR_MAX_ADD_NUM
+	88:  "image enhancer parameter mode error",                                     // NET_DVR_PARAMMODE_ERROR
+	89:  "code splitter is offline",                                                // NET_DVR_CODESPITTER_OFFLINE
+	90:  "device is backing up",                                                    // NET_DVR_BACKUP_COPYING
+	91:  "channel does not support this operation",                                 // NET_DVR_CHAN_NOTSUPPORT
+	92:  "height line too concentrated or length line not inclined enough",         // NET_DVR_CALLINEINVALID
+	93:  "calibration cancellation conflict",                                       // NET_DVR_CALCANCELCONFLICT
+	94:  "calibration point out of range",                                          // NET_DVR_CALPOINTOUTRANGE
+	95:  "size filter does not meet requirements",                                  // NET_DVR_FILTERRECTINVALID
+	96:  "device is not registered with DDNS",                                      // NET_DVR_DDNS_DEVOFFLINE
+	97:  "DDNS server internal error",                                              // NET_DVR_DDNS_INTER_ERROR
+	100: "failed to load voice talk library from the current directory",            // NET_DVR_INTERCOM_SDK_ERROR
+	101: "no valid upgrade package",                                                // NET_DVR_NO_CURRENT_UPDATEFILE
+	150: "duplicate alias (EasyDDNS configuration)",                                // NET_DVR_ALIAS_DUPLICATE
+	152: "user name does not exist",                                                // NET_DVR_USERNAME_NOT_EXIST
+	153: "user is locked",                                                          // NET_ERR_USERNAME_LOCKED
+	154: "invalid user ID",                                                         // NET_DVR_INVALID_USERID
+	155: "login version too low",                                                   // NET_DVR_LOW_LOGIN_VERSION
+	156: "failed to load libeay32",                                                 // NET_DVR_LOAD_LIBEAY32_DLL_ERROR
+	157: "failed to load ssleay32",                                                 // NET_DVR_LOAD_SSLEAY32_DLL_ERROR
+	158: "failed to load libiconv",                                                 // NET_ERR_LOAD_LIBICONV
+	159: "SSL connection failed",                                                   // NET_ERR_SSL_CONNECT_FAILED
+	800: "network traffic exceeds device capacity",                                 // NET_DVR_DEV_NET_OVERFLOW
+	801: "record file is being written and cannot be locked",                       // NET_DVR_STATUS_RECORDFILE_WRITING_NOT_LOCK
+	802: "hard disk too small to format",                                           // NET_DVR_STATUS_CANT_FORMAT_LITTLE_DISK
+	821: "channel not bound or voice talk binding failed",                          // NET_SDK_ERR_CHAN_AUDIO_BIND
+	822: "device is in N+1 mode, cloud storage cannot be configured",               // NET_DVR_N_PLUS_ONE_MODE
+	823: "cloud storage mode is already enabled",                                   // NET_DVR_CLOUD_STORAGE_OPENED
+	824: "device is taken over in N+0 mode, operation not allowed",                 // NET_DVR_ERR_OPER_NOT_ALLOWED
+	825: "device is taken over in N+0 mode, get redirection information and retry", // NET_DVR_ERR_NEED_RELOCATE
+
+	// RAID错误码
+	200: "name already exists",                                        // NET_DVR_NAME_NOT_ONLY
+	201: "maximum number of arrays reached",                           // NET_DVR_OVER_MAX_ARRAY
+	202: "maximum number of virtual disks reached",                    // NET_DVR_OVER_MAX_VD
+	203: "virtual disk slots are full",                                // NET_DVR_VD_SLOT_EXCEED
+	204: "physical disk status invalid for array rebuild",             // NET_DVR_PD_STATUS_INVALID
+	205: "physical disk for rebuild is a dedicated hot spare",         // NET_DVR_PD_BE_DEDICATE_SPARE
+	206: "physical disk for rebuild is not free",                      // NET_DVR_PD_NOT_FREE
+	207: "cannot migrate from the current array type to the new type", // NET_DVR_CANNOT_MIG2NEWMODE
+	208: "migration paused",                                           // NET_DVR_MIG_PAUSE
+	209: "running migration cancelled",                                // NET_DVR_MIG_CANCEL
+	210: "array has virtual disks and cannot be deleted",              // NET_DVR_EXIST_VD
+	211: "target physical disk belongs to a functional virtual disk",  // NET_DVR_TARGET_IN_LD_FUNCTIONAL
+	212: "physical disk is already assigned to a virtual disk",        // NET_DVR_HD_IS_ASSIGNED_ALREADY
+	213: "physical disk count does not match the RAID level",          // NET_DVR_INVALID_HD_COUNT
+	214: "array is functional and cannot be rebuilt",                  // NET_DVR_LD_IS_FUNCTIONAL
+	215: "a background task is running",                               // NET_DVR_BGA_RUNNING
+	216: "cannot create a virtual disk on an ATAPI disk",              // NET_DVR_LD_NO_ATAPI
+	217: "array does not need migration",                              // NET_DVR_MIGRATION_NOT_NEED
+	218: "physical disks are not of the same type",                    // NET_DVR_HD_TYPE_MISMATCH
+	219: "no virtual disk, operation unavailable",                     // NET_DVR_NO_LD_IN_DG
+	220: "disk too small to be a hot spare",                           // NET_DVR_NO_ROOM_FOR_SPARE
+	221: "disk is already a hot spare of an array",                    // NET_DVR_SPARE_IS_IN_MULTI_DG
+	222: "array is missing disks",                                     // NET_DVR_DG_HAS_MISSING_PD
+	223: "name is empty",                                              // NET_DVR_NAME_EMPTY
+	224: "invalid input parameter",                                    // NET_DVR_INPUT_PARAM
+	225: "physical disk unavailable",                                  // NET_DVR_PD_NOT_AVAILABLE
+	226: "array unavailable",                                          // NET_DVR_ARRAY_NOT_AVAILABLE
+	227: "incorrect number of physical disks",                         // NET_DVR_PD_COUNT
+	228: "virtual disk too small",                                     // NET_DVR_VD_SMALL
+	229: "does not exist",                                             // NET_DVR_NO_EXIST
+	230: "operation not supported",                                    // NET_DVR_NOT_SUPPORT
+	231: "array is not in a functional state",                         // NET_DVR_NOT_FUNCTIONAL
+	232: "virtual disk device node not found",                         // NET_DVR_DEV_NODE_NOT_FOUND
+	233: "maximum number of slots reached",                            // NET_DVR_SLOT_EXCEED
+	234: "no virtual disk on the array",                               // NET_DVR_NO_VD_IN_ARRAY
+	235: "invalid virtual disk slot",                                  // NET_DVR_VD_SLOT_INVALID
+	236: "insufficient physical disk space",                           // NET_DVR_PD_NO_ENOUGH_SPACE
+	237: "only functional arrays can be migrated",                     // NET_DVR_ARRAY_NONFUNCTION
+	238: "insufficient array space",                                   // NET_DVR_ARRAY_NO_ENOUGH_SPACE
+	239: "safe removal or rescan in progress",                         // NET_DVR_STOPPING_SCANNING_ARRAY
+	240: "arrays larger than 16T are not supported",                   // NET_DVR_NOT_SUPPORT_16T
+
+	// N+1错误码
+	803: "remote end cannot be connected",                 // NET_SDK_ERR_REMOTE_DISCONNEC
+	804: "a standby cannot add a standby",                 // NET_SDK_ERR_RD_ADD_RD
+	805: "backup disk exception",                          // NET_SDK_ERR_BACKUP_DISK_EXCEPT
+	806: "maximum number of standbys reached",             // NET_SDK_ERR_RD_LIMIT
+	807: "the added standby is a working machine",         // NET_SDK_ERR_ADDED_RD_IS_WD
+	808: "incorrect adding order",                         // NET_SDK_ERR_ADD_ORDER_WRONG
+	809: "a working machine cannot add a working machine", // NET_SDK_ERR_WD_ADD_WD
+	810: "working machine CVR service exception",          // NET_SDK_ERR_WD_SERVICE_EXCETP
+	811: "standby CVR service exception",                  // NET_SDK_ERR_RD_SERVICE_EXCETP
+	812: "the added working machine is a standby",         // NET_SDK_ERR_ADDED_WD_IS_RD
+	813: "performance limit reached",                      // NET_SDK_ERR_PERFORMANCE_LIMIT
+	814: "device already added",                           // NET_SDK_ERR_ADDED_DEVICE_EXIST
+
+	// RTSP通讯库错误码
+	401: "RTSP unauthorized (server returned 401)",                                          // NET_DVR_RTSP_ERROR_NOENOUGHPRI
+	402: "RTSP resource allocation failed",                                                  // NET_DVR_RTSP_ERROR_ALLOC_RESOURCE
+	403: "RTSP invalid parameter",                                                           // NET_DVR_RTSP_ERROR_PARAMETER
+	404: "specified URL not found (server returned 404)",                                    // NET_DVR_RTSP_ERROR_NO_URL
+	406: "user forcibly stopped",                                                            // NET_DVR_RTSP_ERROR_FORCE_STOP
+	407: "failed to get RTSP port",                                                          // NET_DVR_RTSP_GETPORTFAILED
+	410: "RTSP DESCRIBE interaction error",                                                  // NET_DVR_RTSP_DESCRIBERROR
+	411: "RTSP DESCRIBE send timed out",                                                     // NET_DVR_RTSP_DESCRIBESENDTIMEOUT
+	412: "RTSP DESCRIBE send failed",                                                        // NET_DVR_RTSP_DESCRIBESENDERROR
+	413: "RTSP DESCRIBE receive timed out",                                                  // NET_DVR_RTSP_DESCRIBERECVTIMEOUT
+	414: "RTSP DESCRIBE received data error",                                                // NET_DVR_RTSP_DESCRIBERECVDATALOST
+	415: "RTSP DESCRIBE receive failed",                                                     // NET_DVR_RTSP_DESCRIBERECVERROR
+	416: "RTSP DESCRIBE server returned an error status",                                    // NET_DVR_RTSP_DESCRIBESERVERERR
+	420: "RTSP SETUP interaction error",                                                     // NET_DVR_RTSP_SETUPERROR
+	421: "RTSP SETUP send timed out",                                                        // NET_DVR_RTSP_SETUPSENDTIMEOUT
+	422: "RTSP SETUP send failed",                                                           // NET_DVR_RTSP_SETUPSENDERROR
+	423: "RTSP SETUP receive timed out",                                                     // NET_DVR_RTSP_SETUPRECVTIMEOUT
+	424: "RTSP SETUP received data error",                                                   // NET_DVR_RTSP_SETUPRECVDATALOST
+	425: "RTSP SETUP receive failed",                                                        // NET_DVR_RTSP_SETUPRECVERROR
+	426: "server connection limit exceeded or resources insufficient (server returned 453)", // NET_DVR_RTSP_OVER_MAX_CHAN
+	427: "RTSP SETUP server returned an error status",                                       // NET_DVR_RTSP_SETUPSERVERERR
+	430: "RTSP PLAY interaction error",                                                      // NET_DVR_RTSP_PLAYERROR
+	431: "RTSP PLAY send timed out",                                                         // NET_DVR_RTSP_PLAYSENDTIMEOUT
+	432: "RTSP PLAY send failed",                                                            // NET_DVR_RTSP_PLAYSENDERROR
+	433: "RTSP PLAY receive timed out",                                                      // NET_DVR_RTSP_PLAYRECVTIMEOUT
+	434: "RTSP PLAY received data error",                                                    // NET_DVR_RTSP_PLAYRECVDATALOST
+	435: "RTSP PLAY receive failed",                                                         // NET_DVR_RTSP_PLAYRECVERROR
+	436: "RTSP PLAY server returned an error status",                                        // NET_DVR_RTSP_PLAYSERVERERR
+	440: "RTSP TEARDOWN interaction error",                                                  // NET_DVR_RTSP_TEARDOWNERROR
+	441: "RTSP TEARDOWN send timed out",                                                     // NET_DVR_RTSP_TEARDOWNSENDTIMEOUT
+	442: "RTSP TEARDOWN send failed",                                                        // NET_DVR_RTSP_TEARDOWNSENDERROR
+	443: "RTSP TEARDOWN receive timed out",                                                  // NET_DVR_RTSP_TEARDOWNRECVTIMEOUT
+	444: "RTSP TEARDOWN received data error",                                                // NET_DVR_RTSP_TEARDOWNRECVDATALOST
+	445: "RTSP TEARDOWN receive failed",                                                     // NET_DVR_RTSP_TEARDOWNRECVERROR
+	446: "RTSP TEARDOWN server returned an error status",                                    // NET_DVR_RTSP_TEARDOWNSERVERERR
+
+	// 播放库错误码
+	500: "player library: no error",                                  // NET_PLAYM4_NOERROR
+	501: "invalid input parameter",                                   // NET_PLAYM4_PARA_OVER
+	502: "incorrect calling order",                                   // NET_PLAYM4_ORDER_ERROR
+	503: "failed to set multimedia clock",                            // NET_PLAYM4_TIMER_ERROR
+	504: "video decoding failed",                                     // NET_PLAYM4_DEC_VIDEO_ERROR
+	505: "audio decoding failed",                                     // NET_PLAYM4_DEC_AUDIO_ERROR
+	506: "memory allocation failed",                                  // NET_PLAYM4_ALLOC_MEMORY_ERROR
+	507: "file operation failed",                                     // NET_PLAYM4_OPEN_FILE_ERROR
+	508: "failed to create thread or event",                          // NET_PLAYM4_CREATE_OBJ_ERROR
+	509: "failed to create DirectDraw",                               // NET_PLAYM4_CREATE_DDRAW_ERROR
+	510: "failed to create back buffer",                              // NET_PLAYM4_CREATE_OFFSCREEN_ERROR
+	511: "buffer full, stream input failed",                          // NET_PLAYM4_BUF_OVER
+	512: "failed to create audio device",                             // NET_PLAYM4_CREATE_SOUND_ERROR
+	513: "failed to set volume",                                      // NET_PLAYM4_SET_VOLUME_ERROR
+	514: "only available when playing a file",                        // NET_PLAYM4_SUPPORT_FILE_ONLY
+	515: "only available when playing a stream",                      // NET_PLAYM4_SUPPORT_STREAM_ONLY
+	516: "system not supported, decoder requires Pentium 3 or later", // NET_PLAYM4_SYS_NOT_SUPPORT
+	517: "no file header",                                            // NET_PLAYM4_FILEHEADER_UNKNOWN
+	518: "decoder and encoder versions do not match",                 // NET_PLAYM4_VERSION_INCORRECT
+	519: "failed to initialize decoder",                              // NET_PALYM4_INIT_DECODER_ERROR
+	520: "file too short or stream unrecognized",                     // NET_PLAYM4_CHECK_FILE_ERROR
+	521: "failed to initialize multimedia clock",                     // NET_PLAYM4_INIT_TIMER_ERROR
+	522: "bit blit failed",                                           // NET_PLAYM4_BLT_ERROR
+	523: "failed to display overlay",                                 // NET_PLAYM4_UPDATE_ERROR
+	524: "failed to open mixed stream file",                          // NET_PLAYM4_OPEN_FILE_ERROR_MULTI
+	525: "failed to open video stream file",                          // NET_PLAYM4_OPEN_FILE_ERROR_VIDEO
+	526: "JPEG compression error",                                    // NET_PLAYM4_JPEG_COMPRESS_ERROR
+	527: "file version not supported",                                // NET_PLAYM4_EXTRACT_NOT_SUPPORT
+	528: "failed to extract file data",                               // NET_PLAYM4_EXTRACT_DATA_ERROR
+
+	// 语音对讲库错误码
+	600: "voice talk library: no error",      // NET_AUDIOINTERCOM_OK
+	601: "not supported",                     // NET_AUDIOINTECOM_ERR_NOTSUPORT
+	602: "memory allocation error",           // NET_AUDIOINTECOM_ERR_ALLOC_MEMERY
+	603: "invalid parameter",                 // NET_AUDIOINTECOM_ERR_PARAMETER
+	604: "incorrect calling order",           // NET_AUDIOINTECOM_ERR_CALL_ORDER
+	605: "device not found",                  // NET_AUDIOINTECOM_ERR_FIND_DEVICE
+	606: "cannot open device",                // NET_AUDIOINTECOM_ERR_OPEN_DEVICE
+	607: "device context error",              // NET_AUDIOINTECOM_ERR_NO_CONTEXT
+	608: "WAV file error",                    // NET_AUDIOINTECOM_ERR_NO_WAVFILE
+	609: "invalid WAV parameter type",        // NET_AUDIOINTECOM_ERR_INVALID_TYPE
+	610: "encoding failed",                   // NET_AUDIOINTECOM_ERR_ENCODE_FAIL
+	611: "decoding failed",                   // NET_AUDIOINTECOM_ERR_DECODE_FAIL
+	612: "playback failed",                   // NET_AUDIOINTECOM_ERR_NO_PLAYBACK
+	613: "noise reduction failed",            // NET_AUDIOINTECOM_ERR_DENOISE_FAIL
+	619: "voice talk library: unknown error", // NET_AUDIOINTECOM_ERR_UNKOWN
+
+	// 能力集解析库错误码
+	1000: "ability node not supported",           // XML_ABILITY_NOTSUPPORT
+	1001: "insufficient output memory",           // XML_ANALYZE_NOENOUGH_BUF
+	1002: "local XML not found",                  // XML_ANALYZE_FIND_LOCALXML_ERROR
+	1003: "failed to load local XML",             // XML_ANALYZE_LOAD_LOCALXML_ERROR
+	1004: "invalid device ability data format",   // XML_NANLYZE_DVR_DATA_FORMAT_ERROR
+	1005: "invalid ability type",                 // XML_ANALYZE_TYPE_ERROR
+	1006: "invalid XML ability node format",      // XML_ANALYZE_XML_NODE_ERROR
+	1007: "invalid input ability XML node value", // XML_INPUT_PARAM_ERROR
+	1008: "XML version mismatch",                 // XML_VERSION_MISMATCH
+
+	// VQD错误码
+	1500: "VQD diagnosis time period conflict",  // NET_ERR_VQD_TIME_CONFLICT
+	1501: "VQD diagnosis plan does not exist",   // NET_ERR_VQD_PLAN_NO_EXIST
+	1502: "VQD monitoring point does not exist", // NET_ERR_VQD_CHAN_NO_EXIST
+	1503: "maximum number of VQD plans reached", // NET_ERR_VQD_CHAN_MAX
+	1504: "maximum number of VQD tasks reached", // NET_ERR_VQD_TASK_MAX
+}
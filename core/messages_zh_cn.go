@@ -0,0 +1,284 @@
+package core
+
+// zhCNMessages 简体中文错误码说明
+// 依据 docs/错误代码及说明.md 整理，152-159 为文档未列出的登录相关错误码
+var zhCNMessages = map[int]string{
+	// 网络通讯库错误码
+	0:   "没有错误",                         // NET_DVR_NOERROR
+	1:   "用户名或密码错误",                     // NET_DVR_PASSWORD_ERROR
+	2:   "权限不足",                         // NET_DVR_NOENOUGHPRI
+	3:   "SDK未初始化",                      // NET_DVR_NOINIT
+	4:   "通道号错误",                        // NET_DVR_CHANNEL_ERROR
+	5:   "连接到设备的用户个数超过最大",               // NET_DVR_OVER_MAXLINK
+	6:   "版本不匹配",                        // NET_DVR_VERSIONNOMATCH
+	7:   "连接设备失败",                       // NET_DVR_NETWORK_FAIL_CONNECT
+	8:   "向设备发送失败",                      // NET_DVR_NETWORK_SEND_ERROR
+	9:   "从设备接收数据失败",                    // NET_DVR_NETWORK_RECV_ERROR
+	10:  "从设备接收数据超时",                    // NET_DVR_NETWORK_RECV_TIMEOUT
+	11:  "传送的数据有误",                      // NET_DVR_NETWORK_ERRORDATA
+	12:  "调用次序错误",                       // NET_DVR_ORDER_ERROR
+	13:  "无此权限",                         // NET_DVR_OPERNOPERMIT
+	14:  "设备命令执行超时",                     // NET_DVR_COMMANDTIMEOUT
+	15:  "串口号错误",                        // NET_DVR_ERRORSERIALPORT
+	16:  "报警端口错误",                       // NET_DVR_ERRORALARMPORT
+	17:  "参数错误",                         // NET_DVR_PARAMETER_ERROR
+	18:  "设备通道处于错误状态",                   // NET_DVR_CHAN_EXCEPTION
+	19:  "设备无硬盘",                        // NET_DVR_NODISK
+	20:  "硬盘号错误",                        // NET_DVR_ERRORDISKNUM
+	21:  "设备硬盘满",                        // NET_DVR_DISK_FULL
+	22:  "设备硬盘出错",                       // NET_DVR_DISK_ERROR
+	23:  "设备不支持该功能",                     // NET_DVR_NOSUPPORT
+	24:  "设备忙",                          // NET_DVR_BUSY
+	25:  "设备修改不成功",                      // NET_DVR_MODIFY_FAIL
+	26:  "密码输入格式不正确",                    // NET_DVR_PASSWORD_FORMAT_ERROR
+	27:  "硬盘正在格式化，不能启动操作",               // NET_DVR_DISK_FORMATING
+	28:  "设备资源不足",                       // NET_DVR_DVRNORESOURCE
+	29:  "设备操作失败",                       // NET_DVR_DVROPRATEFAILED
+	30:  "采集本地音频或打开音频输出失败",              // NET_DVR_OPENHOSTSOUND_FAIL
+	31:  "设备语音对讲被占用",                    // NET_DVR_DVRVOICEOPENED
+	32:  "时间输入不正确",                      // NET_DVR_TIMEINPUTERROR
+	33:  "回放时设备没有指定的文件",                 // NET_DVR_NOSPECFILE
+	34:  "创建文件出错",                       // NET_DVR_CREATEFILE_ERROR
+	35:  "打开文件出错",                       // NET_DVR_FILEOPENFAIL
+	36:  "上次的操作还没有完成",                   // NET_DVR_OPERNOTFINISH
+	37:  "获取当前播放的时间出错",                  // NET_DVR_GETPLAYTIMEFAIL
+	38:  "播放出错",                         // NET_DVR_PLAYFAIL
+	39:  "文件格式不正确",                      // NET_DVR_FILEFORMAT_ERROR
+	40:  "路径错误",                         // NET_DVR_DIR_ERROR
+	41:  "SDK资源分配错误",                    // NET_DVR_ALLOC_RESOURCE_ERROR
+	42:  "声卡模式错误",                       // NET_DVR_AUDIO_MODE_ERROR
+	43:  "缓冲区太小",                        // NET_DVR_NOENOUGH_BUF
+	44:  "创建SOCKET出错",                   // NET_DVR_CREATESOCKET_ERROR
+	45:  "设置SOCKET出错",                   // NET_DVR_SETSOCKET_ERROR
+	46:  "个数达到最大",                       // NET_DVR_MAX_NUM
+	47:  "用户不存在",                        // NET_DVR_USERNOTEXIST
+	48:  "写FLASH出错",                     // NET_DVR_WRITEFLASHERROR
+	49:  "设备升级失败",                       // NET_DVR_UPGRADEFAIL
+	50:  "解码卡已经初始化过",                    // NET_DVR_CARDHAVEINIT
+	51:  "调用播放库中某个函数失败",                 // NET_DVR_PLAYERFAILED
+	52:  "登录设备的用户数达到最大",                 // NET_DVR_MAX_USERNUM
+	53:  "获得本地PC的IP地址或物理地址失败",           // NET_DVR_GETLOCALIPANDMACFAIL
+	54:  "设备该通道没有启动编码",                  // NET_DVR_NOENCODEING
+	55:  "IP地址不匹配",                      // NET_DVR_IPMISMATCH
+	56:  "MAC地址不匹配",                     // NET_DVR_MACMISMATCH
+	57:  "升级文件语言不匹配",                    // NET_DVR_UPGRADELANGMISMATCH
+	58:  "播放器路数达到最大",                    // NET_DVR_MAX_PLAYERPORT
+	59:  "备份设备中没有足够空间进行备份",              // NET_DVR_NOSPACEBACKUP
+	60:  "没有找到指定的备份设备",                  // NET_DVR_NODEVICEBACKUP
+	61:  "图像素位数不符，限24色",                 // NET_DVR_PICTURE_BITS_ERROR
+	62:  "图片高*宽超限，限128*256",             // NET_DVR_PICTURE_DIMENSION_ERROR
+	63:  "图片大小超限，限100K",                 // NET_DVR_PICTURE_SIZ_ERROR
+	64:  "载入当前目录下Player Sdk出错",          // NET_DVR_LOADPLAYERSDKFAILED
+	65:  "找不到Player Sdk中某个函数入口",         // NET_DVR_LOADPLAYERSDKPROC_ERROR
+	66:  "载入当前目录下DSsdk出错",               // NET_DVR_LOADDSSDKFAILED
+	67:  "找不到DsSdk中某个函数入口",              // NET_DVR_LOADDSSDKPROC_ERROR
+	68:  "调用硬解码库DsSdk中某个函数失败",           // NET_DVR_DSSDK_ERROR
+	69:  "声卡被独占",                        // NET_DVR_VOICEMONOPOLIZE
+	70:  "加入多播组失败",                      // NET_DVR_JOINMULTICASTFAILED
+	71:  "建立日志文件目录失败",                   // NET_DVR_CREATEDIR_ERROR
+	72:  "绑定套接字失败",                      // NET_DVR_BINDSOCKET_ERROR
+	73:  "socket连接中断",                   // NET_DVR_SOCKETCLOSE_ERROR
+	74:  "注销时用户ID正在进行某操作",               // NET_DVR_USERID_ISUSING
+	75:  "监听失败",                         // NET_DVR_SOCKETLISTEN_ERROR
+	76:  "程序异常",                         // NET_DVR_PROGRAM_EXCEPTION
+	77:  "写文件失败",                        // NET_DVR_WRITEFILE_FAILED
+	78:  "禁止格式化只读硬盘",                    // NET_DVR_FORMAT_READONLY
+	79:  "远程用户配置结构中存在相同的用户名",            // NET_DVR_WITHSAMEUSERNAME
+	80:  "导入参数时设备型号不匹配",                 // NET_DVR_DEVICETYPE_ERROR
+	81:  "导入参数时语言不匹配",                   // NET_DVR_LANGUAGE_ERROR
+	82:  "导入参数时软件版本不匹配",                 // NET_DVR_PARAVERSION_ERROR
+	83:  "预览时外接IP通道不在线",                 // NET_DVR_IPCHAN_NOTALIVE
+	84:  "加载标准协议通讯库StreamTransClient失败", // NET_DVR_RTSP_SDK_ERROR
+	85:  "加载转封装库失败",                     // NET_DVR_CONVERT_SDK_ERROR
+	86:  "超出最大的IP接入通道数",                 // NET_DVR_IPC_COUNT_OVERFLOW
+	87:  "添加录像标签或者其他操作超出最多支持的个数",        // NET_DVR_MAX_ADD_NUM
+	88:  "图像增强仪参数模式错误",                  // NET_DVR_PARAMMODE_ERROR
+	89:  "码分器不在线",                       // NET_DVR_CODESPITTER_OFFLINE
+	90:  "设备正在备份",                       // NET_DVR_BACKUP_COPYING
+	91:  "通道不支持该操作",                     // NET_DVR_CHAN_NOTSUPPORT
+	92:  "高度线位置太集中或长度线不够倾斜",             // NET_DVR_CALLINEINVALID
+	93:  "取消标定冲突",                       // NET_DVR_CALCANCELCONFLICT
+	94:  "标定点超出范围",                      // NET_DVR_CALPOINTOUTRANGE
+	95:  "尺寸过滤器不符合要求",                   // NET_DVR_FILTERRECTINVALID
+	96:  "设备没有注册到ddns上",                 // NET_DVR_DDNS_DEVOFFLINE
+	97:  "DDNS服务器内部错误",                  // NET_DVR_DDNS_INTER_ERROR
+	100: "加载当前目录下的语音对讲库失败",              // NET_DVR_INTERCOM_SDK_ERROR
+	101: "没有正确的升级包",                     // NET_DVR_NO_CURRENT_UPDATEFILE
+	150: "别名重复（EasyDDNS配置）",             // NET_DVR_ALIAS_DUPLICATE
+	152: "用户名不存在",                       // NET_DVR_USERNAME_NOT_EXIST
+	153: "用户被锁定",                        // NET_ERR_USERNAME_LOCKED
+	154: "无效用户ID",                       // NET_DVR_INVALID_USERID
+	155: "登录版本低",                        // NET_DVR_LOW_LOGIN_VERSION
+	156: "加载libeay32库失败",                // NET_DVR_LOAD_LIBEAY32_DLL_ERROR
+	157: "加载ssleay32库失败",                // NET_DVR_LOAD_SSLEAY32_DLL_ERROR
+	158: "加载libiconv库失败",                // NET_ERR_LOAD_LIBICONV
+	159: "SSL连接失败",                      // NET_ERR_SSL_CONNECT_FAILED
+	800: "网络流量超过设备能力上限",                 // NET_DVR_DEV_NET_OVERFLOW
+	801: "录像文件在录像，无法被锁定",                // NET_DVR_STATUS_RECORDFILE_WRITING_NOT_LOCK
+	802: "由于硬盘太小无法格式化",                  // NET_DVR_STATUS_CANT_FORMAT_LITTLE_DISK
+	821: "通道未绑定或绑定语音对讲失败",               // NET_SDK_ERR_CHAN_AUDIO_BIND
+	822: "设备当前处于N+1模式，不支持设置云存储",         // NET_DVR_N_PLUS_ONE_MODE
+	823: "云存储模式已开启",                     // NET_DVR_CLOUD_STORAGE_OPENED
+	824: "设备处于N+0被接管状态，不允许该操作",          // NET_DVR_ERR_OPER_NOT_ALLOWED
+	825: "设备处于N+0被接管状态，需要获取重定向信息，再重新操作", // NET_DVR_ERR_NEED_RELOCATE
+
+	// RAID错误码
+	200: "名称已存在",                // NET_DVR_NAME_NOT_ONLY
+	201: "阵列达到上限",               // NET_DVR_OVER_MAX_ARRAY
+	202: "虚拟磁盘达到上限",             // NET_DVR_OVER_MAX_VD
+	203: "虚拟磁盘槽位已满",             // NET_DVR_VD_SLOT_EXCEED
+	204: "重建阵列所需物理磁盘状态错误",       // NET_DVR_PD_STATUS_INVALID
+	205: "重建阵列所需物理磁盘为指定热备",      // NET_DVR_PD_BE_DEDICATE_SPARE
+	206: "重建阵列所需物理磁盘非空闲",        // NET_DVR_PD_NOT_FREE
+	207: "不能从当前的阵列类型迁移到新的阵列类型",  // NET_DVR_CANNOT_MIG2NEWMODE
+	208: "迁移操作已暂停",              // NET_DVR_MIG_PAUSE
+	209: "正在执行的迁移操作已取消",         // NET_DVR_MIG_CANCEL
+	210: "阵列上存在虚拟磁盘，无法删除阵列",     // NET_DVR_EXIST_VD
+	211: "对象物理磁盘为虚拟磁盘组成部分且工作正常", // NET_DVR_TARGET_IN_LD_FUNCTIONAL
+	212: "指定的物理磁盘被分配为虚拟磁盘",      // NET_DVR_HD_IS_ASSIGNED_ALREADY
+	213: "物理磁盘数量与指定的RAID等级不匹配",  // NET_DVR_INVALID_HD_COUNT
+	214: "阵列正常，无法重建",            // NET_DVR_LD_IS_FUNCTIONAL
+	215: "存在正在执行的后台任务",          // NET_DVR_BGA_RUNNING
+	216: "无法用ATAPI盘创建虚拟磁盘",      // NET_DVR_LD_NO_ATAPI
+	217: "阵列无需迁移",               // NET_DVR_MIGRATION_NOT_NEED
+	218: "物理磁盘不属于同一类型",          // NET_DVR_HD_TYPE_MISMATCH
+	219: "无虚拟磁盘，无法进行此项操作",       // NET_DVR_NO_LD_IN_DG
+	220: "磁盘空间过小，无法被指定为热备盘",     // NET_DVR_NO_ROOM_FOR_SPARE
+	221: "磁盘已被分配为某阵列热备盘",        // NET_DVR_SPARE_IS_IN_MULTI_DG
+	222: "阵列缺少盘",                // NET_DVR_DG_HAS_MISSING_PD
+	223: "名称为空",                 // NET_DVR_NAME_EMPTY
+	224: "输入参数有误",               // NET_DVR_INPUT_PARAM
+	225: "物理磁盘不可用",              // NET_DVR_PD_NOT_AVAILABLE
+	226: "阵列不可用",                // NET_DVR_ARRAY_NOT_AVAILABLE
+	227: "物理磁盘数不正确",             // NET_DVR_PD_COUNT
+	228: "虚拟磁盘太小",               // NET_DVR_VD_SMALL
+	229: "不存在",                  // NET_DVR_NO_EXIST
+	230: "不支持该操作",               // NET_DVR_NOT_SUPPORT
+	231: "阵列状态不是正常状态",           // NET_DVR_NOT_FUNCTIONAL
+	232: "虚拟磁盘设备节点不存在",          // NET_DVR_DEV_NODE_NOT_FOUND
+	233: "槽位达到上限",               // NET_DVR_SLOT_EXCEED
+	234: "阵列上不存在虚拟磁盘",           // NET_DVR_NO_VD_IN_ARRAY
+	235: "虚拟磁盘槽位无效",             // NET_DVR_VD_SLOT_INVALID
+	236: "所需物理磁盘空间不足",           // NET_DVR_PD_NO_ENOUGH_SPACE
+	237: "只有处于正常状态的阵列才能进行迁移",    // NET_DVR_ARRAY_NONFUNCTION
+	238: "阵列空间不足",               // NET_DVR_ARRAY_NO_ENOUGH_SPACE
+	239: "正在执行安全拔盘或重新扫描",        // NET_DVR_STOPPING_SCANNING_ARRAY
+	240: "不支持创建大于16T的阵列",        // NET_DVR_NOT_SUPPORT_16T
+
+	// N+1错误码
+	803: "远端无法连接",     // NET_SDK_ERR_REMOTE_DISCONNEC
+	804: "备机不能添加备机",   // NET_SDK_ERR_RD_ADD_RD
+	805: "备份盘异常",      // NET_SDK_ERR_BACKUP_DISK_EXCEPT
+	806: "备机数已达上限",    // NET_SDK_ERR_RD_LIMIT
+	807: "添加的备机是工作机",  // NET_SDK_ERR_ADDED_RD_IS_WD
+	808: "添加顺序出错",     // NET_SDK_ERR_ADD_ORDER_WRONG
+	809: "工作机不能添加工作机", // NET_SDK_ERR_WD_ADD_WD
+	810: "工作机CVR服务异常", // NET_SDK_ERR_WD_SERVICE_EXCETP
+	811: "备机CVR服务异常",  // NET_SDK_ERR_RD_SERVICE_EXCETP
+	812: "添加的工作机是备机",  // NET_SDK_ERR_ADDED_WD_IS_RD
+	813: "性能达到上限",     // NET_SDK_ERR_PERFORMANCE_LIMIT
+	814: "添加的设备已经存在",  // NET_SDK_ERR_ADDED_DEVICE_EXIST
+
+	// RTSP通讯库错误码
+	401: "RTSP无权限（服务器返回401）",            // NET_DVR_RTSP_ERROR_NOENOUGHPRI
+	402: "分配资源失败",                       // NET_DVR_RTSP_ERROR_ALLOC_RESOURCE
+	403: "参数错误",                         // NET_DVR_RTSP_ERROR_PARAMETER
+	404: "指定的URL地址不存在（服务器返回404）",        // NET_DVR_RTSP_ERROR_NO_URL
+	406: "用户中途强行退出",                     // NET_DVR_RTSP_ERROR_FORCE_STOP
+	407: "获取RTSP端口错误",                   // NET_DVR_RTSP_GETPORTFAILED
+	410: "RTSP DECRIBE交互错误",             // NET_DVR_RTSP_DESCRIBERROR
+	411: "RTSP DECRIBE发送超时",             // NET_DVR_RTSP_DESCRIBESENDTIMEOUT
+	412: "RTSP DECRIBE发送失败",             // NET_DVR_RTSP_DESCRIBESENDERROR
+	413: "RTSP DECRIBE接收超时",             // NET_DVR_RTSP_DESCRIBERECVTIMEOUT
+	414: "RTSP DECRIBE接收数据错误",           // NET_DVR_RTSP_DESCRIBERECVDATALOST
+	415: "RTSP DECRIBE接收失败",             // NET_DVR_RTSP_DESCRIBERECVERROR
+	416: "RTSP DECRIBE服务器返回错误状态",        // NET_DVR_RTSP_DESCRIBESERVERERR
+	420: "RTSP SETUP交互错误",               // NET_DVR_RTSP_SETUPERROR
+	421: "RTSP SETUP发送超时",               // NET_DVR_RTSP_SETUPSENDTIMEOUT
+	422: "RTSP SETUP发送错误",               // NET_DVR_RTSP_SETUPSENDERROR
+	423: "RTSP SETUP接收超时",               // NET_DVR_RTSP_SETUPRECVTIMEOUT
+	424: "RTSP SETUP接收数据错误",             // NET_DVR_RTSP_SETUPRECVDATALOST
+	425: "RTSP SETUP接收失败",               // NET_DVR_RTSP_SETUPRECVERROR
+	426: "超过服务器最大连接数或服务器资源不足（服务器返回453）", // NET_DVR_RTSP_OVER_MAX_CHAN
+	427: "RTSP SETUP服务器返回错误状态",          // NET_DVR_RTSP_SETUPSERVERERR
+	430: "RTSP PLAY交互错误",                // NET_DVR_RTSP_PLAYERROR
+	431: "RTSP PLAY发送超时",                // NET_DVR_RTSP_PLAYSENDTIMEOUT
+	432: "RTSP PLAY发送错误",                // NET_DVR_RTSP_PLAYSENDERROR
+	433: "RTSP PLAY接收超时",                // NET_DVR_RTSP_PLAYRECVTIMEOUT
+	434: "RTSP PLAY接收数据错误",              // NET_DVR_RTSP_PLAYRECVDATALOST
+	435: "RTSP PLAY接收失败",                // NET_DVR_RTSP_PLAYRECVERROR
+	436: "RTSP PLAY服务器返回错误状态",           // NET_DVR_RTSP_PLAYSERVERERR
+	440: "RTSP TEARDOWN交互错误",            // NET_DVR_RTSP_TEARDOWNERROR
+	441: "RTSP TEARDOWN发送超时",            // NET_DVR_RTSP_TEARDOWNSENDTIMEOUT
+	442: "RTSP TEARDOWN发送错误",            // NET_DVR_RTSP_TEARDOWNSENDERROR
+	443: "RTSP TEARDOWN接收超时",            // NET_DVR_RTSP_TEARDOWNRECVTIMEOUT
+	444: "RTSP TEARDOWN接收数据错误",          // NET_DVR_RTSP_TEARDOWNRECVDATALOST
+	445: "RTSP TEARDOWN接收失败",            // NET_DVR_RTSP_TEARDOWNRECVERROR
+	446: "RTSP TEARDOWN服务器返回错误状态",       // NET_DVR_RTSP_TEARDOWNSERVERERR
+
+	// 播放库错误码
+	500: "播放库：没有错误",                  // NET_PLAYM4_NOERROR
+	501: "输入参数非法",                    // NET_PLAYM4_PARA_OVER
+	502: "调用顺序不对",                    // NET_PLAYM4_ORDER_ERROR
+	503: "多媒体时钟设置失败",                 // NET_PLAYM4_TIMER_ERROR
+	504: "视频解码失败",                    // NET_PLAYM4_DEC_VIDEO_ERROR
+	505: "音频解码失败",                    // NET_PLAYM4_DEC_AUDIO_ERROR
+	506: "分配内存失败",                    // NET_PLAYM4_ALLOC_MEMORY_ERROR
+	507: "文件操作失败",                    // NET_PLAYM4_OPEN_FILE_ERROR
+	508: "创建线程事件等失败",                 // NET_PLAYM4_CREATE_OBJ_ERROR
+	509: "创建directDraw失败",            // NET_PLAYM4_CREATE_DDRAW_ERROR
+	510: "创建后端缓存失败",                  // NET_PLAYM4_CREATE_OFFSCREEN_ERROR
+	511: "缓冲区满，输入流失败",                // NET_PLAYM4_BUF_OVER
+	512: "创建音频设备失败",                  // NET_PLAYM4_CREATE_SOUND_ERROR
+	513: "设置音量失败",                    // NET_PLAYM4_SET_VOLUME_ERROR
+	514: "只能在播放文件时才能使用此接口",           // NET_PLAYM4_SUPPORT_FILE_ONLY
+	515: "只能在播放流时才能使用此接口",            // NET_PLAYM4_SUPPORT_STREAM_ONLY
+	516: "系统不支持，解码器只能工作在Pentium 3以上", // NET_PLAYM4_SYS_NOT_SUPPORT
+	517: "没有文件头",                     // NET_PLAYM4_FILEHEADER_UNKNOWN
+	518: "解码器和编码器版本不对应",              // NET_PLAYM4_VERSION_INCORRECT
+	519: "初始化解码器失败",                  // NET_PALYM4_INIT_DECODER_ERROR
+	520: "文件太短或码流无法识别",               // NET_PLAYM4_CHECK_FILE_ERROR
+	521: "初始化多媒体时钟失败",                // NET_PLAYM4_INIT_TIMER_ERROR
+	522: "位拷贝失败",                     // NET_PLAYM4_BLT_ERROR
+	523: "显示overlay失败",               // NET_PLAYM4_UPDATE_ERROR
+	524: "打开混合流文件失败",                 // NET_PLAYM4_OPEN_FILE_ERROR_MULTI
+	525: "打开视频流文件失败",                 // NET_PLAYM4_OPEN_FILE_ERROR_VIDEO
+	526: "JPEG压缩错误",                  // NET_PLAYM4_JPEG_COMPRESS_ERROR
+	527: "不支持该文件版本",                  // NET_PLAYM4_EXTRACT_NOT_SUPPORT
+	528: "提取文件数据失败",                  // NET_PLAYM4_EXTRACT_DATA_ERROR
+
+	// 语音对讲库错误码
+	600: "语音对讲库：没有错误", // NET_AUDIOINTERCOM_OK
+	601: "不支持",        // NET_AUDIOINTECOM_ERR_NOTSUPORT
+	602: "内存申请错误",     // NET_AUDIOINTECOM_ERR_ALLOC_MEMERY
+	603: "参数错误",       // NET_AUDIOINTECOM_ERR_PARAMETER
+	604: "调用次序错误",     // NET_AUDIOINTECOM_ERR_CALL_ORDER
+	605: "未发现设备",      // NET_AUDIOINTECOM_ERR_FIND_DEVICE
+	606: "不能打开设备",     // NET_AUDIOINTECOM_ERR_OPEN_DEVICE
+	607: "设备上下文出错",    // NET_AUDIOINTECOM_ERR_NO_CONTEXT
+	608: "WAV文件出错",    // NET_AUDIOINTECOM_ERR_NO_WAVFILE
+	609: "无效的WAV参数类型", // NET_AUDIOINTECOM_ERR_INVALID_TYPE
+	610: "编码失败",       // NET_AUDIOINTECOM_ERR_ENCODE_FAIL
+	611: "解码失败",       // NET_AUDIOINTECOM_ERR_DECODE_FAIL
+	612: "播放失败",       // NET_AUDIOINTECOM_ERR_NO_PLAYBACK
+	613: "降噪失败",       // NET_AUDIOINTECOM_ERR_DENOISE_FAIL
+	619: "语音对讲库：未知错误", // NET_AUDIOINTECOM_ERR_UNKOWN
+
+	// 能力集解析库错误码
+	1000: "不支持能力节点获取",     // XML_ABILITY_NOTSUPPORT
+	1001: "输出内存不足",        // XML_ANALYZE_NOENOUGH_BUF
+	1002: "无法找到对应的本地xml",  // XML_ANALYZE_FIND_LOCALXML_ERROR
+	1003: "加载本地xml出错",     // XML_ANALYZE_LOAD_LOCALXML_ERROR
+	1004: "设备能力数据格式错误",    // XML_NANLYZE_DVR_DATA_FORMAT_ERROR
+	1005: "能力集类型错误",       // XML_ANALYZE_TYPE_ERROR
+	1006: "XML能力节点格式错误",   // XML_ANALYZE_XML_NODE_ERROR
+	1007: "输入的能力XML节点值错误", // XML_INPUT_PARAM_ERROR
+	1008: "XML版本不匹配",      // XML_VERSION_MISMATCH
+
+	// VQD错误码
+	1500: "VQD诊断时间段冲突", // NET_ERR_VQD_TIME_CONFLICT
+	1501: "VQD诊断计划不存在", // NET_ERR_VQD_PLAN_NO_EXIST
+	1502: "VQD监控点不存在",  // NET_ERR_VQD_CHAN_NO_EXIST
+	1503: "VQD计划数已达上限", // NET_ERR_VQD_CHAN_MAX
+	1504: "VQD任务数已达上限", // NET_ERR_VQD_TASK_MAX
+}
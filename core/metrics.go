@@ -0,0 +1,90 @@
+package core
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// 指标中的操作名称
+const (
+	OpLogin       = "login"        // 登录设备
+	OpLogout      = "logout"       // 登出设备
+	OpPTZControl  = "ptz_control"  // 云台控制（方向、变倍、聚焦、光圈、辅助设备等）
+	OpPTZPosition = "ptz_position" // 云台定位（查询和设置绝对位置、3D定位）
+	OpPreset      = "preset"       // 预置点
+	OpCruise      = "cruise"       // 巡航
+	OpTrack       = "track"        // 轨迹
+	OpAlarmArm    = "alarm_arm"    // 布防
+	OpAlarmDisarm = "alarm_disarm" // 撤防
+)
+
+// Metrics 指标钩子
+// 通过 SetMetrics 设置后，库在每次SDK调用及会话、布防、报警状态变化时回调；
+// 实现必须是并发安全的，且不能阻塞（报警事件在SDK回调线程中记录）。
+// core/metrics 包（独立模块，主模块不依赖 Prometheus）提供了基于 Prometheus 的实现
+type Metrics interface {
+	// ObserveCall 记录一次SDK调用
+	// operation 为操作名称（Op* 常量），device 为设备标识，code 为错误码（成功为0，非SDK错误为-1）
+	ObserveCall(operation, device string, code int, duration time.Duration)
+	// AddSessions 在线会话数变化（登录成功+1，登出-1）
+	AddSessions(delta int)
+	// AddArmedListeners 布防中的报警监听数变化
+	AddArmedListeners(delta int)
+	// ObserveAlarm 记录收到的一条报警事件
+	ObserveAlarm(device, eventType string)
+}
+
+// nopMetrics 不记录任何指标
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveCall(operation, device string, code int, duration time.Duration) {}
+func (nopMetrics) AddSessions(delta int)                                                  {}
+func (nopMetrics) AddArmedListeners(delta int)                                            {}
+func (nopMetrics) ObserveAlarm(device, eventType string)                                  {}
+
+// metricsHolder 包装 Metrics，使 atomic.Value 中保存的类型始终一致
+type metricsHolder struct {
+	m Metrics
+}
+
+// globalMetrics 全局指标钩子
+var globalMetrics atomic.Value
+
+func init() {
+	globalMetrics.Store(metricsHolder{nopMetrics{}})
+}
+
+// SetMetrics 设置全局指标钩子
+// 应在登录设备之前设置，否则已有会话和布防不会计入在线会话数和布防数
+// 参数：
+//   - m: 指标钩子，nil表示不记录指标（默认）
+func SetMetrics(m Metrics) {
+	if m == nil {
+		m = nopMetrics{}
+	}
+	globalMetrics.Store(metricsHolder{m})
+}
+
+// GetMetrics 获取全局指标钩子，未设置时返回不记录任何指标的实现
+func GetMetrics() Metrics {
+	return globalMetrics.Load().(metricsHolder).m
+}
+
+// ObserveCall 记录一次SDK调用的结果和耗时
+// 参数：
+//   - operation: 操作名称（Op* 常量）
+//   - device: 设备标识
+//   - began: 调用开始时间
+//   - err: 调用结果，*HKError 按错误码记录，其他错误记为-1
+func ObserveCall(operation, device string, began time.Time, err error) {
+	code := 0
+	if err != nil {
+		code = -1
+		var hkErr *HKError
+		if errors.As(err, &hkErr) {
+			code = hkErr.Code
+		}
+	}
+	GetMetrics().ObserveCall(operation, device, code, time.Since(began))
+}
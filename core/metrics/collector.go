@@ -0,0 +1,120 @@
+// Package metrics 提供基于 Prometheus 的指标钩子实现
+//
+// 本包是独立的 Go 模块（github.com/samsaralc/hiksdk/core/metrics），
+// 只有使用它的程序才会引入 Prometheus 依赖。go.mod 依赖主模块的已发布版本，
+// 在仓库中同时修改两个模块时使用本地工作区（go work init . ./core/metrics）。
+//
+// 使用方法：
+//
+//	collector := metrics.NewCollector("hiksdk")
+//	prometheus.MustRegister(collector)
+//	core.SetMetrics(collector)
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/samsaralc/hiksdk/core"
+)
+
+// Collector Prometheus 指标收集器
+// 同时实现 core.Metrics（记录指标）和 prometheus.Collector（导出指标）
+//
+// 导出的指标（名称带命名空间前缀）：
+//   - sdk_calls_total{operation,device,code}: SDK调用次数，code 为错误码（成功为0）
+//   - sdk_call_duration_seconds{operation,device}: SDK调用耗时
+//   - active_sessions: 在线会话数
+//   - armed_listeners: 布防中的报警监听数
+//   - alarm_events_total{device,type}: 收到的报警事件数
+type Collector struct {
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	sessions prometheus.Gauge
+	armed    prometheus.Gauge
+	alarms   *prometheus.CounterVec
+}
+
+// 编译期检查接口实现
+var (
+	_ core.Metrics         = (*Collector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// NewCollector 创建指标收集器
+// 参数：
+//   - namespace: 指标名称的命名空间前缀，空字符串表示不加前缀
+//
+// 返回值：
+//   - *Collector: 指标收集器，需注册到 prometheus.Registerer 并通过 core.SetMetrics 设置
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sdk_calls_total",
+			Help:      "Number of HCNetSDK calls by operation, device and error code (0 on success).",
+		}, []string{"operation", "device", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "sdk_call_duration_seconds",
+			Help:      "Duration of HCNetSDK calls in seconds.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"operation", "device"}),
+		sessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_sessions",
+			Help:      "Number of devices currently logged in.",
+		}),
+		armed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "armed_listeners",
+			Help:      "Number of alarm listeners currently armed.",
+		}),
+		alarms: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "alarm_events_total",
+			Help:      "Number of alarm events received by device and event type.",
+		}, []string{"device", "type"}),
+	}
+}
+
+// ObserveCall 记录一次SDK调用（实现 core.Metrics）
+func (c *Collector) ObserveCall(operation, device string, code int, duration time.Duration) {
+	c.calls.WithLabelValues(operation, device, strconv.Itoa(code)).Inc()
+	c.duration.WithLabelValues(operation, device).Observe(duration.Seconds())
+}
+
+// AddSessions 在线会话数变化（实现 core.Metrics）
+func (c *Collector) AddSessions(delta int) {
+	c.sessions.Add(float64(delta))
+}
+
+// AddArmedListeners 布防中的报警监听数变化（实现 core.Metrics）
+func (c *Collector) AddArmedListeners(delta int) {
+	c.armed.Add(float64(delta))
+}
+
+// ObserveAlarm 记录收到的一条报警事件（实现 core.Metrics）
+func (c *Collector) ObserveAlarm(device, eventType string) {
+	c.alarms.WithLabelValues(device, eventType).Inc()
+}
+
+// Describe 实现 prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.calls.Describe(ch)
+	c.duration.Describe(ch)
+	c.sessions.Describe(ch)
+	c.armed.Describe(ch)
+	c.alarms.Describe(ch)
+}
+
+// Collect 实现 prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.calls.Collect(ch)
+	c.duration.Collect(ch)
+	c.sessions.Collect(ch)
+	c.armed.Collect(ch)
+	c.alarms.Collect(ch)
+}
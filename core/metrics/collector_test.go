@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestCollector 记录的调用、会话、布防和报警按命名空间导出为 Prometheus 指标
+func TestCollector(t *testing.T) {
+	c := NewCollector("hiksdk")
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(c); err != nil {
+		t.Fatalf("注册收集器失败: %v", err)
+	}
+
+	c.ObserveCall("login", "192.168.1.64:8000", 0, 20*time.Millisecond)
+	c.ObserveCall("login", "192.168.1.64:8000", 1, 30*time.Millisecond)
+	c.ObserveCall("preset", "192.168.1.64:8000", 0, time.Millisecond)
+	c.AddSessions(1)
+	c.AddSessions(1)
+	c.AddSessions(-1)
+	c.AddArmedListeners(1)
+	c.ObserveAlarm("192.168.1.64:8000", "移动侦测")
+	c.ObserveAlarm("192.168.1.64:8000", "移动侦测")
+
+	expected := `
+# HELP hiksdk_sdk_calls_total Number of HCNetSDK calls by operation, device and error code (0 on success).
+# TYPE hiksdk_sdk_calls_total counter
+hiksdk_sdk_calls_total{code="0",device="192.168.1.64:8000",operation="login"} 1
+hiksdk_sdk_calls_total{code="0",device="192.168.1.64:8000",operation="preset"} 1
+hiksdk_sdk_calls_total{code="1",device="192.168.1.64:8000",operation="login"} 1
+# HELP hiksdk_active_sessions Number of devices currently logged in.
+# TYPE hiksdk_active_sessions gauge
+hiksdk_active_sessions 1
+# HELP hiksdk_armed_listeners Number of alarm listeners currently armed.
+# TYPE hiksdk_armed_listeners gauge
+hiksdk_armed_listeners 1
+# HELP hiksdk_alarm_events_total Number of alarm events received by device and event type.
+# TYPE hiksdk_alarm_events_total counter
+hiksdk_alarm_events_total{device="192.168.1.64:8000",type="移动侦测"} 2
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"hiksdk_sdk_calls_total", "hiksdk_active_sessions", "hiksdk_armed_listeners", "hiksdk_alarm_events_total")
+	if err != nil {
+		t.Errorf("导出的指标与期望不符: %v", err)
+	}
+
+	if n := testutil.CollectAndCount(c, "hiksdk_sdk_call_duration_seconds"); n != 2 {
+		t.Errorf("耗时指标应有2个序列，实际: %d", n)
+	}
+}
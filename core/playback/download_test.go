@@ -0,0 +1,91 @@
+package playback
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestDownloadByTime 边下载边写入，并报告进度
+func TestDownloadByTime(t *testing.T) {
+	m, fake := newTestManager(t)
+	record := bytes.Repeat([]byte{0x00, 0x00, 0x01, 0xBA}, 1000)
+	fake.SetRecordData(1, record)
+
+	var buf bytes.Buffer
+	var progress []Progress
+	n, err := m.DownloadByTimeContext(context.Background(), 1, day(9, 0), day(10, 0), &buf, &DownloadOptions{
+		StreamType: 1,
+		Interval:   time.Millisecond,
+		OnProgress: func(p Progress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+	if n != int64(len(record)) || !bytes.Equal(buf.Bytes(), record) {
+		t.Fatalf("下载数据错误: %d字节", n)
+	}
+	if len(progress) != 4 || progress[0].Percent != 25 || progress[0].Bytes != 1000 || progress[3].Percent != 100 {
+		t.Errorf("进度报告错误: %+v", progress)
+	}
+
+	calls := fake.CallsTo("GetFileByTimeV40")
+	if cond := calls[0].Args[2].(sdk.PlayCond); cond.Channel != 1 || cond.StreamType != 1 || !cond.Stop.Equal(day(10, 0)) {
+		t.Errorf("下载条件错误: %+v", cond)
+	}
+	if c := fake.CallsTo("PlayBackControlV40"); len(c) != 1 || c[0].Args[1] != sdk.NET_DVR_PLAYSTART {
+		t.Errorf("应发送开始下载命令: %v", c)
+	}
+	if len(fake.CallsTo("StopGetFile")) != 1 {
+		t.Error("下载完成后应停止下载")
+	}
+}
+
+// TestDownloadByTimeCancel 上下文取消时停止下载
+func TestDownloadByTimeCancel(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetRecordData(1, make([]byte, 4000))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	n, err := m.DownloadByTimeContext(ctx, 1, day(9, 0), day(10, 0), &buf, &DownloadOptions{
+		Interval: time.Millisecond,
+		OnProgress: func(p Progress) {
+			if p.Percent >= 50 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("应返回 context.Canceled，实际: %v", err)
+	}
+	if n != 2000 || buf.Len() != 2000 {
+		t.Errorf("取消前应已写入一半数据，实际: %d", n)
+	}
+	if len(fake.CallsTo("StopGetFile")) != 1 {
+		t.Error("取消后应停止下载")
+	}
+}
+
+// TestDownloadByTimeErrors 下载失败
+func TestDownloadByTimeErrors(t *testing.T) {
+	m, fake := newTestManager(t)
+	var buf bytes.Buffer
+	if _, err := m.DownloadByTime(1, day(9, 0), day(10, 0), &buf); err == nil {
+		t.Error("没有录像时应返回错误")
+	}
+
+	fake.SetRecordData(1, make([]byte, 100))
+	fake.FailOnce("GetDownloadPos", sdk.NET_DVR_NETWORK_RECV_ERROR)
+	opts := &DownloadOptions{Interval: time.Millisecond}
+	if _, err := m.DownloadByTimeContext(context.Background(), 1, day(9, 0), day(10, 0), &buf, opts); err == nil {
+		t.Error("获取进度失败时应返回错误")
+	}
+	if len(fake.CallsTo("StopGetFile")) != 1 {
+		t.Error("失败后应停止下载")
+	}
+}
@@ -0,0 +1,214 @@
+// Package playback 录像查找、下载与回放
+//
+// 通过 NET_DVR_FindFile_V40 / NET_DVR_FindNextFile_V40 按时间段查找设备（NVR/DVR）上的录像文件，
+// 通过 NET_DVR_GetFileByTime_V40 按时间段下载录像（PS封装的原始码流），
+// 通过 NET_DVR_PlayBackByTime_V40 / NET_DVR_PlayBackControl_V40 回放录像并控制暂停、倍速和定位
+package playback
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// FileType 录像文件类型（NET_DVR_FILECOND_V40.dwFileType）
+type FileType int
+
+const (
+	FileTimed          FileType = 0    // 定时录像
+	FileMotion         FileType = 1    // 移动侦测
+	FileAlarm          FileType = 2    // 报警触发
+	FileAlarmOrMotion  FileType = 3    // 报警或移动侦测
+	FileAlarmAndMotion FileType = 4    // 报警和移动侦测
+	FileCommand        FileType = 5    // 命令触发
+	FileManual         FileType = 6    // 手动录像
+	FileSmart          FileType = 7    // 智能录像
+	FileAll            FileType = 0xFF // 全部（仅用于查找条件）
+)
+
+// String 返回录像文件类型的中文描述
+func (t FileType) String() string {
+	switch t {
+	case FileTimed:
+		return "定时录像"
+	case FileMotion:
+		return "移动侦测"
+	case FileAlarm:
+		return "报警触发"
+	case FileAlarmOrMotion:
+		return "报警或移动侦测"
+	case FileAlarmAndMotion:
+		return "报警和移动侦测"
+	case FileCommand:
+		return "命令触发"
+	case FileManual:
+		return "手动录像"
+	case FileSmart:
+		return "智能录像"
+	case FileAll:
+		return "全部"
+	default:
+		return fmt.Sprintf("未知录像类型(%d)", int(t))
+	}
+}
+
+// FindTimeout 查找录像文件的超时时间（设备一直返回"正在查找"时）
+const FindTimeout = 30 * time.Second
+
+// findPollInterval 设备正在查找时的重试间隔
+var findPollInterval = 100 * time.Millisecond
+
+// File 录像文件
+// 设备时间没有时区信息，按本地时区解释
+type File struct {
+	Name   string    // 文件名
+	Start  time.Time // 开始时间
+	End    time.Time // 结束时间
+	Size   int64     // 文件大小（字节）
+	Type   FileType  // 录像类型
+	Locked bool      // 是否锁定
+}
+
+// Duration 返回录像时长
+func (f File) Duration() time.Duration {
+	return f.End.Sub(f.Start)
+}
+
+// Manager 录像查找、下载与回放
+// 记住正在进行的回放，CloseAll 时统一停止
+type Manager struct {
+	mu       sync.Mutex
+	backend  sdk.Backend       // SDK后端
+	login    *core.LoginHandle // 登录句柄
+	sessions []*Session        // 正在进行的回放
+}
+
+// NewManager 创建录像查找、下载与回放（使用默认SDK后端）
+// 参数：
+//   - userID: 登录句柄
+func NewManager(userID int) *Manager {
+	return NewManagerWithBackend(sdk.Default(), userID)
+}
+
+// NewManagerWithBackend 使用指定SDK后端创建录像查找、下载与回放
+// 参数：
+//   - backend: SDK后端（测试时可传入 sdk.NewFake()）
+//   - userID: 登录句柄
+func NewManagerWithBackend(backend sdk.Backend, userID int) *Manager {
+	return NewManagerWithHandle(backend, core.NewLoginHandle(userID))
+}
+
+// NewManagerWithHandle 使用共享登录句柄创建录像查找、下载与回放
+// 重新登录后更新句柄即可继续使用
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+func NewManagerWithHandle(backend sdk.Backend, login *core.LoginHandle) *Manager {
+	return &Manager{
+		backend: backend,
+		login:   login,
+	}
+}
+
+// track 记住新的回放，顺便清理调用方已关闭的回放
+func (m *Manager) track(s *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	running := m.sessions[:0]
+	for _, session := range m.sessions {
+		if session.IsRunning() {
+			running = append(running, session)
+		}
+	}
+	m.sessions = append(running, s)
+}
+
+// CloseAll 停止所有正在进行的回放
+// 登出设备之前调用；auth.Device.Close 会自动调用
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (m *Manager) CloseAll() error {
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for _, s := range sessions {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateRange 检查通道号和时间段
+func validateRange(channel int, start, end time.Time) error {
+	if channel < 1 {
+		return fmt.Errorf("无效的通道号: %d", channel)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("无效的时间段: %s - %s", start.Format(time.DateTime), end.Format(time.DateTime))
+	}
+	return nil
+}
+
+// FindFiles 查找指定时间段内的录像文件
+// 返回与时间段有交集的所有文件，没有录像时返回空列表
+// 参数：
+//   - channel: 通道号
+//   - start: 开始时间
+//   - end: 结束时间
+//   - fileType: 录像类型，FileAll 表示全部
+//
+// 返回值：
+//   - []File: 录像文件列表
+//   - error: 错误信息，成功时为nil
+func (m *Manager) FindFiles(channel int, start, end time.Time, fileType FileType) ([]File, error) {
+	if err := validateRange(channel, start, end); err != nil {
+		return nil, err
+	}
+	userID := m.login.ID()
+	if userID < 0 {
+		return nil, fmt.Errorf("无效的登录ID：%d", userID)
+	}
+
+	operation := fmt.Sprintf("查找录像文件[通道:%d]", channel)
+	cond := &sdk.FileCond{Channel: channel, FileType: int(fileType), Start: start, Stop: end}
+	handle := m.backend.FindFileV40(userID, cond)
+	if handle < 0 {
+		return nil, core.NewHKErrorFrom(m.backend, operation)
+	}
+	defer m.backend.FindCloseV30(handle)
+
+	files := []File{}
+	deadline := time.Now().Add(FindTimeout)
+	for {
+		var data sdk.FindData
+		switch status := m.backend.FindNextFileV40(handle, &data); status {
+		case sdk.NET_DVR_FILE_SUCCESS:
+			files = append(files, File{
+				Name:   data.FileName,
+				Start:  data.Start,
+				End:    data.Stop,
+				Size:   data.FileSize,
+				Type:   FileType(data.FileType),
+				Locked: data.Locked,
+			})
+		case sdk.NET_DVR_ISFINDING:
+			if time.Now().After(deadline) {
+				return nil, fmt.Errorf("%s超时（%v）", operation, FindTimeout)
+			}
+			time.Sleep(findPollInterval)
+		case sdk.NET_DVR_FILE_NOFIND, sdk.NET_DVR_NOMOREFILE:
+			m.login.Logger().Debug("查找录像文件成功", "channel", channel, "files", len(files))
+			return files, nil
+		default:
+			return nil, core.NewHKErrorFrom(m.backend, operation)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package playback
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// newTestManager 创建基于模拟后端的录像查找与下载
+func newTestManager(t *testing.T) (*Manager, *sdk.Fake) {
+	t.Helper()
+	findPollInterval = time.Millisecond
+	fake, userID := sdk.NewLoggedInFake()
+	return NewManagerWithBackend(fake, userID), fake
+}
+
+// day 测试日期中的时刻
+func day(hour, minute int) time.Time {
+	return time.Date(2024, 5, 1, hour, minute, 0, 0, time.Local)
+}
+
+// TestFindFiles 返回与时间段有交集的文件，并等待设备查找完成
+func TestFindFiles(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetRecordFiles(1, []sdk.FindData{
+		{FileName: "ch01_00000000001", Start: day(8, 0), Stop: day(9, 0), FileSize: 1 << 20, FileType: 0},
+		{FileName: "ch01_00000000002", Start: day(9, 0), Stop: day(9, 30), FileSize: 2 << 20, FileType: 1, Locked: true},
+		{FileName: "ch01_00000000003", Start: day(12, 0), Stop: day(13, 0), FileType: 0},
+	})
+
+	files, err := m.FindFiles(1, day(8, 30), day(10, 0), FileAll)
+	if err != nil {
+		t.Fatalf("查找录像失败: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("应找到2个文件，实际: %+v", files)
+	}
+	if f := files[1]; f.Name != "ch01_00000000002" || f.Type != FileMotion || !f.Locked || f.Size != 2<<20 || f.Duration() != 30*time.Minute {
+		t.Errorf("文件信息错误: %+v", f)
+	}
+	cond := fake.CallsTo("FindFileV40")[0].Args[1].(sdk.FileCond)
+	if cond.Channel != 1 || cond.FileType != 0xFF || !cond.Start.Equal(day(8, 30)) {
+		t.Errorf("查找条件错误: %+v", cond)
+	}
+	if len(fake.CallsTo("FindCloseV30")) != 1 {
+		t.Error("查找结束后应关闭查找句柄")
+	}
+
+	// 按类型过滤；没有文件时返回空列表
+	if files, err := m.FindFiles(1, day(0, 0), day(23, 0), FileMotion); err != nil || len(files) != 1 {
+		t.Errorf("按类型查找错误: %+v %v", files, err)
+	}
+	if files, err := m.FindFiles(2, day(0, 0), day(23, 0), FileAll); err != nil || files == nil || len(files) != 0 {
+		t.Errorf("没有录像时应返回空列表: %+v %v", files, err)
+	}
+}
+
+// TestFindFilesErrors 参数错误和SDK错误
+func TestFindFilesErrors(t *testing.T) {
+	m, fake := newTestManager(t)
+	if _, err := m.FindFiles(1, day(10, 0), day(9, 0), FileAll); err == nil {
+		t.Error("结束时间早于开始时间应返回错误")
+	}
+	if _, err := m.FindFiles(0, day(9, 0), day(10, 0), FileAll); err == nil {
+		t.Error("无效的通道号应返回错误")
+	}
+
+	fake.FailOnce("FindNextFileV40", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+	_, err := m.FindFiles(1, day(9, 0), day(10, 0), FileAll)
+	var hkErr *core.HKError
+	if !errors.As(err, &hkErr) || hkErr.Code != sdk.NET_DVR_NETWORK_RECV_TIMEOUT {
+		t.Fatalf("应返回SDK错误，实际: %v", err)
+	}
+	if len(fake.CallsTo("FindCloseV30")) != 1 {
+		t.Error("查找失败时也应关闭查找句柄")
+	}
+}
@@ -0,0 +1,398 @@
+package playback
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/preview"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// 回放速度档位：每档速度加倍（快放）或减半（慢放）
+const (
+	MaxSpeed = 4  // 最快档位（16倍速）
+	MinSpeed = -4 // 最慢档位（1/16倍速）
+)
+
+// playNetworkError NET_DVR_PLAYGETPOS 返回的网络异常
+const playNetworkError = 200
+
+// errCallbackMode 已设置数据回调时不能再通过 Read 读取
+var errCallbackMode = errors.New("已设置数据回调（Options.Handler），不能同时使用 Read")
+
+// Options 回放参数
+type Options struct {
+	StreamType int             // 码流类型：0-主码流，1-子码流，2-三码流
+	BufferSize int             // Read 方式下缓存的数据包个数，0表示使用 preview.DefaultBufferSize
+	Handler    preview.Handler // 数据回调，设置后数据只投递给回调，Read 将返回错误
+}
+
+// validate 检查参数取值范围
+func (o *Options) validate() error {
+	if o.StreamType < 0 || o.StreamType > 2 {
+		return fmt.Errorf("无效的码流类型: %d", o.StreamType)
+	}
+	if o.BufferSize < 0 {
+		return fmt.Errorf("缓存大小不能为负数: %d", o.BufferSize)
+	}
+	return nil
+}
+
+// Session 录像回放
+// 码流数据的获取方式与实时预览（preview.Stream）相同（二选一）：
+//   - 通过 Options.Handler 设置回调，接收所有类型的数据包
+//   - 通过 Read 读取系统头和流数据拼接成的原始码流（PS），Close 后返回 io.EOF
+//
+// 回放不随重新登录恢复，登录会话失效后需要重新创建
+type Session struct {
+	mu         sync.Mutex
+	backend    sdk.Backend  // SDK后端
+	logger     *slog.Logger // 日志记录器
+	channel    int          // 通道号
+	start      time.Time    // 回放开始时间
+	end        time.Time    // 回放结束时间
+	playHandle int          // 回放句柄，停止后为-1
+	paused     bool         // 是否暂停
+	speed      int          // 速度档位：0-正常，>0 快放，<0 慢放
+
+	dataMu    sync.RWMutex    // 保护 handler、packets 和 accepting（回调中不能使用 mu，避免与 StopPlayBack 死锁）
+	handler   preview.Handler // 数据回调
+	packets   chan []byte     // Read 方式的数据缓存；关闭后保留以便读完剩余数据
+	accepting bool            // packets 是否仍接收数据
+	dropped   atomic.Uint64   // 缓存已满时丢弃的数据包个数
+
+	readMu  sync.Mutex // 保护 pending
+	pending []byte     // 上次 Read 未读完的数据
+}
+
+// PlayByTime 按时间段回放录像
+// 参数：
+//   - channel: 通道号
+//   - start: 开始时间
+//   - end: 结束时间
+//   - opts: 回放参数，nil表示使用默认参数（主码流、Read 方式）
+//
+// 返回值：
+//   - *Session: 录像回放，不再使用时调用其 Close
+//   - error: 错误信息，成功时为nil
+func (m *Manager) PlayByTime(channel int, start, end time.Time, opts *Options) (*Session, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if o.BufferSize == 0 {
+		o.BufferSize = preview.DefaultBufferSize
+	}
+	if err := validateRange(channel, start, end); err != nil {
+		return nil, err
+	}
+	userID := m.login.ID()
+	if userID < 0 {
+		return nil, fmt.Errorf("无效的登录ID：%d", userID)
+	}
+
+	s := &Session{
+		backend:    m.backend,
+		logger:     m.login.Logger().With(slog.Int("channel", channel)),
+		channel:    channel,
+		start:      start,
+		end:        end,
+		playHandle: -1,
+		handler:    o.Handler,
+		packets:    make(chan []byte, o.BufferSize),
+		accepting:  true,
+	}
+
+	operation := fmt.Sprintf("按时间回放录像[通道:%d]", channel)
+	cond := &sdk.PlayCond{Channel: channel, Start: start, Stop: end, StreamType: o.StreamType}
+	handle := m.backend.PlayBackByTimeV40(userID, cond)
+	if handle < 0 {
+		return nil, core.NewHKErrorFrom(m.backend, operation)
+	}
+	// 先注册数据回调再开始播放，避免丢失系统头
+	if !m.backend.SetPlayDataCallBackV40(handle, s.callback) {
+		err := core.NewHKErrorFrom(m.backend, operation)
+		m.backend.StopPlayBack(handle)
+		return nil, err
+	}
+	if _, ok := m.backend.PlayBackControlV40(handle, sdk.NET_DVR_PLAYSTART, nil, nil); !ok {
+		err := core.NewHKErrorFrom(m.backend, operation)
+		m.backend.StopPlayBack(handle)
+		return nil, err
+	}
+	s.playHandle = handle
+	m.track(s)
+
+	s.logger.Info("录像回放启动成功", "handle", handle, "start", start, "end", end)
+	return s, nil
+}
+
+// callback 回放数据回调，由SDK后端调用
+func (s *Session) callback(playHandle, dataType int, data []byte) {
+	s.dataMu.RLock()
+	handler, packets := s.handler, s.packets
+	if handler == nil && s.accepting && (dataType == preview.NET_DVR_SYSHEAD || dataType == preview.NET_DVR_STREAMDATA) {
+		// 缓存已满时丢弃，避免阻塞SDK的回调线程
+		select {
+		case packets <- data:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+	s.dataMu.RUnlock()
+
+	if handler != nil {
+		handler(preview.Packet{Type: dataType, Data: data})
+	}
+}
+
+// Read 读取原始码流（实现 io.Reader）
+// 依次返回系统头和流数据；Close 之后读完已缓存的数据再返回 io.EOF
+func (s *Session) Read(p []byte) (int, error) {
+	s.readMu.Lock()
+	defer s.readMu.Unlock()
+
+	for len(s.pending) == 0 {
+		s.dataMu.RLock()
+		handler, packets := s.handler, s.packets
+		s.dataMu.RUnlock()
+
+		if handler != nil {
+			return 0, errCallbackMode
+		}
+		data, ok := <-packets
+		if !ok {
+			return 0, io.EOF
+		}
+		s.pending = data
+	}
+
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Dropped 返回 Read 方式下因缓存已满而丢弃的数据包个数
+func (s *Session) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Channel 返回通道号
+func (s *Session) Channel() int {
+	return s.channel
+}
+
+// Handle 返回回放句柄，停止后为-1
+func (s *Session) Handle() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.playHandle
+}
+
+// IsRunning 是否正在回放
+func (s *Session) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.playHandle >= 0
+}
+
+// control 发送回放控制命令（调用方必须持有锁）
+func (s *Session) control(command int, in, out []byte, operation string) (int, error) {
+	if s.playHandle < 0 {
+		return 0, fmt.Errorf("%s失败：回放已停止", operation)
+	}
+	n, ok := s.backend.PlayBackControlV40(s.playHandle, command, in, out)
+	if !ok {
+		return 0, core.NewHKErrorFrom(s.backend, operation)
+	}
+	return n, nil
+}
+
+// Pause 暂停回放，已暂停时直接返回
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Session) Pause() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paused {
+		return nil
+	}
+	if _, err := s.control(sdk.NET_DVR_PLAYPAUSE, nil, nil, fmt.Sprintf("暂停回放[通道:%d]", s.channel)); err != nil {
+		return err
+	}
+	s.paused = true
+	return nil
+}
+
+// Resume 恢复回放，未暂停时直接返回
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Session) Resume() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.paused {
+		return nil
+	}
+	if _, err := s.control(sdk.NET_DVR_PLAYRESTART, nil, nil, fmt.Sprintf("恢复回放[通道:%d]", s.channel)); err != nil {
+		return err
+	}
+	s.paused = false
+	return nil
+}
+
+// IsPaused 是否已暂停
+func (s *Session) IsPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+// Fast 快放：速度加倍，已是最快档位（16倍速）时返回错误
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Session) Fast() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.speed >= MaxSpeed {
+		return fmt.Errorf("已是最快回放速度: %v倍", speedRate(s.speed))
+	}
+	if _, err := s.control(sdk.NET_DVR_PLAYFAST, nil, nil, fmt.Sprintf("快放[通道:%d]", s.channel)); err != nil {
+		return err
+	}
+	s.speed++
+	return nil
+}
+
+// Slow 慢放：速度减半，已是最慢档位（1/16倍速）时返回错误
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Session) Slow() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.speed <= MinSpeed {
+		return fmt.Errorf("已是最慢回放速度: %v倍", speedRate(s.speed))
+	}
+	if _, err := s.control(sdk.NET_DVR_PLAYSLOW, nil, nil, fmt.Sprintf("慢放[通道:%d]", s.channel)); err != nil {
+		return err
+	}
+	s.speed--
+	return nil
+}
+
+// Normal 恢复正常速度
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Session) Normal() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.control(sdk.NET_DVR_PLAYNORMAL, nil, nil, fmt.Sprintf("正常速度回放[通道:%d]", s.channel)); err != nil {
+		return err
+	}
+	s.speed = 0
+	return nil
+}
+
+// Speed 返回当前回放速度倍数（如 2 表示2倍速，0.5 表示1/2倍速）
+func (s *Session) Speed() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return speedRate(s.speed)
+}
+
+// speedRate 将速度档位转换为速度倍数
+func speedRate(speed int) float64 {
+	if speed >= 0 {
+		return float64(int(1) << speed)
+	}
+	return 1 / float64(int(1)<<-speed)
+}
+
+// Seek 定位到指定的绝对时间
+// 参数：
+//   - t: 目标时间，必须在回放时间段内
+//
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Session) Seek(t time.Time) error {
+	if t.Before(s.start) || t.After(s.end) {
+		return fmt.Errorf("定位时间 %s 不在回放时间段内", t.Format(time.DateTime))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.control(sdk.NET_DVR_PLAYSETTIME, sdk.EncodeTime(t), nil, fmt.Sprintf("回放定位[通道:%d]", s.channel))
+	return err
+}
+
+// OSDTime 获取当前回放画面的OSD时间（按本地时区）
+// 返回值：
+//   - time.Time: OSD时间
+//   - error: 错误信息，成功时为nil
+func (s *Session) OSDTime() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.playHandle < 0 {
+		return time.Time{}, fmt.Errorf("获取回放OSD时间失败：回放已停止")
+	}
+	t, ok := s.backend.GetPlayBackOsdTime(s.playHandle)
+	if !ok {
+		return time.Time{}, core.NewHKErrorFrom(s.backend, fmt.Sprintf("获取回放OSD时间[通道:%d]", s.channel))
+	}
+	return t, nil
+}
+
+// Progress 获取回放进度
+// 返回值：
+//   - int: 回放进度（0-100），100表示回放结束
+//   - error: 错误信息，成功时为nil；网络异常时返回错误
+func (s *Session) Progress() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	operation := fmt.Sprintf("获取回放进度[通道:%d]", s.channel)
+	out := make([]byte, 4)
+	if _, err := s.control(sdk.NET_DVR_PLAYGETPOS, nil, out, operation); err != nil {
+		return 0, err
+	}
+	pos := int(int32(binary.LittleEndian.Uint32(out)))
+	if pos == playNetworkError {
+		return 0, fmt.Errorf("%s失败：网络异常", operation)
+	}
+	return pos, nil
+}
+
+// Close 停止回放
+// 停止后 Read 读完已缓存的数据返回 io.EOF；重复调用是安全的
+// 停止命令失败时回放同样视为已关闭（Read 照常结束），并返回该错误
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 停止失败（如会话已失效）时句柄同样不再可用，仍然结束 Read
+	var err error
+	if s.playHandle >= 0 {
+		if !s.backend.StopPlayBack(s.playHandle) {
+			err = core.NewHKErrorFrom(s.backend, fmt.Sprintf("停止回放[通道:%d]", s.channel))
+		}
+		s.playHandle = -1
+		s.logger.Info("录像回放已停止")
+	}
+
+	s.dataMu.Lock()
+	defer s.dataMu.Unlock()
+	if s.accepting {
+		close(s.packets)
+		s.accepting = false
+	}
+	return err
+}
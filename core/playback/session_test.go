@@ -0,0 +1,171 @@
+package playback
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/preview"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestSessionRead 回放数据与实时预览一样通过 Read 读取
+func TestSessionRead(t *testing.T) {
+	m, fake := newTestManager(t)
+	s, err := m.PlayByTime(1, day(9, 0), day(10, 0), &Options{StreamType: 1})
+	if err != nil {
+		t.Fatalf("启动回放失败: %v", err)
+	}
+	handle := s.Handle()
+	if cond := fake.CallsTo("PlayBackByTimeV40")[0].Args[1].(sdk.PlayCond); cond.StreamType != 1 || !cond.Start.Equal(day(9, 0)) {
+		t.Errorf("回放条件错误: %+v", cond)
+	}
+	if c := fake.CallsTo("PlayBackControlV40"); len(c) != 1 || c[0].Args[1] != sdk.NET_DVR_PLAYSTART {
+		t.Errorf("应发送开始播放命令: %v", c)
+	}
+
+	fake.EmitPlayData(handle, preview.NET_DVR_SYSHEAD, []byte("IMKH"))
+	fake.EmitPlayData(handle, preview.NET_DVR_PRIVATE_DATA, []byte("skip"))
+	fake.EmitPlayData(handle, preview.NET_DVR_STREAMDATA, []byte{0x00, 0x00, 0x01, 0xBA})
+	if err := s.Close(); err != nil {
+		t.Fatalf("停止回放失败: %v", err)
+	}
+	data, err := io.ReadAll(s)
+	if err != nil || string(data) != "IMKH\x00\x00\x01\xBA" {
+		t.Errorf("读取数据错误: % X %v", data, err)
+	}
+	if s.IsRunning() || len(fake.CallsTo("StopPlayBack")) != 1 {
+		t.Error("关闭后应停止回放")
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("重复关闭不应报错: %v", err)
+	}
+	if err := s.Pause(); err == nil {
+		t.Error("停止后控制应返回错误")
+	}
+}
+
+// TestSessionCloseError 停止回放失败时仍然结束 Read 并返回错误
+func TestSessionCloseError(t *testing.T) {
+	m, fake := newTestManager(t)
+	s, err := m.PlayByTime(1, day(9, 0), day(10, 0), nil)
+	if err != nil {
+		t.Fatalf("启动回放失败: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Read(make([]byte, 8))
+		done <- err
+	}()
+
+	fake.FailWith("StopPlayBack", sdk.NET_DVR_USERNOTEXIST)
+	if err := s.Close(); err == nil {
+		t.Fatal("停止失败时应返回错误")
+	}
+	if s.IsRunning() {
+		t.Error("停止失败后不应处于回放状态")
+	}
+	select {
+	case err := <-done:
+		if err != io.EOF {
+			t.Errorf("Read 应返回 io.EOF，实际: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("停止失败后 Read 仍然阻塞")
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("重复关闭不应报错: %v", err)
+	}
+}
+
+// TestSessionHandler 设置数据回调后数据只投递给回调
+func TestSessionHandler(t *testing.T) {
+	m, fake := newTestManager(t)
+	var packets []preview.Packet
+	s, err := m.PlayByTime(1, day(9, 0), day(10, 0), &Options{
+		Handler: func(p preview.Packet) { packets = append(packets, p) },
+	})
+	if err != nil {
+		t.Fatalf("启动回放失败: %v", err)
+	}
+	defer s.Close()
+
+	fake.EmitPlayData(s.Handle(), preview.NET_DVR_STREAMDATA, []byte{1, 2, 3})
+	if len(packets) != 1 || packets[0].Type != preview.NET_DVR_STREAMDATA {
+		t.Errorf("回调收到的数据错误: %+v", packets)
+	}
+	if _, err := s.Read(make([]byte, 10)); err == nil {
+		t.Error("回调方式下 Read 应返回错误")
+	}
+}
+
+// TestSessionControl 暂停、倍速、定位和OSD时间
+func TestSessionControl(t *testing.T) {
+	m, fake := newTestManager(t)
+	s, err := m.PlayByTime(1, day(9, 0), day(10, 0), nil)
+	if err != nil {
+		t.Fatalf("启动回放失败: %v", err)
+	}
+	handle := s.Handle()
+
+	s.Pause()
+	s.Pause()
+	if paused, _, _ := fake.PlayBackState(handle); !paused || !s.IsPaused() {
+		t.Error("应已暂停")
+	}
+	s.Resume()
+	if paused, _, _ := fake.PlayBackState(handle); paused || s.IsPaused() {
+		t.Error("应已恢复")
+	}
+	if c := fake.CallsTo("PlayBackControlV40"); len(c) != 3 {
+		t.Errorf("重复暂停不应再发送命令: %v", c)
+	}
+
+	for i := 0; i < MaxSpeed; i++ {
+		if err := s.Fast(); err != nil {
+			t.Fatalf("快放失败: %v", err)
+		}
+	}
+	if err := s.Fast(); err == nil || s.Speed() != 16 {
+		t.Errorf("16倍速后再快放应返回错误，当前速度: %v", s.Speed())
+	}
+	s.Normal()
+	s.Slow()
+	if _, speed, _ := fake.PlayBackState(handle); speed != -1 || s.Speed() != 0.5 {
+		t.Errorf("慢放速度错误: %d %v", speed, s.Speed())
+	}
+
+	if err := s.Seek(day(11, 0)); err == nil {
+		t.Error("超出回放时间段的定位应返回错误")
+	}
+	if err := s.Seek(day(9, 30)); err != nil {
+		t.Fatalf("定位失败: %v", err)
+	}
+	if osd, err := s.OSDTime(); err != nil || !osd.Equal(day(9, 30)) {
+		t.Errorf("OSD时间错误: %v %v", osd, err)
+	}
+	if pos, err := s.Progress(); err != nil || pos != 50 {
+		t.Errorf("回放进度错误: %d %v", pos, err)
+	}
+
+	// CloseAll 停止所有回放
+	if err := m.CloseAll(); err != nil || s.IsRunning() {
+		t.Errorf("CloseAll 应停止回放: %v", err)
+	}
+}
+
+// TestPlayByTimeErrors 启动失败时释放回放句柄
+func TestPlayByTimeErrors(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.FailOnce("PlayBackControlV40", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+	if _, err := m.PlayByTime(1, day(9, 0), day(10, 0), nil); err == nil {
+		t.Fatal("开始播放失败时应返回错误")
+	}
+	if len(fake.CallsTo("StopPlayBack")) != 1 {
+		t.Error("启动失败时应停止回放")
+	}
+	if _, err := m.PlayByTime(1, day(9, 0), day(10, 0), &Options{StreamType: 5}); err == nil {
+		t.Error("无效的码流类型应返回错误")
+	}
+}
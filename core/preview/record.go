@@ -0,0 +1,126 @@
+package preview
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/samsaralc/hiksdk/core/media"
+)
+
+// Recording 预览录像
+// 在后台读取预览码流、解封装并写入录像文件，直到 Stop 或预览关闭
+type Recording struct {
+	rec      *media.Recorder
+	logger   *slog.Logger
+	stop     chan struct{} // 关闭后读取协程停止等待码流
+	stopOnce sync.Once
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error // 录像过程中的错误
+}
+
+// Record 将预览码流录制为 MP4/TS 文件
+// 通过 Read 读取码流，因此不能与 Options.Handler 或其他 Read 方同时使用；
+// 预览关闭（Close）后自动完成最后一个文件
+// 参数：
+//   - opts: 录像参数（输出目录、格式、切分条件等），Prefix 为空时使用 "ch<通道号>"
+//
+// 返回值：
+//   - *Recording: 录像
+//   - error: 错误信息，成功时为nil
+func (s *Stream) Record(opts media.RecordOptions) (*Recording, error) {
+	if !s.IsRunning() {
+		return nil, fmt.Errorf("通道%d未在预览，不能录像", s.channel)
+	}
+	s.dataMu.RLock()
+	callbackMode := s.handler != nil
+	s.dataMu.RUnlock()
+	if callbackMode {
+		return nil, errCallbackMode
+	}
+
+	if opts.Prefix == "" {
+		opts.Prefix = fmt.Sprintf("ch%d", s.channel)
+	}
+	rec, err := media.NewRecorder(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recording{rec: rec, logger: s.logger(), stop: make(chan struct{}), done: make(chan struct{})}
+	go r.run(media.NewDemuxer(stoppableReader{s, r.stop}))
+	r.logger.Info("开始录像", "dir", opts.Dir)
+	return r, nil
+}
+
+// run 读取帧并写入录像，直到码流结束或录像停止
+func (r *Recording) run(d *media.Demuxer) {
+	defer close(r.done)
+
+	var err error
+	for {
+		var f *media.Frame
+		if f, err = d.ReadFrame(); err != nil {
+			break
+		}
+		if err = r.rec.WriteFrame(f); err != nil {
+			break
+		}
+	}
+	// 码流结束（预览关闭）和录像停止都是正常结束
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, media.ErrRecorderClosed) {
+		err = nil
+	}
+	if e := r.rec.Close(); err == nil {
+		err = e
+	}
+	r.mu.Lock()
+	r.err = err
+	r.mu.Unlock()
+}
+
+// stoppableReader 录像使用的码流读取方，录像停止后不再等待码流
+type stoppableReader struct {
+	s    *Stream
+	stop <-chan struct{}
+}
+
+func (r stoppableReader) Read(p []byte) (int, error) {
+	return r.s.read(p, r.stop)
+}
+
+// Stop 停止录像并完成当前文件
+// 返回时文件已写入磁盘，可以直接播放，且后台读取已经结束（可以再次调用 Record）；重复调用是安全的
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (r *Recording) Stop() error {
+	err := r.rec.Close()
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+	if err != nil {
+		return err
+	}
+	r.logger.Info("录像已停止", "files", len(r.rec.Files()))
+	return nil
+}
+
+// Done 返回录像结束时关闭的通道（预览关闭或 Stop 之后）
+func (r *Recording) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err 返回录像过程中的错误，正常结束或未结束时为nil
+func (r *Recording) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Files 返回已完成的录像文件
+func (r *Recording) Files() []media.RecordFile {
+	return r.rec.Files()
+}
@@ -0,0 +1,95 @@
+package preview
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/media"
+)
+
+// TestStreamRecord 录制预览码流，预览关闭后完成文件
+func TestStreamRecord(t *testing.T) {
+	s, fake := newTestStream(t)
+	if _, err := s.Record(media.RecordOptions{Dir: t.TempDir()}); err == nil {
+		t.Error("未启动预览时录像应返回错误")
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+
+	data, err := os.ReadFile("../media/testdata/h264.ps")
+	if err != nil {
+		t.Fatalf("读取样例文件失败: %v", err)
+	}
+	rec, err := s.Record(media.RecordOptions{Dir: t.TempDir(), Format: media.FormatTS})
+	if err != nil {
+		t.Fatalf("开始录像失败: %v", err)
+	}
+
+	handle := s.Handle()
+	fake.EmitRealData(handle, NET_DVR_SYSHEAD, data[:media.HikHeaderSize])
+	for rest := data[media.HikHeaderSize:]; len(rest) > 0; {
+		n := min(len(rest), 1000)
+		fake.EmitRealData(handle, NET_DVR_STREAMDATA, rest[:n])
+		rest = rest[n:]
+	}
+	s.Close()
+
+	select {
+	case <-rec.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("预览关闭后录像应结束")
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatalf("录像出错: %v", err)
+	}
+	files := rec.Files()
+	if len(files) != 1 || files[0].Frames != 5 {
+		t.Fatalf("录像文件错误: %+v", files)
+	}
+	if info, err := os.Stat(files[0].Path); err != nil || info.Size() == 0 {
+		t.Errorf("录像文件应已写入: %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Errorf("结束后停止不应报错: %v", err)
+	}
+}
+
+// TestStreamRecordStopStalled 码流中断时 Stop 仍结束后台读取，之后可以重新录像
+func TestStreamRecordStopStalled(t *testing.T) {
+	s, _ := newTestStream(t)
+	if err := s.Start(); err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+	defer s.Close()
+
+	rec, err := s.Record(media.RecordOptions{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("开始录像失败: %v", err)
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- rec.Stop() }()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("停止录像失败: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("码流中断时 Stop 不应阻塞")
+	}
+	select {
+	case <-rec.Done():
+	default:
+		t.Fatal("Stop 返回时后台读取应已结束")
+	}
+
+	next, err := s.Record(media.RecordOptions{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("重新录像失败: %v", err)
+	}
+	if err := next.Stop(); err != nil {
+		t.Errorf("停止录像失败: %v", err)
+	}
+}
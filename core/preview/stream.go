@@ -0,0 +1,372 @@
+package preview
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// 码流类型（来自官方SDK）
+const (
+	// STREAM_TYPE_MAIN 主码流
+	STREAM_TYPE_MAIN = 0
+	// STREAM_TYPE_SUB 子码流
+	STREAM_TYPE_SUB = 1
+	// STREAM_TYPE_THIRD 三码流
+	STREAM_TYPE_THIRD = 2
+)
+
+// 实时数据类型（来自官方SDK）
+const (
+	// NET_DVR_SYSHEAD 系统头数据（40字节的海康媒体头，每次预览开始时回调一次）
+	NET_DVR_SYSHEAD = 1
+	// NET_DVR_STREAMDATA 流数据（PS/RTP 封装的视频或复合流）
+	NET_DVR_STREAMDATA = 2
+	// NET_DVR_AUDIOSTREAMDATA 音频流数据
+	NET_DVR_AUDIOSTREAMDATA = 3
+	// NET_DVR_PRIVATE_DATA 私有数据
+	NET_DVR_PRIVATE_DATA = 112
+)
+
+// LinkMode 取流连接方式
+type LinkMode int
+
+const (
+	LinkTCP       LinkMode = 0 // TCP
+	LinkUDP       LinkMode = 1 // UDP
+	LinkMulticast LinkMode = 2 // 多播
+	LinkRTP       LinkMode = 3 // RTP
+	LinkRTPRTSP   LinkMode = 4 // RTP/RTSP
+	LinkRTSPHTTP  LinkMode = 5 // RTSP/HTTP
+)
+
+// DefaultBufferSize Read 方式下缓存的默认数据包个数
+const DefaultBufferSize = 256
+
+// errCallbackMode 已设置数据回调时不能再通过 Read 读取
+var errCallbackMode = errors.New("已设置数据回调（Options.Handler），不能同时使用 Read")
+
+// Options 预览参数
+type Options struct {
+	StreamType int      // 码流类型：STREAM_TYPE_MAIN、STREAM_TYPE_SUB、STREAM_TYPE_THIRD
+	LinkMode   LinkMode // 取流连接方式
+	Blocked    bool     // 是否阻塞取流
+	BufferSize int      // Read 方式下缓存的数据包个数，0表示使用 DefaultBufferSize
+	Handler    Handler  // 数据回调，设置后数据只投递给回调，Read 将返回错误
+}
+
+// DefaultOptions 返回默认预览参数（主码流、TCP、非阻塞取流）
+func DefaultOptions() Options {
+	return Options{
+		StreamType: STREAM_TYPE_MAIN,
+		LinkMode:   LinkTCP,
+		BufferSize: DefaultBufferSize,
+	}
+}
+
+// validate 检查参数取值范围
+func (o *Options) validate() error {
+	if o.StreamType < STREAM_TYPE_MAIN || o.StreamType > STREAM_TYPE_THIRD {
+		return fmt.Errorf("无效的码流类型: %d", o.StreamType)
+	}
+	if o.LinkMode < LinkTCP || o.LinkMode > LinkRTSPHTTP {
+		return fmt.Errorf("无效的连接方式: %d", o.LinkMode)
+	}
+	if o.BufferSize < 0 {
+		return fmt.Errorf("缓存大小不能为负数: %d", o.BufferSize)
+	}
+	return nil
+}
+
+// Packet 一包实时数据
+type Packet struct {
+	Type int    // 数据类型：NET_DVR_SYSHEAD、NET_DVR_STREAMDATA 等
+	Data []byte // 数据内容
+}
+
+// Handler 实时数据回调
+// 在SDK的回调线程中同步调用，应尽快返回，否则会阻塞取流
+type Handler func(Packet)
+
+// Stream 实时预览
+// 启动后码流数据有两种获取方式（二选一）：
+//   - 通过 Options.Handler 设置回调，接收所有类型的数据包
+//   - 通过 Read 读取系统头和流数据拼接成的原始码流（PS/RTP），Close 后返回 io.EOF
+type Stream struct {
+	mu         sync.Mutex
+	backend    sdk.Backend       // SDK后端
+	login      *core.LoginHandle // 登录句柄
+	channel    int               // 通道号
+	opts       Options           // 预览参数（重新启动时沿用）
+	realHandle int               // 预览句柄
+	playing    bool              // 是否处于预览状态（Start成功后至Close前），用于重新启动
+
+	dataMu    sync.RWMutex  // 保护 handler、packets 和 accepting（回调中不能使用 mu，避免与 StopRealPlay 死锁）
+	handler   Handler       // 数据回调
+	packets   chan []byte   // Read 方式的数据缓存，从未启动时为nil；关闭后保留以便读完剩余数据
+	accepting bool          // packets 是否仍接收数据
+	dropped   atomic.Uint64 // 缓存已满时丢弃的数据包个数
+
+	readMu  sync.Mutex // 保护 pending
+	pending []byte     // 上次 Read 未读完的数据
+}
+
+// NewStream 创建实时预览（使用默认SDK后端）
+// 参数：
+//   - userID: 登录句柄
+//   - channel: 通道号
+func NewStream(userID int, channel int) *Stream {
+	return NewStreamWithBackend(sdk.Default(), userID, channel)
+}
+
+// NewStreamWithBackend 使用指定SDK后端创建实时预览
+// 参数：
+//   - backend: SDK后端（测试时可传入 sdk.NewFake()）
+//   - userID: 登录句柄
+//   - channel: 通道号
+func NewStreamWithBackend(backend sdk.Backend, userID int, channel int) *Stream {
+	return NewStreamWithHandle(backend, core.NewLoginHandle(userID), channel)
+}
+
+// NewStreamWithHandle 使用共享登录句柄创建实时预览
+// 重新登录后更新句柄并调用 Restart 即可恢复预览
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+//   - channel: 通道号
+func NewStreamWithHandle(backend sdk.Backend, login *core.LoginHandle, channel int) *Stream {
+	return &Stream{
+		backend:    backend,
+		login:      login,
+		channel:    channel,
+		opts:       DefaultOptions(),
+		realHandle: -1,
+	}
+}
+
+// Channel 返回通道号
+func (s *Stream) Channel() int {
+	return s.channel
+}
+
+// Start 启动实时预览（使用默认预览参数）
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Stream) Start() error {
+	return s.StartWithOptions(nil)
+}
+
+// StartWithOptions 使用指定预览参数启动实时预览
+// 已在预览时直接返回；参数在重新启动（Restart）时沿用
+// 参数：
+//   - opts: 预览参数，nil表示使用 DefaultOptions()
+//
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Stream) StartWithOptions(opts *Options) error {
+	o := DefaultOptions()
+	if opts != nil {
+		o = *opts
+	}
+	if err := o.validate(); err != nil {
+		return err
+	}
+	if o.BufferSize == 0 {
+		o.BufferSize = DefaultBufferSize
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.playing {
+		return nil
+	}
+	s.opts = o
+
+	s.dataMu.Lock()
+	s.handler = o.Handler
+	s.packets = make(chan []byte, o.BufferSize)
+	s.accepting = true
+	s.dataMu.Unlock()
+
+	if err := s.start(); err != nil {
+		s.closePackets()
+		return err
+	}
+	s.playing = true
+	return nil
+}
+
+// start 调用SDK开始取流（调用方必须持有锁）
+func (s *Stream) start() error {
+	userID := s.login.ID()
+	if userID < 0 {
+		return fmt.Errorf("无效的登录ID：%d", userID)
+	}
+
+	info := &sdk.PreviewInfo{
+		Channel:    s.channel,
+		StreamType: s.opts.StreamType,
+		LinkMode:   int(s.opts.LinkMode),
+		Blocked:    s.opts.Blocked,
+	}
+	handle := s.backend.RealPlayV40(userID, info, s.callback)
+	if handle < 0 {
+		return core.NewHKErrorFrom(s.backend, fmt.Sprintf("启动实时预览[通道:%d]", s.channel))
+	}
+	s.realHandle = handle
+
+	s.logger().Info("实时预览启动成功", "handle", handle)
+	return nil
+}
+
+// callback 实时数据回调，由SDK后端调用
+func (s *Stream) callback(realHandle, dataType int, data []byte) {
+	s.dataMu.RLock()
+	handler, packets := s.handler, s.packets
+	if handler == nil && s.accepting && (dataType == NET_DVR_SYSHEAD || dataType == NET_DVR_STREAMDATA) {
+		// 缓存已满时丢弃，避免阻塞SDK的回调线程
+		select {
+		case packets <- data:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+	s.dataMu.RUnlock()
+
+	if handler != nil {
+		handler(Packet{Type: dataType, Data: data})
+	}
+}
+
+// Read 读取原始码流（实现 io.Reader）
+// 依次返回系统头和流数据，不包含音频分离数据和私有数据；
+// 未启动时返回 io.EOF；Close 之后读完已缓存的数据再返回 io.EOF
+func (s *Stream) Read(p []byte) (int, error) {
+	return s.read(p, nil)
+}
+
+// read 读取原始码流，stop 关闭时停止等待并返回 io.EOF（nil 表示一直等待）
+// 未读完的数据保留，下一个读取方可以继续读取
+func (s *Stream) read(p []byte, stop <-chan struct{}) (int, error) {
+	s.readMu.Lock()
+	defer s.readMu.Unlock()
+
+	for len(s.pending) == 0 {
+		s.dataMu.RLock()
+		handler, packets := s.handler, s.packets
+		s.dataMu.RUnlock()
+
+		if handler != nil {
+			return 0, errCallbackMode
+		}
+		if packets == nil {
+			return 0, io.EOF
+		}
+		var data []byte
+		var ok bool
+		select {
+		case data, ok = <-packets:
+		case <-stop:
+		}
+		if !ok {
+			return 0, io.EOF
+		}
+		s.pending = data
+	}
+
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Dropped 返回 Read 方式下因缓存已满而丢弃的数据包个数
+func (s *Stream) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Handle 返回预览句柄，未启动时为-1
+func (s *Stream) Handle() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.realHandle
+}
+
+// logger 获取日志记录器，日志附带登录ID和通道号
+func (s *Stream) logger() *slog.Logger {
+	return s.login.Logger().With(slog.Int("channel", s.channel))
+}
+
+// IsRunning 是否正在预览
+func (s *Stream) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.realHandle >= 0
+}
+
+// IsClosed 预览是否已关闭（未启动或已调用 Close）
+// 重新启动（Restart）失败时预览句柄无效，但预览未关闭，可在下次 Restart 时恢复
+func (s *Stream) IsClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.playing
+}
+
+// Close 停止实时预览
+// 停止取流后 Read 返回 io.EOF；停止失败时同样结束取流，再返回停止的错误；重复调用是安全的
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 停止失败（如会话已失效）时句柄同样不再可用，仍然结束 Read
+	var err error
+	if s.realHandle >= 0 {
+		if !s.backend.StopRealPlay(s.realHandle) {
+			err = core.NewHKErrorFrom(s.backend, fmt.Sprintf("停止实时预览[通道:%d]", s.channel))
+		}
+		s.realHandle = -1
+		s.logger().Info("实时预览已停止")
+	}
+	s.playing = false
+	s.closePackets()
+	return err
+}
+
+// closePackets 关闭 Read 方式的数据缓存
+func (s *Stream) closePackets() {
+	s.dataMu.Lock()
+	defer s.dataMu.Unlock()
+	if s.accepting {
+		close(s.packets)
+		s.accepting = false
+	}
+}
+
+// Restart 重新启动预览
+// 用于重新登录之后：旧的预览句柄随旧会话失效，尽力停止后使用新的登录ID重新取流，
+// Read 方式下已缓存的数据保留，读取方无需感知
+// 未启动（或已关闭）的预览不做任何操作
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (s *Stream) Restart() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.playing {
+		return nil
+	}
+	// 旧句柄通常已随会话失效，停止失败可以忽略
+	if s.realHandle >= 0 {
+		s.backend.StopRealPlay(s.realHandle)
+		s.realHandle = -1
+	}
+	return s.start()
+}
@@ -0,0 +1,188 @@
+package preview
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// newTestStream 创建基于模拟后端的实时预览
+func newTestStream(t *testing.T) (*Stream, *sdk.Fake) {
+	t.Helper()
+	fake, userID := sdk.NewLoggedInFake()
+	return NewStreamWithBackend(fake, userID, 1), fake
+}
+
+// TestStreamRead Read 依次返回系统头和流数据，Close 后返回 io.EOF
+func TestStreamRead(t *testing.T) {
+	s, fake := newTestStream(t)
+
+	opts := DefaultOptions()
+	opts.StreamType = STREAM_TYPE_SUB
+	if err := s.StartWithOptions(&opts); err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+	calls := fake.CallsTo("RealPlayV40")
+	want := sdk.PreviewInfo{Channel: 1, StreamType: STREAM_TYPE_SUB, LinkMode: int(LinkTCP)}
+	if len(calls) != 1 || calls[0].Args[1] != want {
+		t.Fatalf("预览参数错误: %v，期望 %+v", calls, want)
+	}
+
+	handle := s.Handle()
+	fake.EmitRealData(handle, NET_DVR_SYSHEAD, []byte("IMKH"))
+	fake.EmitRealData(handle, NET_DVR_PRIVATE_DATA, []byte("private"))
+	fake.EmitRealData(handle, NET_DVR_STREAMDATA, []byte{0x00, 0x00, 0x01, 0xBA})
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("停止预览失败: %v", err)
+	}
+	if len(fake.CallsTo("StopRealPlay")) != 1 {
+		t.Error("应调用 StopRealPlay")
+	}
+
+	// Close 之前已缓存的数据仍可读出
+	data, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatalf("读取码流失败: %v", err)
+	}
+	if want := []byte("IMKH\x00\x00\x01\xBA"); !bytes.Equal(data, want) {
+		t.Errorf("码流内容错误: % X，期望 % X", data, want)
+	}
+	if fake.EmitRealData(handle, NET_DVR_STREAMDATA, []byte{1}) {
+		t.Error("停止后预览句柄应失效")
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("重复关闭不应报错: %v", err)
+	}
+}
+
+// TestStreamCloseError 停止预览失败时仍结束取流，阻塞中的 Read 返回 io.EOF
+func TestStreamCloseError(t *testing.T) {
+	s, fake := newTestStream(t)
+	if err := s.Start(); err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Read(make([]byte, 8))
+		done <- err
+	}()
+
+	fake.FailWith("StopRealPlay", sdk.NET_DVR_USERNOTEXIST)
+	if err := s.Close(); err == nil {
+		t.Fatal("停止失败时应返回错误")
+	}
+	if s.IsRunning() {
+		t.Error("停止失败后不应处于预览状态")
+	}
+	select {
+	case err := <-done:
+		if err != io.EOF {
+			t.Errorf("Read 应返回 io.EOF，实际: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("停止失败后 Read 仍然阻塞")
+	}
+}
+
+// TestStreamHandler 设置回调后所有数据都投递给回调，Read 返回错误
+func TestStreamHandler(t *testing.T) {
+	s, fake := newTestStream(t)
+
+	var packets []Packet
+	opts := DefaultOptions()
+	opts.Handler = func(p Packet) { packets = append(packets, p) }
+	if err := s.StartWithOptions(&opts); err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+	defer s.Close()
+
+	fake.EmitRealData(s.Handle(), NET_DVR_SYSHEAD, []byte{1})
+	fake.EmitRealData(s.Handle(), NET_DVR_AUDIOSTREAMDATA, []byte{2})
+	if len(packets) != 2 || packets[0].Type != NET_DVR_SYSHEAD || packets[1].Type != NET_DVR_AUDIOSTREAMDATA {
+		t.Errorf("回调数据错误: %+v", packets)
+	}
+	if _, err := s.Read(make([]byte, 8)); err == nil {
+		t.Error("回调方式下 Read 应返回错误")
+	}
+}
+
+// TestStreamDropWhenFull 缓存已满时丢弃数据，不阻塞回调
+func TestStreamDropWhenFull(t *testing.T) {
+	s, fake := newTestStream(t)
+
+	opts := DefaultOptions()
+	opts.BufferSize = 2
+	if err := s.StartWithOptions(&opts); err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 5; i++ {
+		fake.EmitRealData(s.Handle(), NET_DVR_STREAMDATA, []byte{byte(i)})
+	}
+	if s.Dropped() != 3 {
+		t.Errorf("应丢弃3包数据，实际: %d", s.Dropped())
+	}
+}
+
+// TestStreamStartError 参数越界或SDK失败时返回错误
+func TestStreamStartError(t *testing.T) {
+	s, fake := newTestStream(t)
+
+	for _, opts := range []Options{{StreamType: 3}, {LinkMode: 6}, {BufferSize: -1}} {
+		if err := s.StartWithOptions(&opts); err == nil {
+			t.Errorf("参数 %+v 应返回错误", opts)
+		}
+	}
+	if calls := fake.CallsTo("RealPlayV40"); len(calls) != 0 {
+		t.Errorf("参数越界时不应调用SDK: %v", calls)
+	}
+
+	fake.FailOnce("RealPlayV40", sdk.NET_DVR_CHANNEL_ERROR)
+	if err := s.Start(); err == nil {
+		t.Fatal("SDK失败时应返回错误")
+	}
+	if s.IsRunning() {
+		t.Error("启动失败后不应处于预览状态")
+	}
+	if _, err := s.Read(make([]byte, 8)); err != io.EOF {
+		t.Errorf("未启动时 Read 应返回 io.EOF，实际: %v", err)
+	}
+}
+
+// TestStreamRestart 重新启动时使用新的登录ID，已缓存的数据保留
+func TestStreamRestart(t *testing.T) {
+	s, fake := newTestStream(t)
+
+	if err := s.Restart(); err != nil || s.IsRunning() {
+		t.Fatalf("未启动的预览不应重新启动: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("启动预览失败: %v", err)
+	}
+	defer s.Close()
+	fake.EmitRealData(s.Handle(), NET_DVR_SYSHEAD, []byte{1})
+
+	newID := fake.LoginDevice()
+	s.login.Set(newID)
+	if err := s.Restart(); err != nil {
+		t.Fatalf("重新启动预览失败: %v", err)
+	}
+	calls := fake.CallsTo("RealPlayV40")
+	if len(calls) != 2 || calls[1].Args[0] != newID {
+		t.Errorf("应使用新的登录ID重新取流: %v", calls)
+	}
+	fake.EmitRealData(s.Handle(), NET_DVR_STREAMDATA, []byte{2})
+
+	buf := make([]byte, 1)
+	for _, want := range []byte{1, 2} {
+		if n, err := s.Read(buf); n != 1 || err != nil || buf[0] != want {
+			t.Errorf("读取数据错误: %d %v %v，期望 %d", n, err, buf, want)
+		}
+	}
+}
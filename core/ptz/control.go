@@ -1,17 +1,14 @@
 package ptz
 
-/*
-#include <stdio.h>
-#include <stdlib.h>
-#include "../hiksdk_wrapper.h"
-*/
-import "C"
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
 )
 
 // ==================== 云台移动命令常量（来自官方文档表 5.10）====================
@@ -96,17 +93,38 @@ const (
 // Controller PTZ统一控制器
 // 封装云台移动、相机控制、辅助设备控制的所有操作
 type Controller struct {
-	userID  int // 登录句柄
-	channel int // 通道号
+	backend sdk.Backend       // SDK后端
+	login   *core.LoginHandle // 登录句柄
+	channel int               // 通道号
 }
 
-// NewController 创建PTZ控制器
+// NewController 创建PTZ控制器（使用默认SDK后端）
 // 参数：
 //   - userID: 登录句柄
 //   - channel: 通道号
 func NewController(userID int, channel int) *Controller {
+	return NewControllerWithBackend(sdk.Default(), userID, channel)
+}
+
+// NewControllerWithBackend 使用指定SDK后端创建PTZ控制器
+// 参数：
+//   - backend: SDK后端（测试时可传入 sdk.NewFake()）
+//   - userID: 登录句柄
+//   - channel: 通道号
+func NewControllerWithBackend(backend sdk.Backend, userID int, channel int) *Controller {
+	return NewControllerWithHandle(backend, core.NewLoginHandle(userID), channel)
+}
+
+// NewControllerWithHandle 使用共享登录句柄创建PTZ控制器
+// 重新登录后更新句柄即可，无需重新创建控制器
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+//   - channel: 通道号
+func NewControllerWithHandle(backend sdk.Backend, login *core.LoginHandle, channel int) *Controller {
 	return &Controller{
-		userID:  userID,
+		backend: backend,
+		login:   login,
 		channel: channel,
 	}
 }
@@ -177,6 +195,88 @@ func (c *Controller) DownRight(speed int, duration time.Duration) error {
 	return c.move(DOWN_RIGHT, speed, duration)
 }
 
+// ==================== 云台移动控制（带持续时间，支持上下文取消）====================
+
+// UpContext 云台上仰（到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - speed: 速度（1-7）
+//   - duration: 最长持续时间
+func (c *Controller) UpContext(ctx context.Context, speed int, duration time.Duration) error {
+	return c.moveContext(ctx, TILT_UP, speed, duration)
+}
+
+// DownContext 云台下俯（到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - speed: 速度（1-7）
+//   - duration: 最长持续时间
+func (c *Controller) DownContext(ctx context.Context, speed int, duration time.Duration) error {
+	return c.moveContext(ctx, TILT_DOWN, speed, duration)
+}
+
+// LeftContext 云台左转（到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - speed: 速度（1-7）
+//   - duration: 最长持续时间
+func (c *Controller) LeftContext(ctx context.Context, speed int, duration time.Duration) error {
+	return c.moveContext(ctx, PAN_LEFT, speed, duration)
+}
+
+// RightContext 云台右转（到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - speed: 速度（1-7）
+//   - duration: 最长持续时间
+func (c *Controller) RightContext(ctx context.Context, speed int, duration time.Duration) error {
+	return c.moveContext(ctx, PAN_RIGHT, speed, duration)
+}
+
+// UpLeftContext 云台上仰并左转（到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - speed: 速度（1-7）
+//   - duration: 最长持续时间
+func (c *Controller) UpLeftContext(ctx context.Context, speed int, duration time.Duration) error {
+	return c.moveContext(ctx, UP_LEFT, speed, duration)
+}
+
+// UpRightContext 云台上仰并右转（到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - speed: 速度（1-7）
+//   - duration: 最长持续时间
+func (c *Controller) UpRightContext(ctx context.Context, speed int, duration time.Duration) error {
+	return c.moveContext(ctx, UP_RIGHT, speed, duration)
+}
+
+// DownLeftContext 云台下俯并左转（到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - speed: 速度（1-7）
+//   - duration: 最长持续时间
+func (c *Controller) DownLeftContext(ctx context.Context, speed int, duration time.Duration) error {
+	return c.moveContext(ctx, DOWN_LEFT, speed, duration)
+}
+
+// DownRightContext 云台下俯并右转（到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - speed: 速度（1-7）
+//   - duration: 最长持续时间
+func (c *Controller) DownRightContext(ctx context.Context, speed int, duration time.Duration) error {
+	return c.moveContext(ctx, DOWN_RIGHT, speed, duration)
+}
+
 // AutoScan 云台左右自动扫描（持续扫描，需要手动停止）
 // 参数：
 //   - speed: 速度（1-7）
@@ -190,7 +290,7 @@ func (c *Controller) AutoScan(speed int) error {
 		return fmt.Errorf("启动自动扫描失败: %w", err)
 	}
 
-	log.Printf("✓ 启动自动扫描（通道%d，速度%d）", c.channel, speed)
+	c.logger().Debug("启动自动扫描", "command", PAN_AUTO, "speed", speed)
 	return nil
 }
 
@@ -200,7 +300,7 @@ func (c *Controller) StopAutoScan() error {
 		return fmt.Errorf("停止自动扫描失败: %w", err)
 	}
 
-	log.Printf("✓ 停止自动扫描（通道%d）", c.channel)
+	c.logger().Debug("停止自动扫描", "command", PAN_AUTO)
 	return nil
 }
 
@@ -346,6 +446,62 @@ func (c *Controller) IrisClose(duration time.Duration) error {
 	return c.adjustCamera(IRIS_CLOSE, duration, "光圈缩小")
 }
 
+// ==================== 相机控制（带持续时间，支持上下文取消）====================
+
+// ZoomInContext 焦距放大（拉近，到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - duration: 最长持续时间
+func (c *Controller) ZoomInContext(ctx context.Context, duration time.Duration) error {
+	return c.adjustCameraContext(ctx, ZOOM_IN, duration, "焦距放大")
+}
+
+// ZoomOutContext 焦距缩小（拉远，到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - duration: 最长持续时间
+func (c *Controller) ZoomOutContext(ctx context.Context, duration time.Duration) error {
+	return c.adjustCameraContext(ctx, ZOOM_OUT, duration, "焦距缩小")
+}
+
+// FocusNearContext 焦点前调（聚焦近处，到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - duration: 最长持续时间
+func (c *Controller) FocusNearContext(ctx context.Context, duration time.Duration) error {
+	return c.adjustCameraContext(ctx, FOCUS_NEAR, duration, "焦点前调")
+}
+
+// FocusFarContext 焦点后调（聚焦远处，到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - duration: 最长持续时间
+func (c *Controller) FocusFarContext(ctx context.Context, duration time.Duration) error {
+	return c.adjustCameraContext(ctx, FOCUS_FAR, duration, "焦点后调")
+}
+
+// IrisOpenContext 光圈扩大（变亮，到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - duration: 最长持续时间
+func (c *Controller) IrisOpenContext(ctx context.Context, duration time.Duration) error {
+	return c.adjustCameraContext(ctx, IRIS_OPEN, duration, "光圈扩大")
+}
+
+// IrisCloseContext 光圈缩小（变暗，到达持续时间或上下文结束时停止）
+// 上下文被取消或超时时立即发送停止命令，并返回 ctx.Err()；停止命令也失败时返回同时包装两者的错误（使用 errors.Is 判断）
+// 参数：
+//   - ctx: 上下文
+//   - duration: 最长持续时间
+func (c *Controller) IrisCloseContext(ctx context.Context, duration time.Duration) error {
+	return c.adjustCameraContext(ctx, IRIS_CLOSE, duration, "光圈缩小")
+}
+
 // ==================== 相机控制（手动开始/停止）====================
 
 // StartZoomIn 开始焦距放大（需手动调用StopZoomIn停止）
@@ -474,25 +630,17 @@ func (c *Controller) AuxDevice2Off() error {
 
 // move 云台移动（带速度和时长）
 func (c *Controller) move(cmd, speed int, duration time.Duration) error {
+	return c.moveContext(context.Background(), cmd, speed, duration)
+}
+
+// moveContext 云台移动（带速度和时长，可通过上下文提前结束）
+func (c *Controller) moveContext(ctx context.Context, cmd, speed int, duration time.Duration) error {
 	// 验证速度
 	if err := c.validateSpeed(speed); err != nil {
 		return err
 	}
 
-	// 开始移动
-	if err := c.controlWithSpeed(cmd, PTZ_START, speed); err != nil {
-		return err
-	}
-
-	// 等待指定时间
-	time.Sleep(duration)
-
-	// 停止移动
-	if err := c.controlWithSpeed(cmd, PTZ_STOP, speed); err != nil {
-		return err
-	}
-
-	return nil
+	return c.runTimed(ctx, cmd, speed, duration)
 }
 
 // startMove 开始云台移动（手动控制）
@@ -505,7 +653,7 @@ func (c *Controller) startMove(cmd, speed int, actionName string) error {
 		return fmt.Errorf("开始%s失败: %w", actionName, err)
 	}
 
-	log.Printf("✓ 开始%s（通道%d，速度%d）", actionName, c.channel, speed)
+	c.logger().Debug("开始"+actionName, "command", cmd, "speed", speed)
 	return nil
 }
 
@@ -515,27 +663,65 @@ func (c *Controller) stopMove(cmd int, actionName string) error {
 		return fmt.Errorf("停止%s失败: %w", actionName, err)
 	}
 
-	log.Printf("✓ 停止%s（通道%d）", actionName, c.channel)
+	c.logger().Debug("停止"+actionName, "command", cmd)
 	return nil
 }
 
 // adjustCamera 相机调整（带时长）
 func (c *Controller) adjustCamera(cmd int, duration time.Duration, actionName string) error {
-	// 开始调整
-	if err := c.controlWithSpeed(cmd, PTZ_START, DefaultSpeed); err != nil {
+	return c.adjustCameraContext(context.Background(), cmd, duration, actionName)
+}
+
+// adjustCameraContext 相机调整（带时长，可通过上下文提前结束）
+func (c *Controller) adjustCameraContext(ctx context.Context, cmd int, duration time.Duration, actionName string) error {
+	if err := c.runTimed(ctx, cmd, DefaultSpeed, duration); err != nil {
 		return fmt.Errorf("%s失败: %w", actionName, err)
 	}
 
-	// 等待指定时间
-	time.Sleep(duration)
+	c.logger().Debug(actionName, "command", cmd, "duration", duration)
+	return nil
+}
 
-	// 停止调整
-	if err := c.controlWithSpeed(cmd, PTZ_STOP, DefaultSpeed); err != nil {
-		return fmt.Errorf("停止%s失败: %w", actionName, err)
+// runTimed 发送开始命令，等待指定时长或上下文结束后发送停止命令
+// 只要尝试过开始命令，无论其是否成功、上下文是否被取消，都会发送停止命令，
+// 避免开始命令实际已生效（如响应超时）而云台持续转动
+func (c *Controller) runTimed(ctx context.Context, cmd, speed int, duration time.Duration) error {
+	// 上下文已结束时不再开始动作
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	log.Printf("✓ %s（通道%d，持续%v）", actionName, c.channel, duration)
-	return nil
+	// 开始动作
+	startErr := c.controlWithSpeed(cmd, PTZ_START, speed)
+
+	// 开始成功时等待指定时间，期间可被上下文中断
+	var waitErr error
+	if startErr == nil {
+		timer := time.NewTimer(duration)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			waitErr = ctx.Err()
+		}
+	}
+
+	// 停止动作
+	stopErr := c.controlWithSpeed(cmd, PTZ_STOP, speed)
+	if stopErr != nil {
+		stopErr = fmt.Errorf("停止失败: %w", stopErr)
+	}
+
+	switch {
+	case startErr != nil:
+		return startErr
+	case waitErr == nil:
+		return stopErr
+	case stopErr == nil:
+		return waitErr
+	default:
+		return errors.Join(waitErr, stopErr)
+	}
 }
 
 // startCamera 开始相机调整（手动控制）
@@ -544,7 +730,7 @@ func (c *Controller) startCamera(cmd int, actionName string) error {
 		return fmt.Errorf("开始%s失败: %w", actionName, err)
 	}
 
-	log.Printf("✓ 开始%s（通道%d）", actionName, c.channel)
+	c.logger().Debug("开始"+actionName, "command", cmd)
 	return nil
 }
 
@@ -554,7 +740,7 @@ func (c *Controller) stopCamera(cmd int, actionName string) error {
 		return fmt.Errorf("停止%s失败: %w", actionName, err)
 	}
 
-	log.Printf("✓ 停止%s（通道%d）", actionName, c.channel)
+	c.logger().Debug("停止"+actionName, "command", cmd)
 	return nil
 }
 
@@ -571,29 +757,29 @@ func (c *Controller) switchDevice(cmd int, turnOn bool, deviceName string) error
 		return fmt.Errorf("%s%s失败: %w", actionName, deviceName, err)
 	}
 
-	log.Printf("✓ %s%s（通道%d）", actionName, deviceName, c.channel)
+	c.logger().Debug(actionName+deviceName, "command", cmd)
 	return nil
 }
 
+// logger 获取日志记录器，日志附带登录ID和通道号
+func (c *Controller) logger() *slog.Logger {
+	return c.login.Logger().With(slog.Int("channel", c.channel))
+}
+
 // controlWithSpeed 带速度的云台控制（底层调用）
 func (c *Controller) controlWithSpeed(cmd, stop, speed int) error {
-	if c.userID < 0 {
-		return fmt.Errorf("无效的登录ID：%d", c.userID)
+	userID := c.login.ID()
+	if userID < 0 {
+		return fmt.Errorf("无效的登录ID：%d", userID)
 	}
 
-	ret := C.NET_DVR_PTZControlWithSpeed_Other(
-		C.LONG(c.userID),
-		C.LONG(c.channel),
-		C.DWORD(cmd),
-		C.DWORD(stop),
-		C.DWORD(speed),
-	)
-
-	if ret != C.TRUE {
-		return core.NewHKError(fmt.Sprintf("PTZ控制[通道:%d 命令:%d]", c.channel, cmd))
+	began := time.Now()
+	var err error
+	if !c.backend.PTZControlWithSpeedOther(userID, c.channel, cmd, stop, speed) {
+		err = core.NewHKErrorFrom(c.backend, fmt.Sprintf("PTZ控制[通道:%d 命令:%d]", c.channel, cmd))
 	}
-
-	return nil
+	core.ObserveCall(core.OpPTZControl, c.login.Device(), began, err)
+	return err
 }
 
 // validateSpeed 验证速度范围
@@ -0,0 +1,125 @@
+package ptz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// newTestController 创建基于模拟后端的控制器
+func newTestController(t *testing.T) (*Controller, *sdk.Fake) {
+	t.Helper()
+	fake, userID := sdk.NewLoggedInFake()
+	return NewControllerWithBackend(fake, userID, 1), fake
+}
+
+// TestControllerMove 定时移动先发送开始命令，再发送停止命令
+func TestControllerMove(t *testing.T) {
+	ctrl, fake := newTestController(t)
+
+	if err := ctrl.Right(5, time.Millisecond); err != nil {
+		t.Fatalf("右转失败: %v", err)
+	}
+
+	calls := fake.CallsTo("PTZControlWithSpeedOther")
+	if len(calls) != 2 {
+		t.Fatalf("应调用2次PTZ控制，实际: %v", calls)
+	}
+	if calls[0].Args[2] != PAN_RIGHT || calls[0].Args[3] != PTZ_START || calls[0].Args[4] != 5 {
+		t.Errorf("开始命令参数错误: %v", calls[0])
+	}
+	if calls[1].Args[3] != PTZ_STOP {
+		t.Errorf("停止命令参数错误: %v", calls[1])
+	}
+}
+
+// TestControllerError SDK失败时返回带错误码的 HKError
+func TestControllerError(t *testing.T) {
+	ctrl, fake := newTestController(t)
+	fake.FailWith("PTZControlWithSpeedOther", sdk.NET_DVR_NOSUPPORT)
+
+	err := ctrl.StartLeft(3)
+	var hkErr *core.HKError
+	if !errors.As(err, &hkErr) {
+		t.Fatalf("应返回 HKError，实际: %v", err)
+	}
+	if hkErr.Code != sdk.NET_DVR_NOSUPPORT {
+		t.Errorf("错误码应为 %d，实际: %d", sdk.NET_DVR_NOSUPPORT, hkErr.Code)
+	}
+}
+
+// TestControllerValidateSpeed 速度越界时不调用SDK
+func TestControllerValidateSpeed(t *testing.T) {
+	ctrl, fake := newTestController(t)
+
+	if err := ctrl.Up(MaxSpeed+1, time.Millisecond); err == nil {
+		t.Fatal("速度越界应返回错误")
+	}
+	if calls := fake.CallsTo("PTZControlWithSpeedOther"); len(calls) != 0 {
+		t.Errorf("速度越界时不应调用SDK: %v", calls)
+	}
+}
+
+// TestControllerMoveContextCancel 上下文取消时立即发送停止命令
+func TestControllerMoveContextCancel(t *testing.T) {
+	ctrl, fake := newTestController(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	err := ctrl.LeftContext(ctx, 4, time.Minute)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("停止成功时应直接返回 context.DeadlineExceeded，实际: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 5*time.Second {
+		t.Fatalf("上下文超时后未及时返回，耗时: %v", elapsed)
+	}
+
+	calls := fake.CallsTo("PTZControlWithSpeedOther")
+	if len(calls) != 2 || calls[1].Args[3] != PTZ_STOP {
+		t.Errorf("取消后应发送停止命令，实际: %v", calls)
+	}
+}
+
+// TestControllerMoveContextStopFailure 取消后停止命令也失败时返回同时包装两者的错误
+func TestControllerMoveContextStopFailure(t *testing.T) {
+	ctrl, fake := newTestController(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// 开始命令之后再注入停止命令的失败
+		for len(fake.CallsTo("PTZControlWithSpeedOther")) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		fake.FailOnce("PTZControlWithSpeedOther", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+		cancel()
+	}()
+
+	err := ctrl.LeftContext(ctx, 4, time.Minute)
+	var hkErr *core.HKError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &hkErr) || hkErr.Code != sdk.NET_DVR_NETWORK_RECV_TIMEOUT {
+		t.Fatalf("应同时包含 context.Canceled 和停止命令的错误，实际: %v", err)
+	}
+}
+
+// TestControllerStopAfterStartFailure 开始命令失败时仍发送停止命令
+func TestControllerStopAfterStartFailure(t *testing.T) {
+	ctrl, fake := newTestController(t)
+	fake.FailOnce("PTZControlWithSpeedOther", sdk.NET_DVR_NETWORK_RECV_TIMEOUT)
+
+	err := ctrl.ZoomInContext(context.Background(), time.Minute)
+	var hkErr *core.HKError
+	if !errors.As(err, &hkErr) || hkErr.Code != sdk.NET_DVR_NETWORK_RECV_TIMEOUT {
+		t.Fatalf("应返回开始命令的错误，实际: %v", err)
+	}
+
+	calls := fake.CallsTo("PTZControlWithSpeedOther")
+	if len(calls) != 2 || calls[1].Args[2] != ZOOM_IN || calls[1].Args[3] != PTZ_STOP {
+		t.Errorf("开始失败后应发送停止命令，实际: %v", calls)
+	}
+}
@@ -1,25 +1,12 @@
 package ptz
 
-/*
-#cgo CFLAGS: -I../../include
-#cgo CFLAGS: -I..
-
-// Linux 平台的链接配置（需要在系统 LD_LIBRARY_PATH 中配置海康 SDK 库路径）
-#cgo linux LDFLAGS: -lhcnetsdk -lhpr -lHCCore
-
-// Windows 平台的链接配置（需要在系统 PATH 中配置海康 SDK 库路径）
-#cgo windows LDFLAGS: -lHCNetSDK -lHCCore
-
-#include <stdio.h>
-#include <stdlib.h>
-#include "../hiksdk_wrapper.h"
-*/
-import "C"
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
 )
 
 // 巡航命令常量（来自官方文档表 5.12）
@@ -52,11 +39,12 @@ const (
 // CruiseManager 巡航控制器
 // 封装了云台巡航的所有操作，提供简化的API
 type CruiseManager struct {
-	userID  int // 登录句柄（NET_DVR_Login_V30 的返回值）
-	channel int // 通道号
+	backend sdk.Backend       // SDK后端
+	login   *core.LoginHandle // 登录句柄（NET_DVR_Login_V30 的返回值）
+	channel int               // 通道号
 }
 
-// NewCruiseManager 创建巡航控制器
+// NewCruiseManager 创建巡航控制器（使用默认SDK后端）
 // 参数：
 //   - userID: 设备登录ID (dev.GetLoginID())
 //   - channel: 通道号
@@ -64,8 +52,34 @@ type CruiseManager struct {
 // 返回：
 //   - *CruiseManager: 巡航控制器实例
 func NewCruiseManager(userID int, channel int) *CruiseManager {
+	return NewCruiseManagerWithBackend(sdk.Default(), userID, channel)
+}
+
+// NewCruiseManagerWithBackend 使用指定SDK后端创建巡航控制器
+// 参数：
+//   - backend: SDK后端
+//   - userID: 设备登录ID
+//   - channel: 通道号
+//
+// 返回：
+//   - *CruiseManager: 巡航控制器实例
+func NewCruiseManagerWithBackend(backend sdk.Backend, userID int, channel int) *CruiseManager {
+	return NewCruiseManagerWithHandle(backend, core.NewLoginHandle(userID), channel)
+}
+
+// NewCruiseManagerWithHandle 使用共享登录句柄创建巡航控制器
+// 重新登录后更新句柄即可，无需重新创建控制器
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+//   - channel: 通道号
+//
+// 返回：
+//   - *CruiseManager: 巡航控制器实例
+func NewCruiseManagerWithHandle(backend sdk.Backend, login *core.LoginHandle, channel int) *CruiseManager {
 	return &CruiseManager{
-		userID:  userID,
+		backend: backend,
+		login:   login,
 		channel: channel,
 	}
 }
@@ -92,7 +106,7 @@ func (c *CruiseManager) AddPresetToCruise(routeIndex, pointIndex, presetID int)
 		return fmt.Errorf("添加预置点%d到巡航路径%d点%d失败: %w", presetID, routeIndex, pointIndex, err)
 	}
 
-	log.Printf("✓ 添加预置点%d到巡航路径%d点%d成功", presetID, routeIndex, pointIndex)
+	c.logger().Debug("添加预置点到巡航路径", "route", routeIndex, "point", pointIndex, "preset", presetID)
 	return nil
 }
 
@@ -118,7 +132,7 @@ func (c *CruiseManager) RemovePresetFromCruise(routeIndex, pointIndex, presetID
 		return fmt.Errorf("从巡航路径%d点%d删除预置点%d失败: %w", routeIndex, pointIndex, presetID, err)
 	}
 
-	log.Printf("✓ 从巡航路径%d点%d删除预置点%d成功", routeIndex, pointIndex, presetID)
+	c.logger().Debug("从巡航路径删除预置点", "route", routeIndex, "point", pointIndex, "preset", presetID)
 	return nil
 }
 
@@ -144,7 +158,7 @@ func (c *CruiseManager) SetCruiseSpeed(routeIndex, pointIndex, speed int) error
 		return fmt.Errorf("设置巡航路径%d点%d速度为%d失败: %w", routeIndex, pointIndex, speed, err)
 	}
 
-	log.Printf("✓ 设置巡航路径%d点%d速度为%d成功", routeIndex, pointIndex, speed)
+	c.logger().Debug("设置巡航点速度", "route", routeIndex, "point", pointIndex, "speed", speed)
 	return nil
 }
 
@@ -170,7 +184,7 @@ func (c *CruiseManager) SetCruiseDwellTime(routeIndex, pointIndex, dwellTime int
 		return fmt.Errorf("设置巡航路径%d点%d停顿时间为%d秒失败: %w", routeIndex, pointIndex, dwellTime, err)
 	}
 
-	log.Printf("✓ 设置巡航路径%d点%d停顿时间为%d秒成功", routeIndex, pointIndex, dwellTime)
+	c.logger().Debug("设置巡航点停顿时间", "route", routeIndex, "point", pointIndex, "dwell_seconds", dwellTime)
 	return nil
 }
 
@@ -192,7 +206,7 @@ func (c *CruiseManager) StartCruise(routeIndex int) error {
 		return fmt.Errorf("开始巡航路径%d失败: %w", routeIndex, err)
 	}
 
-	log.Printf("✓ 开始巡航路径%d", routeIndex)
+	c.logger().Debug("开始巡航", "route", routeIndex)
 	return nil
 }
 
@@ -213,7 +227,7 @@ func (c *CruiseManager) StopCruise(routeIndex int) error {
 		return fmt.Errorf("停止巡航路径%d失败: %w", routeIndex, err)
 	}
 
-	log.Printf("✓ 停止巡航路径%d", routeIndex)
+	c.logger().Debug("停止巡航", "route", routeIndex)
 	return nil
 }
 
@@ -234,7 +248,7 @@ func (c *CruiseManager) DeleteCruiseRoute(routeIndex int) error {
 		return fmt.Errorf("删除巡航路径%d失败: %w", routeIndex, err)
 	}
 
-	log.Printf("✓ 删除巡航路径%d成功", routeIndex)
+	c.logger().Debug("删除巡航路径", "route", routeIndex)
 	return nil
 }
 
@@ -249,29 +263,28 @@ func (c *CruiseManager) validateRoutePoint(routeIndex, pointIndex int) error {
 	return nil
 }
 
+// logger 获取日志记录器，日志附带登录ID和通道号
+func (c *CruiseManager) logger() *slog.Logger {
+	return c.login.Logger().With(slog.Int("channel", c.channel))
+}
+
 // control 内部通用控制函数
 // 直接调用 NET_DVR_PTZCruise_Other（推荐，不需要预览）
 func (c *CruiseManager) control(cmd, route, point, input int) error {
-	if c.userID < 0 {
-		return fmt.Errorf("无效的登录ID：%d", c.userID)
+	userID := c.login.ID()
+	if userID < 0 {
+		return fmt.Errorf("无效的登录ID：%d", userID)
 	}
 
-	// 调用 C 接口
-	ret := C.NET_DVR_PTZCruise_Other(
-		C.LONG(c.userID),
-		C.LONG(c.channel),
-		C.DWORD(cmd),
-		C.BYTE(route),
-		C.BYTE(point),
-		C.WORD(input),
-	)
-
-	if ret != C.TRUE {
-		return core.NewHKError(fmt.Sprintf("巡航操作[通道:%d 命令:%d 路径:%d 点:%d]",
+	// 调用 SDK 接口
+	began := time.Now()
+	var err error
+	if !c.backend.PTZCruiseOther(userID, c.channel, cmd, route, point, input) {
+		err = core.NewHKErrorFrom(c.backend, fmt.Sprintf("巡航操作[通道:%d 命令:%d 路径:%d 点:%d]",
 			c.channel, cmd, route, point))
 	}
-
-	return nil
+	core.ObserveCall(core.OpCruise, c.login.Device(), began, err)
+	return err
 }
 
 // GetCommandName 获取巡航命令的名称（用于调试）
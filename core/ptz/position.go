@@ -0,0 +1,249 @@
+package ptz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// 参数配置命令（来自官方SDK）
+const (
+	// NET_DVR_SET_PTZPOS 云台设置PTZ位置
+	NET_DVR_SET_PTZPOS = 292
+	// NET_DVR_GET_PTZPOS 云台获取PTZ位置
+	NET_DVR_GET_PTZPOS = 293
+)
+
+// PTZPOS 定位操作类型（NET_DVR_PTZPOS.wAction）
+const (
+	// PTZPOS_ACTION_PTZ 同时定位水平、垂直和变倍
+	PTZPOS_ACTION_PTZ = 1
+)
+
+// 绝对位置范围
+const (
+	MinPan  = 0.0   // 最小水平角度
+	MaxPan  = 360.0 // 最大水平角度（不含）
+	MinTilt = 0.0   // 最小垂直角度
+	MaxTilt = 360.0 // 最大垂直角度（不含）
+	MinZoom = 1.0   // 最小变倍倍数
+	MaxZoom = 999.9 // 最大变倍倍数（BCD编码上限）
+)
+
+// PointFrameScale 3D定位坐标系大小（SDK以255x255描述整个画面）
+const PointFrameScale = 255
+
+// ptzPosSize NET_DVR_PTZPOS 结构体大小
+const ptzPosSize = 8
+
+// Position 云台绝对位置
+type Position struct {
+	Pan  float64 // 水平角度（度），0-359.9
+	Tilt float64 // 垂直角度（度），范围与设备有关，通常0-90，部分设备以 360-x 表示水平线以上
+	Zoom float64 // 变倍倍数，如 1.0 表示1倍
+}
+
+// String 返回便于阅读的位置描述
+func (p Position) String() string {
+	return fmt.Sprintf("P:%.1f° T:%.1f° Z:%.1fx", p.Pan, p.Tilt, p.Zoom)
+}
+
+// GetPosition 获取云台当前绝对位置
+// 返回值：
+//   - Position: 当前位置
+//   - error: 错误信息，成功时为nil
+func (c *Controller) GetPosition() (Position, error) {
+	userID := c.login.ID()
+	if userID < 0 {
+		return Position{}, fmt.Errorf("无效的登录ID：%d", userID)
+	}
+
+	buf := make([]byte, ptzPosSize)
+	began := time.Now()
+	if _, ok := c.backend.GetDVRConfig(userID, NET_DVR_GET_PTZPOS, c.channel, buf); !ok {
+		err := core.NewHKErrorFrom(c.backend, fmt.Sprintf("获取PTZ位置[通道:%d]", c.channel))
+		core.ObserveCall(core.OpPTZPosition, c.login.Device(), began, err)
+		return Position{}, err
+	}
+	core.ObserveCall(core.OpPTZPosition, c.login.Device(), began, nil)
+
+	le := binary.LittleEndian
+	pan, err := fromBCD(le.Uint16(buf[2:]))
+	if err != nil {
+		return Position{}, fmt.Errorf("解析水平参数失败: %w", err)
+	}
+	tilt, err := fromBCD(le.Uint16(buf[4:]))
+	if err != nil {
+		return Position{}, fmt.Errorf("解析垂直参数失败: %w", err)
+	}
+	zoom, err := fromBCD(le.Uint16(buf[6:]))
+	if err != nil {
+		return Position{}, fmt.Errorf("解析变倍参数失败: %w", err)
+	}
+
+	return Position{Pan: pan, Tilt: tilt, Zoom: zoom}, nil
+}
+
+// SetAbsolutePosition 将云台转到指定绝对位置
+// 角度精度为0.1度，变倍精度为0.1倍
+// 参数：
+//   - pan: 水平角度（0-359.9）
+//   - tilt: 垂直角度（0-359.9，实际可用范围与设备有关）
+//   - zoom: 变倍倍数（1.0-999.9）
+//
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (c *Controller) SetAbsolutePosition(pan, tilt, zoom float64) error {
+	// 先按编码精度取整再校验，避免 359.96 之类的值通过校验后被编码为 360.0
+	pan, tilt, zoom = roundTenth(pan), roundTenth(tilt), roundTenth(zoom)
+	if err := c.validatePosition(pan, tilt, zoom); err != nil {
+		return err
+	}
+
+	userID := c.login.ID()
+	if userID < 0 {
+		return fmt.Errorf("无效的登录ID：%d", userID)
+	}
+
+	le := binary.LittleEndian
+	buf := make([]byte, ptzPosSize)
+	le.PutUint16(buf[0:], PTZPOS_ACTION_PTZ)
+	le.PutUint16(buf[2:], toBCD(pan))
+	le.PutUint16(buf[4:], toBCD(tilt))
+	le.PutUint16(buf[6:], toBCD(zoom))
+
+	began := time.Now()
+	if !c.backend.SetDVRConfig(userID, NET_DVR_SET_PTZPOS, c.channel, buf) {
+		err := core.NewHKErrorFrom(c.backend, fmt.Sprintf("设置PTZ位置[通道:%d]", c.channel))
+		core.ObserveCall(core.OpPTZPosition, c.login.Device(), began, err)
+		return err
+	}
+	core.ObserveCall(core.OpPTZPosition, c.login.Device(), began, nil)
+
+	c.logger().Debug("云台定位", "position", Position{Pan: pan, Tilt: tilt, Zoom: zoom})
+	return nil
+}
+
+// validatePosition 验证绝对位置范围
+func (c *Controller) validatePosition(pan, tilt, zoom float64) error {
+	if math.IsNaN(pan) || pan < MinPan || pan >= MaxPan {
+		return fmt.Errorf("水平角度超出范围：%.1f（有效范围：%.0f-%.1f）", pan, MinPan, MaxPan-0.1)
+	}
+	if math.IsNaN(tilt) || tilt < MinTilt || tilt >= MaxTilt {
+		return fmt.Errorf("垂直角度超出范围：%.1f（有效范围：%.0f-%.1f）", tilt, MinTilt, MaxTilt-0.1)
+	}
+	if math.IsNaN(zoom) || zoom < MinZoom || zoom > MaxZoom {
+		return fmt.Errorf("变倍倍数超出范围：%.1f（有效范围：%.1f-%.1f）", zoom, MinZoom, MaxZoom)
+	}
+	return nil
+}
+
+// roundTenth 将数值四舍五入到0.1（BCD编码精度）
+func roundTenth(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// toBCD 将数值按0.1精度编码为BCD（如 359.9 -> 0x3599）
+// 调用前需保证取值在 0-999.9 之间
+func toBCD(v float64) uint16 {
+	n := int(math.Round(v * 10))
+	if n > 9999 {
+		n = 9999
+	}
+	var bcd uint16
+	for shift := 0; shift < 16; shift += 4 {
+		bcd |= uint16(n%10) << shift
+		n /= 10
+	}
+	return bcd
+}
+
+// fromBCD 将BCD编码解码为数值（如 0x3599 -> 359.9）
+func fromBCD(bcd uint16) (float64, error) {
+	n := 0
+	for shift := 12; shift >= 0; shift -= 4 {
+		digit := int(bcd>>shift) & 0xF
+		if digit > 9 {
+			return 0, fmt.Errorf("无效的BCD编码：0x%04X", bcd)
+		}
+		n = n*10 + digit
+	}
+	return float64(n) / 10, nil
+}
+
+// ==================== 3D定位（点击居中/框选放大）====================
+
+// CenterOn 将画面中的指定点移动到画面中心（不改变变倍）
+// 参数：
+//   - x: 水平坐标，0为画面最左侧，1为最右侧
+//   - y: 垂直坐标，0为画面最上方，1为最下方
+//
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (c *Controller) CenterOn(x, y float64) error {
+	if err := c.validatePoint(x, y); err != nil {
+		return err
+	}
+
+	px, py := toFrame(x), toFrame(y)
+	return c.selZoomIn(&sdk.PointFrame{XTop: px, YTop: py, XBottom: px, YBottom: py}, "3D定位居中")
+}
+
+// ZoomToRect 框选放大（或缩小）画面中的区域
+// 从左上向右下框选时放大到该区域，从右下向左上框选时缩小
+// 参数：
+//   - x1, y1: 框选起点（0-1）
+//   - x2, y2: 框选终点（0-1）
+//
+// 返回值：
+//   - error: 错误信息，成功时为nil
+func (c *Controller) ZoomToRect(x1, y1, x2, y2 float64) error {
+	if err := c.validatePoint(x1, y1); err != nil {
+		return err
+	}
+	if err := c.validatePoint(x2, y2); err != nil {
+		return err
+	}
+
+	frame := &sdk.PointFrame{XTop: toFrame(x1), YTop: toFrame(y1), XBottom: toFrame(x2), YBottom: toFrame(y2)}
+	return c.selZoomIn(frame, "3D定位框选")
+}
+
+// selZoomIn 区域选择放大缩小（底层调用）
+func (c *Controller) selZoomIn(frame *sdk.PointFrame, actionName string) error {
+	userID := c.login.ID()
+	if userID < 0 {
+		return fmt.Errorf("无效的登录ID：%d", userID)
+	}
+
+	began := time.Now()
+	if !c.backend.PTZSelZoomInEx(userID, c.channel, frame) {
+		err := core.NewHKErrorFrom(c.backend, fmt.Sprintf("%s[通道:%d]", actionName, c.channel))
+		core.ObserveCall(core.OpPTZPosition, c.login.Device(), began, err)
+		return err
+	}
+	core.ObserveCall(core.OpPTZPosition, c.login.Device(), began, nil)
+
+	c.logger().Debug(actionName, "x1", frame.XTop, "y1", frame.YTop, "x2", frame.XBottom, "y2", frame.YBottom)
+	return nil
+}
+
+// validatePoint 验证归一化画面坐标范围
+func (c *Controller) validatePoint(x, y float64) error {
+	if math.IsNaN(x) || x < 0 || x > 1 {
+		return fmt.Errorf("水平坐标超出范围：%.3f（有效范围：0-1）", x)
+	}
+	if math.IsNaN(y) || y < 0 || y > 1 {
+		return fmt.Errorf("垂直坐标超出范围：%.3f（有效范围：0-1）", y)
+	}
+	return nil
+}
+
+// toFrame 将归一化坐标转换为SDK画面坐标
+func toFrame(v float64) int {
+	return int(math.Round(v * PointFrameScale))
+}
@@ -0,0 +1,128 @@
+package ptz
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// TestBCD BCD编码与解码
+func TestBCD(t *testing.T) {
+	cases := []struct {
+		value float64
+		bcd   uint16
+	}{
+		{0, 0x0000},
+		{1, 0x0010},
+		{45.5, 0x0455},
+		{359.9, 0x3599},
+		{999.9, 0x9999},
+	}
+	for _, c := range cases {
+		if got := toBCD(c.value); got != c.bcd {
+			t.Errorf("toBCD(%.1f) = 0x%04X，期望 0x%04X", c.value, got, c.bcd)
+		}
+		if got, err := fromBCD(c.bcd); err != nil || got != c.value {
+			t.Errorf("fromBCD(0x%04X) = %.1f, %v，期望 %.1f", c.bcd, got, err, c.value)
+		}
+	}
+	if _, err := fromBCD(0x12A4); err == nil {
+		t.Error("非法BCD编码应返回错误")
+	}
+}
+
+// TestControllerGetPosition 读取位置时解码BCD
+func TestControllerGetPosition(t *testing.T) {
+	ctrl, fake := newTestController(t)
+	fake.SetConfig(NET_DVR_GET_PTZPOS, 1, []byte{0, 0, 0x99, 0x35, 0x50, 0x04, 0x40, 0x00})
+
+	pos, err := ctrl.GetPosition()
+	if err != nil {
+		t.Fatalf("获取位置失败: %v", err)
+	}
+	if pos != (Position{Pan: 359.9, Tilt: 45, Zoom: 4}) {
+		t.Errorf("位置解码错误: %s", pos)
+	}
+}
+
+// TestControllerSetAbsolutePosition 设置位置时编码BCD并校验范围
+func TestControllerSetAbsolutePosition(t *testing.T) {
+	ctrl, fake := newTestController(t)
+
+	if err := ctrl.SetAbsolutePosition(123.4, 10, 2.5); err != nil {
+		t.Fatalf("设置位置失败: %v", err)
+	}
+	calls := fake.CallsTo("SetDVRConfig")
+	if len(calls) != 1 || calls[0].Args[1] != NET_DVR_SET_PTZPOS || calls[0].Args[2] != 1 {
+		t.Fatalf("SetDVRConfig 调用错误: %v", calls)
+	}
+	want := []byte{PTZPOS_ACTION_PTZ, 0, 0x34, 0x12, 0x00, 0x01, 0x25, 0x00}
+	if got := calls[0].Args[3].([]byte); !bytes.Equal(got, want) {
+		t.Errorf("PTZPOS 编码错误: % X，期望 % X", got, want)
+	}
+
+	fake.ResetCalls()
+	for _, p := range [][3]float64{{360, 0, 1}, {-1, 0, 1}, {0, 360, 1}, {0, 0, 0.5}, {0, 0, 1000},
+		{359.96, 0, 1}, {0, 359.96, 1}, {0, 0, 999.96}} {
+		if err := ctrl.SetAbsolutePosition(p[0], p[1], p[2]); err == nil {
+			t.Errorf("位置 %v 应返回错误", p)
+		}
+	}
+	if calls := fake.CallsTo("SetDVRConfig"); len(calls) != 0 {
+		t.Errorf("参数越界时不应调用SDK: %v", calls)
+	}
+}
+
+// TestControllerGetPositionUnsupported 设备不支持时返回SDK错误
+func TestControllerGetPositionUnsupported(t *testing.T) {
+	ctrl, _ := newTestController(t)
+
+	_, err := ctrl.GetPosition()
+	var hkErr *core.HKError
+	if !errors.As(err, &hkErr) || hkErr.Code != sdk.NET_DVR_NOSUPPORT {
+		t.Errorf("应返回错误码 %d，实际: %v", sdk.NET_DVR_NOSUPPORT, err)
+	}
+}
+
+// TestControllerCenterOn 点击居中时起点与终点相同
+func TestControllerCenterOn(t *testing.T) {
+	ctrl, fake := newTestController(t)
+
+	if err := ctrl.CenterOn(0.5, 0.2); err != nil {
+		t.Fatalf("3D定位居中失败: %v", err)
+	}
+	calls := fake.CallsTo("PTZSelZoomInEx")
+	want := sdk.PointFrame{XTop: 128, YTop: 51, XBottom: 128, YBottom: 51}
+	if len(calls) != 1 || calls[0].Args[1] != 1 || calls[0].Args[2] != want {
+		t.Errorf("3D定位参数错误: %v，期望 %+v", calls, want)
+	}
+}
+
+// TestControllerZoomToRect 框选坐标按255x255换算，越界时不调用SDK
+func TestControllerZoomToRect(t *testing.T) {
+	ctrl, fake := newTestController(t)
+
+	if err := ctrl.ZoomToRect(0, 0.25, 1, 0.75); err != nil {
+		t.Fatalf("3D定位框选失败: %v", err)
+	}
+	want := sdk.PointFrame{XTop: 0, YTop: 64, XBottom: 255, YBottom: 191}
+	if calls := fake.CallsTo("PTZSelZoomInEx"); len(calls) != 1 || calls[0].Args[2] != want {
+		t.Errorf("3D定位参数错误: %v，期望 %+v", calls, want)
+	}
+
+	fake.ResetCalls()
+	for _, r := range [][4]float64{{-0.1, 0, 1, 1}, {0, 0, 1.1, 1}, {0, 0, 1, 2}} {
+		if err := ctrl.ZoomToRect(r[0], r[1], r[2], r[3]); err == nil {
+			t.Errorf("区域 %v 应返回错误", r)
+		}
+	}
+	if err := ctrl.CenterOn(0.5, -0.5); err == nil {
+		t.Error("坐标越界应返回错误")
+	}
+	if calls := fake.CallsTo("PTZSelZoomInEx"); len(calls) != 0 {
+		t.Errorf("参数越界时不应调用SDK: %v", calls)
+	}
+}
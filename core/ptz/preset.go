@@ -1,16 +1,12 @@
 package ptz
 
-/*
-#include <stdio.h>
-#include <stdlib.h>
-#include "../hiksdk_wrapper.h"
-*/
-import "C"
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
 )
 
 // 预置点命令常量（来自官方文档表 5.11）
@@ -32,11 +28,12 @@ const (
 // PresetManager 预置点控制器
 // 封装了云台预置点的所有操作，提供简化的API
 type PresetManager struct {
-	userID  int // 登录句柄（NET_DVR_Login_V40 的返回值）
-	channel int // 通道号
+	backend sdk.Backend       // SDK后端
+	login   *core.LoginHandle // 登录句柄（NET_DVR_Login_V40 的返回值）
+	channel int               // 通道号
 }
 
-// NewPresetManager 创建预置点控制器
+// NewPresetManager 创建预置点控制器（使用默认SDK后端）
 // 参数：
 //   - userID: 设备登录ID (dev.GetLoginID())
 //   - channel: 通道号
@@ -44,8 +41,34 @@ type PresetManager struct {
 // 返回：
 //   - *PresetManager: 预置点控制器实例
 func NewPresetManager(userID int, channel int) *PresetManager {
+	return NewPresetManagerWithBackend(sdk.Default(), userID, channel)
+}
+
+// NewPresetManagerWithBackend 使用指定SDK后端创建预置点控制器
+// 参数：
+//   - backend: SDK后端
+//   - userID: 设备登录ID
+//   - channel: 通道号
+//
+// 返回：
+//   - *PresetManager: 预置点控制器实例
+func NewPresetManagerWithBackend(backend sdk.Backend, userID int, channel int) *PresetManager {
+	return NewPresetManagerWithHandle(backend, core.NewLoginHandle(userID), channel)
+}
+
+// NewPresetManagerWithHandle 使用共享登录句柄创建预置点控制器
+// 重新登录后更新句柄即可，无需重新创建控制器
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+//   - channel: 通道号
+//
+// 返回：
+//   - *PresetManager: 预置点控制器实例
+func NewPresetManagerWithHandle(backend sdk.Backend, login *core.LoginHandle, channel int) *PresetManager {
 	return &PresetManager{
-		userID:  userID,
+		backend: backend,
+		login:   login,
 		channel: channel,
 	}
 }
@@ -68,7 +91,7 @@ func (p *PresetManager) SetPreset(presetID int) error {
 		return fmt.Errorf("设置预置点%d失败: %w", presetID, err)
 	}
 
-	log.Printf("✓ 设置预置点%d成功（通道%d）", presetID, p.channel)
+	p.logger().Debug("设置预置点", "preset", presetID)
 	return nil
 }
 
@@ -90,7 +113,7 @@ func (p *PresetManager) GotoPreset(presetID int) error {
 		return fmt.Errorf("转到预置点%d失败: %w", presetID, err)
 	}
 
-	log.Printf("✓ 转到预置点%d（通道%d）", presetID, p.channel)
+	p.logger().Debug("转到预置点", "preset", presetID)
 	return nil
 }
 
@@ -112,7 +135,7 @@ func (p *PresetManager) DeletePreset(presetID int) error {
 		return fmt.Errorf("删除预置点%d失败: %w", presetID, err)
 	}
 
-	log.Printf("✓ 删除预置点%d成功（通道%d）", presetID, p.channel)
+	p.logger().Debug("删除预置点", "preset", presetID)
 	return nil
 }
 
@@ -124,27 +147,28 @@ func (p *PresetManager) validatePresetID(presetID int) error {
 	return nil
 }
 
+// logger 获取日志记录器，日志附带登录ID和通道号
+func (p *PresetManager) logger() *slog.Logger {
+	return p.login.Logger().With(slog.Int("channel", p.channel))
+}
+
 // control 内部通用控制函数
 // 直接调用 NET_DVR_PTZPreset_Other（推荐，不需要预览）
 func (p *PresetManager) control(cmd, presetID int) error {
-	if p.userID < 0 {
-		return fmt.Errorf("无效的登录ID：%d", p.userID)
+	userID := p.login.ID()
+	if userID < 0 {
+		return fmt.Errorf("无效的登录ID：%d", userID)
 	}
 
-	// 调用 C 接口
-	ret := C.NET_DVR_PTZPreset_Other(
-		C.LONG(p.userID),
-		C.LONG(p.channel),
-		C.DWORD(cmd),
-		C.DWORD(presetID),
-	)
-
-	if ret != C.TRUE {
-		return core.NewHKError(fmt.Sprintf("预置点操作[通道:%d 命令:%d 预置点:%d]",
+	// 调用 SDK 接口
+	began := time.Now()
+	var err error
+	if !p.backend.PTZPresetOther(userID, p.channel, cmd, presetID) {
+		err = core.NewHKErrorFrom(p.backend, fmt.Sprintf("预置点操作[通道:%d 命令:%d 预置点:%d]",
 			p.channel, cmd, presetID))
 	}
-
-	return nil
+	core.ObserveCall(core.OpPreset, p.login.Device(), began, err)
+	return err
 }
 
 // GetPresetCommandName 获取预置点命令的名称（用于调试）
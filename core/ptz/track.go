@@ -1,16 +1,12 @@
 package ptz
 
-/*
-#include <stdio.h>
-#include <stdlib.h>
-#include "../hiksdk_wrapper.h"
-*/
-import "C"
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
 )
 
 // 轨迹命令常量（来自官方文档表 5.13）
@@ -26,11 +22,12 @@ const (
 // TrackManager 轨迹控制器
 // 封装了云台轨迹（花样扫描路径）的所有操作
 type TrackManager struct {
-	userID  int // 登录句柄（NET_DVR_Login_V40 的返回值）
-	channel int // 通道号
+	backend sdk.Backend       // SDK后端
+	login   *core.LoginHandle // 登录句柄（NET_DVR_Login_V40 的返回值）
+	channel int               // 通道号
 }
 
-// NewTrackManager 创建轨迹控制器
+// NewTrackManager 创建轨迹控制器（使用默认SDK后端）
 // 参数：
 //   - userID: 设备登录ID (dev.GetLoginID())
 //   - channel: 通道号
@@ -38,8 +35,34 @@ type TrackManager struct {
 // 返回：
 //   - *TrackManager: 轨迹控制器实例
 func NewTrackManager(userID int, channel int) *TrackManager {
+	return NewTrackManagerWithBackend(sdk.Default(), userID, channel)
+}
+
+// NewTrackManagerWithBackend 使用指定SDK后端创建轨迹控制器
+// 参数：
+//   - backend: SDK后端
+//   - userID: 设备登录ID
+//   - channel: 通道号
+//
+// 返回：
+//   - *TrackManager: 轨迹控制器实例
+func NewTrackManagerWithBackend(backend sdk.Backend, userID int, channel int) *TrackManager {
+	return NewTrackManagerWithHandle(backend, core.NewLoginHandle(userID), channel)
+}
+
+// NewTrackManagerWithHandle 使用共享登录句柄创建轨迹控制器
+// 重新登录后更新句柄即可，无需重新创建控制器
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+//   - channel: 通道号
+//
+// 返回：
+//   - *TrackManager: 轨迹控制器实例
+func NewTrackManagerWithHandle(backend sdk.Backend, login *core.LoginHandle, channel int) *TrackManager {
 	return &TrackManager{
-		userID:  userID,
+		backend: backend,
+		login:   login,
 		channel: channel,
 	}
 }
@@ -55,7 +78,7 @@ func (t *TrackManager) StartRecordTrack() error {
 		return fmt.Errorf("开始记录轨迹失败: %w", err)
 	}
 
-	log.Printf("✓ 开始记录轨迹（通道%d）", t.channel)
+	t.logger().Debug("开始记录轨迹", "command", STA_MEM_CRUISE)
 	return nil
 }
 
@@ -70,7 +93,7 @@ func (t *TrackManager) StopRecordTrack() error {
 		return fmt.Errorf("停止记录轨迹失败: %w", err)
 	}
 
-	log.Printf("✓ 停止记录轨迹（通道%d）", t.channel)
+	t.logger().Debug("停止记录轨迹", "command", STO_MEM_CRUISE)
 	return nil
 }
 
@@ -85,29 +108,31 @@ func (t *TrackManager) RunTrack() error {
 		return fmt.Errorf("执行轨迹失败: %w", err)
 	}
 
-	log.Printf("✓ 开始执行轨迹（通道%d）", t.channel)
+	t.logger().Debug("开始执行轨迹", "command", RUN_CRUISE)
 	return nil
 }
 
+// logger 获取日志记录器，日志附带登录ID和通道号
+func (t *TrackManager) logger() *slog.Logger {
+	return t.login.Logger().With(slog.Int("channel", t.channel))
+}
+
 // control 内部通用控制函数
 // 直接调用 NET_DVR_PTZTrack_Other（推荐，不需要预览）
 func (t *TrackManager) control(cmd int) error {
-	if t.userID < 0 {
-		return fmt.Errorf("无效的登录ID：%d", t.userID)
+	userID := t.login.ID()
+	if userID < 0 {
+		return fmt.Errorf("无效的登录ID：%d", userID)
 	}
 
-	// 调用 C 接口
-	ret := C.NET_DVR_PTZTrack_Other(
-		C.LONG(t.userID),
-		C.LONG(t.channel),
-		C.DWORD(cmd),
-	)
-
-	if ret != C.TRUE {
-		return core.NewHKError(fmt.Sprintf("轨迹操作[通道:%d 命令:%d]", t.channel, cmd))
+	// 调用 SDK 接口
+	began := time.Now()
+	var err error
+	if !t.backend.PTZTrackOther(userID, t.channel, cmd) {
+		err = core.NewHKErrorFrom(t.backend, fmt.Sprintf("轨迹操作[通道:%d 命令:%d]", t.channel, cmd))
 	}
-
-	return nil
+	core.ObserveCall(core.OpTrack, t.login.Device(), began, err)
+	return err
 }
 
 // GetTrackCommandName 获取轨迹命令的名称（用于调试）
@@ -0,0 +1,302 @@
+// Package sdk 定义海康 C SDK 的 Go 接口抽象
+//
+// 上层模块（auth/alarm/ptz）只依赖 Backend 接口，不直接调用 C 函数：
+//   - 启用 CGO 时，Default() 返回基于 libhcnetsdk 的实现
+//   - 未启用 CGO 时，Default() 返回一个所有调用都失败的占位实现
+//   - 测试时可使用 NewFake() 创建纯 Go 的模拟实现，记录调用并模拟错误
+package sdk
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// Backend 海康SDK后端接口
+// 方法与 hiksdk_wrapper.h 中声明的 NET_DVR_* 函数一一对应，
+// 返回值语义与C接口保持一致：BOOL 映射为 bool，句柄映射为 int（<0 表示失败），
+// 失败原因通过 GetLastError 获取
+type Backend interface {
+	// Init 对应 NET_DVR_Init
+	Init() bool
+	// Cleanup 对应 NET_DVR_Cleanup
+	Cleanup() bool
+	// SetConnectTime 对应 NET_DVR_SetConnectTime
+	SetConnectTime(waitTime, tryTimes uint32) bool
+	// SetReconnect 对应 NET_DVR_SetReconnect
+	SetReconnect(interval uint32, enable bool) bool
+	// SetLogToFile 对应 NET_DVR_SetLogToFile
+	SetLogToFile(level int, logDir string, autoDelete bool) bool
+	// SetRecvTimeOut 对应 NET_DVR_SetRecvTimeOut（毫秒）
+	SetRecvTimeOut(timeout uint32) bool
+	// SetSDKInitCfg 对应 NET_DVR_SetSDKInitCfg，只支持路径类参数（NET_SDK_INIT_CFG_SDK_PATH 等），需在 Init 之前调用
+	SetSDKInitCfg(cfgType int, path string) bool
+
+	// LoginV40 对应 NET_DVR_Login_V40，成功时填充 deviceInfo（包含V40扩展字段）；
+	// 失败时只填充锁定相关字段（SupportLock、RetryLoginTime、SurplusLockTime）
+	LoginV40(loginInfo *LoginInfo, deviceInfo *DeviceInfo) int
+	// LoginV40Async 对应 NET_DVR_Login_V40 的异步登录（byUseAsynLogin = 1）
+	// 返回false表示登录请求未能提交；提交成功后登录结果通过 callback 返回（在SDK线程中调用）
+	LoginV40Async(loginInfo *LoginInfo, callback LoginResultCallback) bool
+	// LoginV30 对应 NET_DVR_Login_V30，成功时填充 deviceInfo
+	LoginV30(ip string, port int, username, password string, deviceInfo *DeviceInfo) int
+	// Logout 对应 NET_DVR_Logout
+	Logout(userID int) bool
+	// CheckUserStatus 检测会话是否有效
+	// 对应 NET_DVR_RemoteControl(lUserID, NET_DVR_CHECK_USER_STATUS, NULL, 0)
+	CheckUserStatus(userID int) bool
+	// GetDVRIPByResolveSvrEx 对应 NET_DVR_GetDVRIPByResolveSvr_EX
+	GetDVRIPByResolveSvrEx(serverIP string, serverPort uint16, dvrName, serialNumber string) (string, uint32, bool)
+
+	// GetDVRConfig 对应 NET_DVR_GetDVRConfig
+	// 配置结构体的原始字节写入 buf（长度即结构体大小），返回实际返回的长度
+	GetDVRConfig(userID, command, channel int, buf []byte) (int, bool)
+	// SetDVRConfig 对应 NET_DVR_SetDVRConfig，buf 为配置结构体的原始字节
+	SetDVRConfig(userID, command, channel int, buf []byte) bool
+
+	// SetDVRMessageCallBackV30 对应 NET_DVR_SetDVRMessageCallBack_V30
+	SetDVRMessageCallBackV30(callback MessageCallback) bool
+	// SetupAlarmChanV41 对应 NET_DVR_SetupAlarmChan_V41
+	SetupAlarmChanV41(userID int, param *AlarmSetupParam) int
+	// CloseAlarmChanV30 对应 NET_DVR_CloseAlarmChan_V30
+	CloseAlarmChanV30(alarmHandle int) bool
+	// StartListenV30 对应 NET_DVR_StartListen_V30，设备主动上传的报警通过 callback 回调
+	// localIP 为空表示监听所有本地地址
+	StartListenV30(localIP string, localPort uint16, callback MessageCallback) int
+	// StopListenV30 对应 NET_DVR_StopListen_V30
+	StopListenV30(listenHandle int) bool
+
+	// RealPlayV40 对应 NET_DVR_RealPlay_V40，不使用播放窗口，码流数据通过 callback 回调
+	RealPlayV40(userID int, info *PreviewInfo, callback RealDataCallback) int
+	// StopRealPlay 对应 NET_DVR_StopRealPlay，返回后不会再回调该预览句柄的数据
+	StopRealPlay(realHandle int) bool
+
+	// PTZControlWithSpeedOther 对应 NET_DVR_PTZControlWithSpeed_Other
+	PTZControlWithSpeedOther(userID, channel, command, stop, speed int) bool
+	// PTZPresetOther 对应 NET_DVR_PTZPreset_Other
+	PTZPresetOther(userID, channel, command, presetIndex int) bool
+	// PTZCruiseOther 对应 NET_DVR_PTZCruise_Other
+	PTZCruiseOther(userID, channel, command, route, point, input int) bool
+	// PTZTrackOther 对应 NET_DVR_PTZTrack_Other
+	PTZTrackOther(userID, channel, command int) bool
+	// PTZSelZoomInEx 对应 NET_DVR_PTZSelZoomIn_EX
+	PTZSelZoomInEx(userID, channel int, frame *PointFrame) bool
+
+	// CaptureJPEGPicture 对应 NET_DVR_CaptureJPEGPicture，图片保存到 fileName
+	CaptureJPEGPicture(userID, channel int, para *JPEGPara, fileName string) bool
+	// CaptureJPEGPictureNew 对应 NET_DVR_CaptureJPEGPicture_NEW
+	// 图片写入 buf，返回图片的实际大小；buf 不足时失败，错误码为 NET_DVR_NOENOUGH_BUF
+	CaptureJPEGPictureNew(userID, channel int, para *JPEGPara, buf []byte) (int, bool)
+
+	// FindFileV40 对应 NET_DVR_FindFile_V40，返回查找句柄
+	FindFileV40(userID int, cond *FileCond) int
+	// FindNextFileV40 对应 NET_DVR_FindNextFile_V40
+	// 返回 NET_DVR_FILE_SUCCESS（data 已填充）、NET_DVR_ISFINDING、NET_DVR_NOMOREFILE 等状态，失败返回-1
+	FindNextFileV40(findHandle int, data *FindData) int
+	// FindCloseV30 对应 NET_DVR_FindClose_V30
+	FindCloseV30(findHandle int) bool
+	// GetFileByTimeV40 对应 NET_DVR_GetFileByTime_V40，录像保存到 fileName，返回下载句柄
+	// 需要通过 PlayBackControlV40(NET_DVR_PLAYSTART) 开始下载
+	GetFileByTimeV40(userID int, fileName string, cond *PlayCond) int
+	// PlayBackControlV40 对应 NET_DVR_PlayBackControl_V40
+	// in 为输入参数的原始字节，输出参数写入 out，返回实际输出的长度
+	PlayBackControlV40(playHandle, command int, in, out []byte) (int, bool)
+	// GetDownloadPos 对应 NET_DVR_GetDownloadPos，返回0-100的下载进度，200表示网络异常，失败返回-1
+	GetDownloadPos(fileHandle int) int
+	// StopGetFile 对应 NET_DVR_StopGetFile
+	StopGetFile(fileHandle int) bool
+
+	// PlayBackByTimeV40 对应 NET_DVR_PlayBackByTime_V40（不使用播放窗口），返回回放句柄
+	// 需要通过 PlayBackControlV40(NET_DVR_PLAYSTART) 开始播放
+	PlayBackByTimeV40(userID int, cond *PlayCond) int
+	// SetPlayDataCallBackV40 对应 NET_DVR_SetPlayDataCallBack_V40，码流数据通过 callback 回调
+	SetPlayDataCallBackV40(playHandle int, callback RealDataCallback) bool
+	// GetPlayBackOsdTime 对应 NET_DVR_GetPlayBackOsdTime，返回回放画面的OSD时间（按本地时区）
+	GetPlayBackOsdTime(playHandle int) (time.Time, bool)
+	// StopPlayBack 对应 NET_DVR_StopPlayBack，返回后不会再回调该回放句柄的数据
+	StopPlayBack(playHandle int) bool
+
+	// GetDeviceAbility 对应 NET_DVR_GetDeviceAbility
+	// in 为输入XML，能力集XML写入 out，返回输出的长度；out 不足时失败，错误码为 NET_DVR_NOENOUGH_BUF
+	GetDeviceAbility(userID, abilityType int, in, out []byte) (int, bool)
+	// STDXMLConfig 对应 NET_DVR_STDXMLConfig（ISAPI协议透传）
+	// request 为请求信令（如 "GET /ISAPI/System/capabilities"），in 为请求报文；
+	// 响应报文写入 out，设备返回的 ResponseStatus 写入 status，返回两者的长度
+	STDXMLConfig(userID int, request string, in, out, status []byte) (int, int, bool)
+
+	// GetLastError 对应 NET_DVR_GetLastError
+	GetLastError() int
+	// GetErrorMsg 对应 NET_DVR_GetErrorMsg，返回最后一次错误的错误码和SDK提供的说明
+	GetErrorMsg() (int, string)
+}
+
+// LoginInfo 登录参数（对应 NET_DVR_USER_LOGIN_INFO）
+type LoginInfo struct {
+	DeviceAddress string // 设备地址（IP或域名）
+	Port          int    // 设备端口号
+	Username      string // 登录用户名
+	Password      string // 登录密码
+}
+
+// DeviceInfo 设备信息（对应 NET_DVR_DEVICEINFO_V30，以及 NET_DVR_DEVICEINFO_V40 的扩展字段）
+// V30 登录时扩展字段保持零值
+type DeviceInfo struct {
+	SerialNumber    string // 序列号
+	AlarmInPortNum  int    // 报警输入个数
+	AlarmOutPortNum int    // 报警输出个数
+	DiskNum         int    // 硬盘个数
+	DVRType         int    // 设备类型
+	ChanNum         int    // 模拟通道个数
+	StartChan       int    // 起始通道号
+	AudioChanNum    int    // 语音通道数
+	IPChanNum       int    // 最大数字通道个数（已合并高低8位）
+	ZeroChanNum     int    // 零通道编码个数
+	DevType         int    // 设备型号
+	StartDChan      int    // 起始数字通道号
+
+	SupportLock     bool // 是否支持用户锁定
+	RetryLoginTime  int  // 剩余可尝试登录的次数
+	PasswordLevel   int  // admin密码安全等级：0-无效，1-默认密码，2-有效密码，3-风险较高的密码
+	SurplusLockTime int  // 剩余锁定时间（秒）
+}
+
+// PointFrame 区域框（对应 NET_DVR_POINT_FRAME）
+// 坐标基于255x255的画面；起点在终点左上方时放大，反之缩小，起点与终点相同时居中
+type PointFrame struct {
+	XTop    int // 起始点x坐标
+	YTop    int // 起始点y坐标
+	XBottom int // 结束点x坐标
+	YBottom int // 结束点y坐标
+}
+
+// PreviewInfo 预览参数（对应 NET_DVR_PREVIEWINFO）
+type PreviewInfo struct {
+	Channel    int  // 通道号
+	StreamType int  // 码流类型：0-主码流，1-子码流，2-三码流
+	LinkMode   int  // 连接方式：0-TCP，1-UDP，2-多播，3-RTP，4-RTP/RTSP，5-RTSP/HTTP
+	Blocked    bool // 是否阻塞取流
+}
+
+// JPEGPara 抓图参数（对应 NET_DVR_JPEGPARA）
+type JPEGPara struct {
+	PicSize    int // 图片尺寸编号，0xff表示使用当前码流分辨率
+	PicQuality int // 图片质量：0-最好，1-较好，2-一般
+}
+
+// FileCond 录像文件查找条件（对应 NET_DVR_FILECOND_V40）
+// 设备时间没有时区信息，Start、Stop 按本地时区转换为年月日时分秒
+type FileCond struct {
+	Channel  int       // 通道号
+	FileType int       // 录像文件类型，0xff表示全部
+	Start    time.Time // 开始时间
+	Stop     time.Time // 结束时间
+}
+
+// FindData 录像文件信息（对应 NET_DVR_FINDDATA_V40）
+// 时间按本地时区解释
+type FindData struct {
+	FileName string    // 文件名
+	Start    time.Time // 文件的开始时间
+	Stop     time.Time // 文件的结束时间
+	FileSize int64     // 文件大小（字节）
+	FileType int       // 录像文件类型
+	Locked   bool      // 是否锁定
+}
+
+// PlayCond 按时间回放/下载条件（对应 NET_DVR_PLAYCOND、NET_DVR_VOD_PARA）
+// 时间按本地时区转换
+type PlayCond struct {
+	Channel    int       // 通道号
+	Start      time.Time // 开始时间
+	Stop       time.Time // 结束时间
+	StreamType int       // 码流类型：0-主码流，1-子码流，2-三码流
+}
+
+// TimeSize NET_DVR_TIME 的大小（6个DWORD）
+const TimeSize = 24
+
+// EncodeTime 将时间编码为 NET_DVR_TIME 的原始字节（按本地时区）
+// 用于 PlayBackControlV40(NET_DVR_PLAYSETTIME) 等以原始字节传参的接口
+func EncodeTime(t time.Time) []byte {
+	t = t.Local()
+	buf := make([]byte, TimeSize)
+	for i, v := range []int{t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second()} {
+		binary.LittleEndian.PutUint32(buf[i*4:], uint32(v))
+	}
+	return buf
+}
+
+// DecodeTime 解码 NET_DVR_TIME 的原始字节（按本地时区），长度不足时返回零值
+func DecodeTime(b []byte) time.Time {
+	if len(b) < TimeSize {
+		return time.Time{}
+	}
+	var v [6]int
+	for i := range v {
+		v[i] = int(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	return time.Date(v[0], time.Month(v[1]), v[2], v[3], v[4], v[5], 0, time.Local)
+}
+
+// LoginResultCallback 异步登录结果回调（对应 fLoginResultCallBack）
+// errorCode 为 NET_DVR_NOERROR 表示登录成功，此时 userID 和 deviceInfo 有效（只包含V30字段）
+type LoginResultCallback func(userID int, deviceInfo *DeviceInfo, errorCode int)
+
+// RealDataCallback 实时数据回调（预览和回放共用）
+// dataType 为 NET_DVR_SYSHEAD、NET_DVR_STREAMDATA 等；
+// data 为数据的拷贝，回调返回后仍可安全使用
+type RealDataCallback func(realHandle, dataType int, data []byte)
+
+// Alarmer 报警设备信息（对应 NET_DVR_ALARMER）
+// 无效字段保持零值，UserID 无效时为 -1
+type Alarmer struct {
+	UserID       int    // 登录ID
+	SerialNumber string // 序列号
+	DeviceName   string // 设备名字
+	DeviceIP     string // 设备IP
+	LinkPort     int    // 连接端口
+}
+
+// AlarmSetupParam 布防参数（对应 NET_DVR_SETUPALARM_PARAM）
+// 按位字段的含义见 hiksdk_wrapper.h
+type AlarmSetupParam struct {
+	Level              int   // 布防优先级：0-高，1-中，2-低
+	AlarmInfoType      int   // 报警信息类型：0-老报警信息，1-新报警信息
+	RetAlarmTypeV40    int   // 设备支持时返回V40报警信息：0-否，1-是
+	FaceAlarmDetection int   // 人脸侦测报警：0-原先结构，1-扩展结构
+	Support            uint8 // bySupport 按位标志
+	BrokenNetHTTP      uint8 // 断网续传类型（按位）
+	DeployType         int   // 布防类型：0-客户端布防，1-实时布防
+	Subscription       uint8 // 订阅（按位）
+	AlarmTypeURL       uint8 // 图片数据采用URL传输（按位）
+	CustomCtrl         uint8 // byCustomCtrl 按位标志
+}
+
+// MessageCallback 报警消息回调
+// info 为报警信息结构体的原始字节拷贝，回调返回后仍可安全使用；
+// COMM_ALARM_V40 的可变部分（pAlarmData 指向的 DWORD 数组）紧跟在结构体之后
+type MessageCallback func(command int, alarmer *Alarmer, info []byte)
+
+var (
+	// defaultMutex 保护默认后端的互斥锁
+	defaultMutex sync.RWMutex
+	// defaultBackend 当前默认后端
+	defaultBackend Backend = newDefaultBackend()
+)
+
+// Default 返回当前默认后端
+func Default() Backend {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultBackend
+}
+
+// SetDefault 替换默认后端并返回之前的后端
+// 通常仅在测试中使用，应在创建任何控制器之前调用
+func SetDefault(b Backend) Backend {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	prev := defaultBackend
+	defaultBackend = b
+	return prev
+}
@@ -0,0 +1,893 @@
+//go:build cgo
+
+package sdk
+
+/*
+#cgo CFLAGS: -I../../include
+#cgo CFLAGS: -I..
+
+// Linux 平台的链接配置（需要在系统 LD_LIBRARY_PATH 中配置海康 SDK 库路径）
+#cgo linux LDFLAGS: -lhcnetsdk -lhpr -lHCCore
+
+// Windows 平台的链接配置（需要在系统 PATH 中配置海康 SDK 库路径）
+#cgo windows LDFLAGS: -lHCNetSDK -lHCCore
+
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include "../hiksdk_wrapper.h"
+
+// 声明Go回调函数
+extern void hiksdkMessageCallback(LONG command, NET_DVR_ALARMER *alarm, char *info, DWORD len, void *user);
+extern void hiksdkListenCallback(LONG command, NET_DVR_ALARMER *alarm, char *info, DWORD len, void *user);
+
+// 设置异步登录参数，以整数键作为用户数据，Go回调据此找到对应的登录结果回调
+static void hiksdkSetAsyncLogin(NET_DVR_USER_LOGIN_INFO *info, uintptr_t key) {
+    info->byUseAsynLogin = 1;
+    info->fLoginResultCallBack = (fLoginResultCallBack)GoLoginResultCallback;
+    info->pUser = (void *)key;
+}
+
+// 启动报警监听，以整数键作为用户数据，Go回调据此找到对应的监听回调
+static LONG hiksdkStartListen(char *ip, WORD port, uintptr_t key) {
+    return NET_DVR_StartListen_V30(ip, port, (MSGCallBack)hiksdkListenCallback, (void *)key);
+}
+*/
+import "C"
+import (
+	"runtime/cgo"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/samsaralc/hiksdk/core/utils"
+)
+
+// cgoBackend 基于 libhcnetsdk 的后端实现
+type cgoBackend struct{}
+
+func newDefaultBackend() Backend {
+	return cgoBackend{}
+}
+
+var (
+	// messageMutex 保护报警回调的读写锁
+	messageMutex sync.RWMutex
+	// messageCallback 当前注册的报警回调
+	messageCallback MessageCallback
+
+	// listenMutex 保护监听回调表
+	listenMutex sync.RWMutex
+	// listenCallbacks 监听键 -> 监听回调
+	listenCallbacks = make(map[uintptr]MessageCallback)
+	// listenKeys 监听句柄 -> 监听键
+	listenKeys = make(map[int]uintptr)
+	// nextListenKey 下一个监听键
+	nextListenKey uintptr = 1
+
+	// realPlayMutex 保护预览回调表
+	realPlayMutex sync.Mutex
+	// realPlayHandles 预览句柄 -> 回调的 cgo.Handle（作为用户数据传给SDK）
+	realPlayHandles = make(map[int]cgo.Handle)
+
+	// loginMutex 保护异步登录回调表
+	loginMutex sync.Mutex
+	// loginCallbacks 登录键 -> 登录结果回调（回调一次后删除）
+	loginCallbacks = make(map[uintptr]LoginResultCallback)
+	// nextLoginKey 下一个登录键
+	nextLoginKey uintptr = 1
+
+	// playBackMutex 保护回放回调表
+	playBackMutex sync.Mutex
+	// playBackHandles 回放句柄 -> 回调的 cgo.Handle
+	playBackHandles = make(map[int]cgo.Handle)
+
+	// dataHandleMutex 保护有效的数据回调句柄集合
+	dataHandleMutex sync.RWMutex
+	// dataHandles 尚未释放的数据回调 cgo.Handle
+	// 停止失败时句柄也会释放，SDK之后的迟到回调据此丢弃，避免访问已释放的句柄
+	dataHandles = make(map[cgo.Handle]struct{})
+)
+
+// newDataHandle 为数据回调创建 cgo.Handle 并登记为有效
+func newDataHandle(callback RealDataCallback) cgo.Handle {
+	handle := cgo.NewHandle(callback)
+	dataHandleMutex.Lock()
+	dataHandles[handle] = struct{}{}
+	dataHandleMutex.Unlock()
+	return handle
+}
+
+// deleteDataHandle 注销并释放数据回调的 cgo.Handle
+func deleteDataHandle(handle cgo.Handle) {
+	dataHandleMutex.Lock()
+	delete(dataHandles, handle)
+	dataHandleMutex.Unlock()
+	handle.Delete()
+}
+
+// lookupDataHandle 获取数据回调，句柄已释放时返回nil
+func lookupDataHandle(handle cgo.Handle) RealDataCallback {
+	dataHandleMutex.RLock()
+	defer dataHandleMutex.RUnlock()
+	if _, ok := dataHandles[handle]; !ok {
+		return nil
+	}
+	callback, _ := handle.Value().(RealDataCallback)
+	return callback
+}
+
+// hiksdkMessageCallback 报警回调函数
+// 由C代码调用，将C结构体转换为Go类型后分发给注册的回调
+//
+//export hiksdkMessageCallback
+func hiksdkMessageCallback(command C.LONG, alarm *C.NET_DVR_ALARMER, info *C.char, length C.DWORD, user unsafe.Pointer) {
+	messageMutex.RLock()
+	callback := messageCallback
+	messageMutex.RUnlock()
+
+	dispatchMessage(callback, command, alarm, info, length)
+}
+
+// hiksdkListenCallback 报警监听回调函数
+// 由C代码调用，根据用户数据中的监听键找到对应的回调
+//
+//export hiksdkListenCallback
+func hiksdkListenCallback(command C.LONG, alarm *C.NET_DVR_ALARMER, info *C.char, length C.DWORD, user unsafe.Pointer) {
+	listenMutex.RLock()
+	callback := listenCallbacks[uintptr(user)]
+	listenMutex.RUnlock()
+
+	dispatchMessage(callback, command, alarm, info, length)
+}
+
+// dispatchMessage 拷贝报警信息并调用回调
+func dispatchMessage(callback MessageCallback, command C.LONG, alarm *C.NET_DVR_ALARMER, info *C.char, length C.DWORD) {
+	if callback == nil || alarm == nil {
+		return
+	}
+
+	var data []byte
+	if info != nil && length > 0 {
+		data = C.GoBytes(unsafe.Pointer(info), C.int(length))
+		if command == C.COMM_ALARM_V40 {
+			data = appendAlarmV40Data(data, (*C.NET_DVR_ALARMINFO_V40)(unsafe.Pointer(info)))
+		}
+	}
+
+	callback(int(command), convertAlarmer(alarm), data)
+}
+
+// takeLoginCallback 取出并删除登录结果回调，保证每个登录请求只回调一次
+func takeLoginCallback(key uintptr) LoginResultCallback {
+	loginMutex.Lock()
+	defer loginMutex.Unlock()
+	callback := loginCallbacks[key]
+	delete(loginCallbacks, key)
+	return callback
+}
+
+// GoLoginResultCallback 异步登录结果回调函数
+// 由C代码调用，根据用户数据中的登录键找到对应的回调
+//
+//export GoLoginResultCallback
+func GoLoginResultCallback(userID C.LONG, result C.DWORD, info C.LPNET_DVR_DEVICEINFO_V30, user unsafe.Pointer) {
+	callback := takeLoginCallback(uintptr(user))
+	if callback == nil {
+		return
+	}
+
+	// 失败时的错误码只能在回调线程中获取
+	if result != 1 {
+		callback(-1, nil, int(C.NET_DVR_GetLastError()))
+		return
+	}
+	var deviceInfo DeviceInfo
+	if info != nil {
+		deviceInfo = convertDeviceInfoV30(info)
+	}
+	callback(int(userID), &deviceInfo, NET_DVR_NOERROR)
+}
+
+// GoRealDataCallback 实时数据回调函数
+// 由C代码调用（见 NET_DVR_RealPlay_V40_WithCallback、NET_DVR_SetPlayDataCallBack_V40_WithCallback），
+// handle 为注册预览或回放回调时创建的 cgo.Handle
+//
+//export GoRealDataCallback
+func GoRealDataCallback(realHandle C.LONG, dataType C.DWORD, buffer *C.BYTE, size C.DWORD, handle C.uintptr_t) {
+	if handle == 0 || buffer == nil || size == 0 {
+		return
+	}
+	callback := lookupDataHandle(cgo.Handle(handle))
+	if callback == nil {
+		return
+	}
+
+	// pBuffer 仅在回调期间有效，必须拷贝
+	callback(int(realHandle), int(dataType), C.GoBytes(unsafe.Pointer(buffer), C.int(size)))
+}
+
+// appendAlarmV40Data 将V40报警的可变部分追加到固定部分之后
+// pAlarmData 指向的内存仅在回调期间有效，必须在此处拷贝
+func appendAlarmV40Data(data []byte, info *C.NET_DVR_ALARMINFO_V40) []byte {
+	size := int(unsafe.Sizeof(*info))
+	if len(data) < size || info.pAlarmData == nil {
+		return data
+	}
+
+	num := int(C.hiksdk_alarm_v40_data_num(info))
+	if num <= 0 {
+		return data[:size]
+	}
+	return append(data[:size], C.GoBytes(unsafe.Pointer(info.pAlarmData), C.int(num*4))...)
+}
+
+func (cgoBackend) Init() bool {
+	return C.NET_DVR_Init() == C.TRUE
+}
+
+func (cgoBackend) Cleanup() bool {
+	return C.NET_DVR_Cleanup() == C.TRUE
+}
+
+func (cgoBackend) SetConnectTime(waitTime, tryTimes uint32) bool {
+	return C.NET_DVR_SetConnectTime(C.DWORD(waitTime), C.DWORD(tryTimes)) == C.TRUE
+}
+
+func (cgoBackend) SetReconnect(interval uint32, enable bool) bool {
+	return C.NET_DVR_SetReconnect(C.DWORD(interval), cBool(enable)) == C.TRUE
+}
+
+func (cgoBackend) SetLogToFile(level int, logDir string, autoDelete bool) bool {
+	cLogDir := C.CString(logDir)
+	defer C.free(unsafe.Pointer(cLogDir))
+
+	return C.NET_DVR_SetLogToFile(C.DWORD(level), cLogDir, cBool(autoDelete)) == C.TRUE
+}
+
+func (cgoBackend) SetRecvTimeOut(timeout uint32) bool {
+	return C.NET_DVR_SetRecvTimeOut(C.DWORD(timeout)) == C.TRUE
+}
+
+func (cgoBackend) SetSDKInitCfg(cfgType int, path string) bool {
+	if cfgType == NET_SDK_INIT_CFG_SDK_PATH {
+		var sdkPath C.NET_DVR_LOCAL_SDK_PATH
+		utils.Strcpy(unsafe.Pointer(&sdkPath.sPath[0]), path, len(sdkPath.sPath))
+		return C.NET_DVR_SetSDKInitCfg(C.NET_SDK_INIT_CFG_TYPE(cfgType), unsafe.Pointer(&sdkPath)) == C.TRUE
+	}
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return C.NET_DVR_SetSDKInitCfg(C.NET_SDK_INIT_CFG_TYPE(cfgType), unsafe.Pointer(cPath)) == C.TRUE
+}
+
+func (cgoBackend) LoginV40(loginInfo *LoginInfo, deviceInfo *DeviceInfo) int {
+	var deviceInfoV40 C.NET_DVR_DEVICEINFO_V40
+	var userLoginInfo C.NET_DVR_USER_LOGIN_INFO
+
+	// 设置登录参数
+	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sDeviceAddress[0]), loginInfo.DeviceAddress, len(userLoginInfo.sDeviceAddress))
+	userLoginInfo.wPort = C.WORD(loginInfo.Port)
+	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sUserName[0]), loginInfo.Username, len(userLoginInfo.sUserName))
+	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sPassword[0]), loginInfo.Password, len(userLoginInfo.sPassword))
+
+	// 设置为同步登录模式（0=同步，1=异步）
+	userLoginInfo.byUseAsynLogin = 0
+
+	loginID := int(C.NET_DVR_Login_V40(&userLoginInfo, &deviceInfoV40))
+	if deviceInfo == nil {
+		return loginID
+	}
+	if loginID >= 0 {
+		*deviceInfo = convertDeviceInfoV30(&deviceInfoV40.struDeviceV30)
+		deviceInfo.PasswordLevel = int(deviceInfoV40.byPasswordLevel)
+	}
+	// 登录失败时SDK同样返回锁定信息（剩余尝试次数、剩余锁定时间）
+	deviceInfo.SupportLock = deviceInfoV40.bySupportLock == 1
+	deviceInfo.RetryLoginTime = int(deviceInfoV40.byRetryLoginTime)
+	deviceInfo.SurplusLockTime = int(deviceInfoV40.dwSurplusLockTime)
+	return loginID
+}
+
+func (cgoBackend) LoginV40Async(loginInfo *LoginInfo, callback LoginResultCallback) bool {
+	var deviceInfoV40 C.NET_DVR_DEVICEINFO_V40
+	var userLoginInfo C.NET_DVR_USER_LOGIN_INFO
+
+	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sDeviceAddress[0]), loginInfo.DeviceAddress, len(userLoginInfo.sDeviceAddress))
+	userLoginInfo.wPort = C.WORD(loginInfo.Port)
+	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sUserName[0]), loginInfo.Username, len(userLoginInfo.sUserName))
+	utils.Strcpy(unsafe.Pointer(&userLoginInfo.sPassword[0]), loginInfo.Password, len(userLoginInfo.sPassword))
+
+	loginMutex.Lock()
+	key := nextLoginKey
+	nextLoginKey++
+	loginCallbacks[key] = callback
+	loginMutex.Unlock()
+
+	C.hiksdkSetAsyncLogin(&userLoginInfo, C.uintptr_t(key))
+	if C.NET_DVR_Login_V40(&userLoginInfo, &deviceInfoV40) < 0 {
+		// 请求未提交，不会再有回调
+		takeLoginCallback(key)
+		return false
+	}
+	return true
+}
+
+func (cgoBackend) LoginV30(ip string, port int, username, password string, deviceInfo *DeviceInfo) int {
+	var deviceInfoV30 C.NET_DVR_DEVICEINFO_V30
+
+	cIP := C.CString(ip)
+	cUser := C.CString(username)
+	cPasswd := C.CString(password)
+	defer func() {
+		C.free(unsafe.Pointer(cIP))
+		C.free(unsafe.Pointer(cUser))
+		C.free(unsafe.Pointer(cPasswd))
+	}()
+
+	loginID := int(C.NET_DVR_Login_V30(cIP, C.WORD(port), cUser, cPasswd, &deviceInfoV30))
+	if loginID >= 0 && deviceInfo != nil {
+		*deviceInfo = convertDeviceInfoV30(&deviceInfoV30)
+	}
+	return loginID
+}
+
+func (cgoBackend) Logout(userID int) bool {
+	return C.NET_DVR_Logout(C.LONG(userID)) == C.TRUE
+}
+
+func (cgoBackend) CheckUserStatus(userID int) bool {
+	return C.NET_DVR_RemoteControl(C.LONG(userID), C.NET_DVR_CHECK_USER_STATUS, nil, 0) == C.TRUE
+}
+
+func (cgoBackend) GetDVRIPByResolveSvrEx(serverIP string, serverPort uint16, dvrName, serialNumber string) (string, uint32, bool) {
+	cServerIP := C.CString(serverIP)
+	defer C.free(unsafe.Pointer(cServerIP))
+
+	// 准备设备名称参数（C内存，避免向C传递Go指针）
+	var cDVRName *C.BYTE
+	if dvrName != "" {
+		cDVRName = (*C.BYTE)(C.CBytes([]byte(dvrName)))
+		defer C.free(unsafe.Pointer(cDVRName))
+	}
+
+	// 准备序列号参数
+	var cSerialNumber *C.BYTE
+	if serialNumber != "" {
+		cSerialNumber = (*C.BYTE)(C.CBytes([]byte(serialNumber)))
+		defer C.free(unsafe.Pointer(cSerialNumber))
+	}
+
+	// 准备输出缓冲区
+	cGetIP := (*C.char)(C.calloc(128, 1))
+	defer C.free(unsafe.Pointer(cGetIP))
+	var dwPort C.DWORD
+
+	result := C.NET_DVR_GetDVRIPByResolveSvr_EX(
+		cServerIP,
+		C.WORD(serverPort),
+		cDVRName,
+		C.WORD(len(dvrName)),
+		cSerialNumber,
+		C.WORD(len(serialNumber)),
+		cGetIP,
+		&dwPort,
+	)
+	if result != C.TRUE {
+		return "", 0, false
+	}
+	return C.GoString(cGetIP), uint32(dwPort), true
+}
+
+func (cgoBackend) GetDVRConfig(userID, command, channel int, buf []byte) (int, bool) {
+	if len(buf) == 0 {
+		return 0, false
+	}
+
+	// 使用C内存，避免SDK持有Go指针
+	cBuf := C.calloc(C.size_t(len(buf)), 1)
+	defer C.free(cBuf)
+
+	var returned C.DWORD
+	if C.NET_DVR_GetDVRConfig(C.LONG(userID), C.DWORD(command), C.LONG(channel), C.LPVOID(cBuf), C.DWORD(len(buf)), &returned) != C.TRUE {
+		return 0, false
+	}
+	copy(buf, unsafe.Slice((*byte)(cBuf), len(buf)))
+	return int(returned), true
+}
+
+func (cgoBackend) SetDVRConfig(userID, command, channel int, buf []byte) bool {
+	if len(buf) == 0 {
+		return false
+	}
+
+	cBuf := C.CBytes(buf)
+	defer C.free(cBuf)
+
+	return C.NET_DVR_SetDVRConfig(C.LONG(userID), C.DWORD(command), C.LONG(channel), C.LPVOID(cBuf), C.DWORD(len(buf))) == C.TRUE
+}
+
+func (cgoBackend) SetDVRMessageCallBackV30(callback MessageCallback) bool {
+	messageMutex.Lock()
+	messageCallback = callback
+	messageMutex.Unlock()
+
+	return C.NET_DVR_SetDVRMessageCallBack_V30((*[0]byte)(C.hiksdkMessageCallback), nil) == C.TRUE
+}
+
+func (cgoBackend) SetupAlarmChanV41(userID int, param *AlarmSetupParam) int {
+	var setupParam C.NET_DVR_SETUPALARM_PARAM
+	setupParam.dwSize = C.DWORD(unsafe.Sizeof(setupParam))
+	setupParam.byLevel = C.BYTE(param.Level)
+	setupParam.byAlarmInfoType = C.BYTE(param.AlarmInfoType)
+	setupParam.byRetAlarmTypeV40 = C.BYTE(param.RetAlarmTypeV40)
+	setupParam.byFaceAlarmDetection = C.BYTE(param.FaceAlarmDetection)
+	setupParam.bySupport = C.BYTE(param.Support)
+	setupParam.byBrokenNetHttp = C.BYTE(param.BrokenNetHTTP)
+	setupParam.byDeployType = C.BYTE(param.DeployType)
+	setupParam.bySubScription = C.BYTE(param.Subscription)
+	setupParam.byAlarmTypeURL = C.BYTE(param.AlarmTypeURL)
+	setupParam.byCustomCtrl = C.BYTE(param.CustomCtrl)
+
+	return int(C.NET_DVR_SetupAlarmChan_V41(C.LONG(userID), &setupParam))
+}
+
+func (cgoBackend) CloseAlarmChanV30(alarmHandle int) bool {
+	return C.NET_DVR_CloseAlarmChan_V30(C.LONG(alarmHandle)) == C.TRUE
+}
+
+func (cgoBackend) StartListenV30(localIP string, localPort uint16, callback MessageCallback) int {
+	var cIP *C.char
+	if localIP != "" {
+		cIP = C.CString(localIP)
+		defer C.free(unsafe.Pointer(cIP))
+	}
+
+	listenMutex.Lock()
+	key := nextListenKey
+	nextListenKey++
+	listenCallbacks[key] = callback
+	listenMutex.Unlock()
+
+	handle := int(C.hiksdkStartListen(cIP, C.WORD(localPort), C.uintptr_t(key)))
+
+	listenMutex.Lock()
+	if handle < 0 {
+		delete(listenCallbacks, key)
+	} else {
+		listenKeys[handle] = key
+	}
+	listenMutex.Unlock()
+	return handle
+}
+
+func (cgoBackend) StopListenV30(listenHandle int) bool {
+	if C.NET_DVR_StopListen_V30(C.LONG(listenHandle)) != C.TRUE {
+		return false
+	}
+
+	listenMutex.Lock()
+	delete(listenCallbacks, listenKeys[listenHandle])
+	delete(listenKeys, listenHandle)
+	listenMutex.Unlock()
+	return true
+}
+
+func (cgoBackend) RealPlayV40(userID int, info *PreviewInfo, callback RealDataCallback) int {
+	var previewInfo C.NET_DVR_PREVIEWINFO
+	previewInfo.lChannel = C.LONG(info.Channel)
+	previewInfo.dwStreamType = C.DWORD(info.StreamType)
+	previewInfo.dwLinkMode = C.DWORD(info.LinkMode)
+	previewInfo.bBlocked = cBool(info.Blocked)
+
+	// 回调通过 cgo.Handle 传递给SDK，停止预览时释放
+	handle := newDataHandle(callback)
+	realHandle := int(C.NET_DVR_RealPlay_V40_WithCallback(C.LONG(userID), &previewInfo, C.uintptr_t(handle)))
+	if realHandle < 0 {
+		deleteDataHandle(handle)
+		return realHandle
+	}
+
+	realPlayMutex.Lock()
+	realPlayHandles[realHandle] = handle
+	realPlayMutex.Unlock()
+	return realHandle
+}
+
+func (cgoBackend) StopRealPlay(realHandle int) bool {
+	ok := C.NET_DVR_StopRealPlay(C.LONG(realHandle)) == C.TRUE
+
+	// 无论停止是否成功都释放 cgo.Handle，调用方不会再用该句柄重试；
+	// 停止失败后SDK若仍有迟到回调，会因句柄已注销而被丢弃
+	realPlayMutex.Lock()
+	if handle, found := realPlayHandles[realHandle]; found {
+		deleteDataHandle(handle)
+		delete(realPlayHandles, realHandle)
+	}
+	realPlayMutex.Unlock()
+	return ok
+}
+
+func (cgoBackend) PTZControlWithSpeedOther(userID, channel, command, stop, speed int) bool {
+	return C.NET_DVR_PTZControlWithSpeed_Other(
+		C.LONG(userID),
+		C.LONG(channel),
+		C.DWORD(command),
+		C.DWORD(stop),
+		C.DWORD(speed),
+	) == C.TRUE
+}
+
+func (cgoBackend) PTZPresetOther(userID, channel, command, presetIndex int) bool {
+	return C.NET_DVR_PTZPreset_Other(
+		C.LONG(userID),
+		C.LONG(channel),
+		C.DWORD(command),
+		C.DWORD(presetIndex),
+	) == C.TRUE
+}
+
+func (cgoBackend) PTZCruiseOther(userID, channel, command, route, point, input int) bool {
+	return C.NET_DVR_PTZCruise_Other(
+		C.LONG(userID),
+		C.LONG(channel),
+		C.DWORD(command),
+		C.BYTE(route),
+		C.BYTE(point),
+		C.WORD(input),
+	) == C.TRUE
+}
+
+func (cgoBackend) PTZTrackOther(userID, channel, command int) bool {
+	return C.NET_DVR_PTZTrack_Other(
+		C.LONG(userID),
+		C.LONG(channel),
+		C.DWORD(command),
+	) == C.TRUE
+}
+
+func (cgoBackend) PTZSelZoomInEx(userID, channel int, frame *PointFrame) bool {
+	pointFrame := C.NET_DVR_POINT_FRAME{
+		xTop:    C.int(frame.XTop),
+		yTop:    C.int(frame.YTop),
+		xBottom: C.int(frame.XBottom),
+		yBottom: C.int(frame.YBottom),
+	}
+	return C.NET_DVR_PTZSelZoomIn_EX(C.LONG(userID), C.LONG(channel), &pointFrame) == C.TRUE
+}
+
+func (cgoBackend) CaptureJPEGPicture(userID, channel int, para *JPEGPara, fileName string) bool {
+	jpegPara := C.NET_DVR_JPEGPARA{
+		wPicSize:    C.WORD(para.PicSize),
+		wPicQuality: C.WORD(para.PicQuality),
+	}
+	cFileName := C.CString(fileName)
+	defer C.free(unsafe.Pointer(cFileName))
+
+	return C.NET_DVR_CaptureJPEGPicture(C.LONG(userID), C.LONG(channel), &jpegPara, cFileName) == C.TRUE
+}
+
+func (cgoBackend) CaptureJPEGPictureNew(userID, channel int, para *JPEGPara, buf []byte) (int, bool) {
+	if len(buf) == 0 {
+		return 0, false
+	}
+	jpegPara := C.NET_DVR_JPEGPARA{
+		wPicSize:    C.WORD(para.PicSize),
+		wPicQuality: C.WORD(para.PicQuality),
+	}
+
+	// 使用C内存，避免SDK持有Go指针
+	cBuf := C.malloc(C.size_t(len(buf)))
+	defer C.free(cBuf)
+
+	var returned C.DWORD
+	if C.NET_DVR_CaptureJPEGPicture_NEW(C.LONG(userID), C.LONG(channel), &jpegPara, (*C.char)(cBuf), C.DWORD(len(buf)), &returned) != C.TRUE {
+		return 0, false
+	}
+	n := min(int(returned), len(buf))
+	copy(buf, unsafe.Slice((*byte)(cBuf), n))
+	return n, true
+}
+
+func (cgoBackend) FindFileV40(userID int, cond *FileCond) int {
+	var findCond C.NET_DVR_FILECOND_V40
+	findCond.lChannel = C.LONG(cond.Channel)
+	findCond.dwFileType = C.DWORD(cond.FileType)
+	findCond.dwIsLocked = 0xff
+	findCond.struStartTime = cTime(cond.Start)
+	findCond.struStopTime = cTime(cond.Stop)
+	findCond.byStreamType = 0xff
+
+	return int(C.NET_DVR_FindFile_V40(C.LONG(userID), &findCond))
+}
+
+func (cgoBackend) FindNextFileV40(findHandle int, data *FindData) int {
+	var findData C.NET_DVR_FINDDATA_V40
+	status := int(C.NET_DVR_FindNextFile_V40(C.LONG(findHandle), &findData))
+	if status == NET_DVR_FILE_SUCCESS && data != nil {
+		*data = FindData{
+			FileName: cString(unsafe.Pointer(&findData.sFileName[0]), len(findData.sFileName)),
+			Start:    goTime(&findData.struStartTime),
+			Stop:     goTime(&findData.struStopTime),
+			FileSize: int64(findData.dwFileSize),
+			FileType: int(findData.byFileType),
+			Locked:   findData.byLocked == 1,
+		}
+	}
+	return status
+}
+
+func (cgoBackend) FindCloseV30(findHandle int) bool {
+	return C.NET_DVR_FindClose_V30(C.LONG(findHandle)) == C.TRUE
+}
+
+func (cgoBackend) GetFileByTimeV40(userID int, fileName string, cond *PlayCond) int {
+	var playCond C.NET_DVR_PLAYCOND
+	playCond.dwChannel = C.DWORD(cond.Channel)
+	playCond.struStartTime = cTime(cond.Start)
+	playCond.struStopTime = cTime(cond.Stop)
+	playCond.byStreamType = C.BYTE(cond.StreamType)
+
+	cFileName := C.CString(fileName)
+	defer C.free(unsafe.Pointer(cFileName))
+
+	return int(C.NET_DVR_GetFileByTime_V40(C.LONG(userID), cFileName, &playCond))
+}
+
+func (cgoBackend) PlayBackControlV40(playHandle, command int, in, out []byte) (int, bool) {
+	// 使用C内存，避免SDK持有Go指针
+	var cIn, cOut unsafe.Pointer
+	if len(in) > 0 {
+		cIn = C.CBytes(in)
+		defer C.free(cIn)
+	}
+	if len(out) > 0 {
+		cOut = C.calloc(C.size_t(len(out)), 1)
+		defer C.free(cOut)
+	}
+
+	var returned C.DWORD
+	if C.NET_DVR_PlayBackControl_V40(C.LONG(playHandle), C.DWORD(command), C.LPVOID(cIn), C.DWORD(len(in)), C.LPVOID(cOut), &returned) != C.TRUE {
+		return 0, false
+	}
+	if cOut == nil {
+		return 0, true
+	}
+	n := min(int(returned), len(out))
+	copy(out, unsafe.Slice((*byte)(cOut), n))
+	return n, true
+}
+
+func (cgoBackend) GetDownloadPos(fileHandle int) int {
+	return int(C.NET_DVR_GetDownloadPos(C.LONG(fileHandle)))
+}
+
+func (cgoBackend) StopGetFile(fileHandle int) bool {
+	return C.NET_DVR_StopGetFile(C.LONG(fileHandle)) == C.TRUE
+}
+
+func (cgoBackend) PlayBackByTimeV40(userID int, cond *PlayCond) int {
+	var vodPara C.NET_DVR_VOD_PARA
+	vodPara.dwSize = C.DWORD(unsafe.Sizeof(vodPara))
+	vodPara.struIDInfo.dwSize = C.DWORD(unsafe.Sizeof(vodPara.struIDInfo))
+	vodPara.struIDInfo.dwChannel = C.DWORD(cond.Channel)
+	vodPara.struBeginTime = cTime(cond.Start)
+	vodPara.struEndTime = cTime(cond.Stop)
+	vodPara.byStreamType = C.BYTE(cond.StreamType)
+
+	return int(C.NET_DVR_PlayBackByTime_V40(C.LONG(userID), &vodPara))
+}
+
+func (cgoBackend) SetPlayDataCallBackV40(playHandle int, callback RealDataCallback) bool {
+	// 回调通过 cgo.Handle 传递给SDK，停止回放时释放
+	handle := newDataHandle(callback)
+	if C.NET_DVR_SetPlayDataCallBack_V40_WithCallback(C.LONG(playHandle), C.uintptr_t(handle)) != C.TRUE {
+		deleteDataHandle(handle)
+		return false
+	}
+
+	playBackMutex.Lock()
+	if old, ok := playBackHandles[playHandle]; ok {
+		deleteDataHandle(old)
+	}
+	playBackHandles[playHandle] = handle
+	playBackMutex.Unlock()
+	return true
+}
+
+func (cgoBackend) GetPlayBackOsdTime(playHandle int) (time.Time, bool) {
+	var osdTime C.NET_DVR_TIME
+	if C.NET_DVR_GetPlayBackOsdTime(C.LONG(playHandle), &osdTime) != C.TRUE {
+		return time.Time{}, false
+	}
+	return goTime(&osdTime), true
+}
+
+func (cgoBackend) StopPlayBack(playHandle int) bool {
+	ok := C.NET_DVR_StopPlayBack(C.LONG(playHandle)) == C.TRUE
+
+	// 与 StopRealPlay 相同，停止失败时也释放 cgo.Handle
+	playBackMutex.Lock()
+	if handle, found := playBackHandles[playHandle]; found {
+		deleteDataHandle(handle)
+		delete(playBackHandles, playHandle)
+	}
+	playBackMutex.Unlock()
+	return ok
+}
+
+func (cgoBackend) GetDeviceAbility(userID, abilityType int, in, out []byte) (int, bool) {
+	if len(out) == 0 {
+		return 0, false
+	}
+
+	// 使用C内存，避免SDK持有Go指针
+	var cIn *C.char
+	if len(in) > 0 {
+		cIn = (*C.char)(C.CBytes(in))
+		defer C.free(unsafe.Pointer(cIn))
+	}
+	cOut := (*C.char)(C.calloc(C.size_t(len(out)), 1))
+	defer C.free(unsafe.Pointer(cOut))
+
+	if C.NET_DVR_GetDeviceAbility(C.LONG(userID), C.DWORD(abilityType), cIn, C.DWORD(len(in)), cOut, C.DWORD(len(out))) != C.TRUE {
+		return 0, false
+	}
+	// 输出为以空字符结尾的XML
+	n := int(C.strnlen(cOut, C.size_t(len(out))))
+	copy(out, unsafe.Slice((*byte)(unsafe.Pointer(cOut)), n))
+	return n, true
+}
+
+func (cgoBackend) STDXMLConfig(userID int, request string, in, out, status []byte) (int, int, bool) {
+	cRequest := C.CString(request)
+	defer C.free(unsafe.Pointer(cRequest))
+
+	var input C.NET_DVR_XML_CONFIG_INPUT
+	input.dwSize = C.DWORD(unsafe.Sizeof(input))
+	input.lpRequestUrl = unsafe.Pointer(cRequest)
+	input.dwRequestUrlLen = C.DWORD(len(request))
+	if len(in) > 0 {
+		cIn := C.CBytes(in)
+		defer C.free(cIn)
+		input.lpInBuffer = cIn
+		input.dwInBufferSize = C.DWORD(len(in))
+	}
+
+	var output C.NET_DVR_XML_CONFIG_OUTPUT
+	output.dwSize = C.DWORD(unsafe.Sizeof(output))
+	if len(out) > 0 {
+		cOut := C.calloc(C.size_t(len(out)), 1)
+		defer C.free(cOut)
+		output.lpOutBuffer = cOut
+		output.dwOutBufferSize = C.DWORD(len(out))
+	}
+	if len(status) > 0 {
+		cStatus := C.calloc(C.size_t(len(status)), 1)
+		defer C.free(cStatus)
+		output.lpStatusBuffer = cStatus
+		output.dwStatusSize = C.DWORD(len(status))
+	}
+
+	ok := C.NET_DVR_STDXMLConfig(C.LONG(userID), &input, &output) == C.TRUE
+
+	// 失败时响应状态中有设备返回的错误详情，同样需要拷贝
+	statusLen := 0
+	if output.lpStatusBuffer != nil {
+		statusLen = int(C.strnlen((*C.char)(output.lpStatusBuffer), C.size_t(len(status))))
+		copy(status, unsafe.Slice((*byte)(output.lpStatusBuffer), statusLen))
+	}
+	if !ok {
+		return 0, statusLen, false
+	}
+	outLen := 0
+	if output.lpOutBuffer != nil {
+		outLen = min(int(output.dwReturnedXMLSize), len(out))
+		copy(out, unsafe.Slice((*byte)(output.lpOutBuffer), outLen))
+	}
+	return outLen, statusLen, true
+}
+
+func (cgoBackend) GetLastError() int {
+	return int(C.NET_DVR_GetLastError())
+}
+
+func (cgoBackend) GetErrorMsg() (int, string) {
+	var code C.LONG
+	msg := C.NET_DVR_GetErrorMsg(&code)
+	if msg == nil {
+		return int(code), ""
+	}
+	return int(code), C.GoString(msg)
+}
+
+// cBool 将Go布尔值转换为C的BOOL
+func cBool(b bool) C.BOOL {
+	if b {
+		return C.BOOL(1)
+	}
+	return C.BOOL(0)
+}
+
+// cString 将定长C字节数组转换为Go字符串（去除空字符）
+func cString(p unsafe.Pointer, n int) string {
+	return strings.TrimRight(string(C.GoBytes(p, C.int(n))), "\x00")
+}
+
+// cTime 将Go时间转换为设备时间（按本地时区）
+func cTime(t time.Time) C.NET_DVR_TIME {
+	t = t.Local()
+	return C.NET_DVR_TIME{
+		dwYear:   C.DWORD(t.Year()),
+		dwMonth:  C.DWORD(t.Month()),
+		dwDay:    C.DWORD(t.Day()),
+		dwHour:   C.DWORD(t.Hour()),
+		dwMinute: C.DWORD(t.Minute()),
+		dwSecond: C.DWORD(t.Second()),
+	}
+}
+
+// goTime 将设备时间转换为Go时间（按本地时区）
+func goTime(t *C.NET_DVR_TIME) time.Time {
+	return time.Date(int(t.dwYear), time.Month(t.dwMonth), int(t.dwDay),
+		int(t.dwHour), int(t.dwMinute), int(t.dwSecond), 0, time.Local)
+}
+
+// convertDeviceInfoV30 将C设备信息转换为Go类型
+func convertDeviceInfoV30(info *C.NET_DVR_DEVICEINFO_V30) DeviceInfo {
+	return DeviceInfo{
+		SerialNumber:    cString(unsafe.Pointer(&info.sSerialNumber[0]), len(info.sSerialNumber)),
+		AlarmInPortNum:  int(info.byAlarmInPortNum),
+		AlarmOutPortNum: int(info.byAlarmOutPortNum),
+		DiskNum:         int(info.byDiskNum),
+		DVRType:         int(info.byDVRType),
+		ChanNum:         int(info.byChanNum),
+		StartChan:       int(info.byStartChan),
+		AudioChanNum:    int(info.byAudioChanNum),
+		IPChanNum:       int(info.byIPChanNum) | int(info.byHighDChanNum)<<8,
+		ZeroChanNum:     int(info.byZeroChanNum),
+		DevType:         int(info.wDevType),
+		StartDChan:      int(info.byStartDChan),
+	}
+}
+
+// alarmerLayout 返回 NET_DVR_ALARMER 的大小和关键字段偏移，用于检查与官方头文件的布局一致
+func alarmerLayout() (size uintptr, offsets map[string]uintptr) {
+	var a C.NET_DVR_ALARMER
+	return unsafe.Sizeof(a), map[string]uintptr{
+		"lUserID":         unsafe.Offsetof(a.lUserID),
+		"sSerialNumber":   unsafe.Offsetof(a.sSerialNumber),
+		"dwDeviceVersion": unsafe.Offsetof(a.dwDeviceVersion),
+		"sDeviceName":     unsafe.Offsetof(a.sDeviceName),
+		"byMacAddr":       unsafe.Offsetof(a.byMacAddr),
+		"wLinkPort":       unsafe.Offsetof(a.wLinkPort),
+		"sDeviceIP":       unsafe.Offsetof(a.sDeviceIP),
+		"sSocketIP":       unsafe.Offsetof(a.sSocketIP),
+		"byIpProtocol":    unsafe.Offsetof(a.byIpProtocol),
+		"wSocketPort":     unsafe.Offsetof(a.wSocketPort),
+	}
+}
+
+// convertAlarmer 将C报警设备信息转换为Go类型
+func convertAlarmer(alarm *C.NET_DVR_ALARMER) *Alarmer {
+	alarmer := &Alarmer{UserID: -1}
+
+	if alarm.byUserIDValid == 1 {
+		alarmer.UserID = int(alarm.lUserID)
+	}
+	if alarm.bySerialValid == 1 {
+		alarmer.SerialNumber = cString(unsafe.Pointer(&alarm.sSerialNumber[0]), len(alarm.sSerialNumber))
+	}
+	if alarm.byDeviceNameValid == 1 {
+		alarmer.DeviceName = cString(unsafe.Pointer(&alarm.sDeviceName[0]), len(alarm.sDeviceName))
+	}
+	if alarm.byDeviceIPValid == 1 {
+		alarmer.DeviceIP = cString(unsafe.Pointer(&alarm.sDeviceIP[0]), len(alarm.sDeviceIP))
+	}
+	if alarm.byLinkPortValid == 1 {
+		alarmer.LinkPort = int(alarm.wLinkPort)
+	}
+
+	return alarmer
+}
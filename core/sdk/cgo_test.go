@@ -0,0 +1,68 @@
+//go:build cgo
+
+package sdk
+
+import "testing"
+
+// TestAlarmerLayout NET_DVR_ALARMER 的布局与官方头文件（HCNetSDK.h）一致
+func TestAlarmerLayout(t *testing.T) {
+	size, offsets := alarmerLayout()
+	if size != 372 {
+		t.Errorf("结构体大小为 %d，官方为 372", size)
+	}
+	want := map[string]uintptr{
+		"lUserID":         8,
+		"sSerialNumber":   12,
+		"dwDeviceVersion": 60,
+		"sDeviceName":     64,
+		"byMacAddr":       96,
+		"wLinkPort":       102,
+		"sDeviceIP":       104,
+		"sSocketIP":       232,
+		"byIpProtocol":    360,
+		"wSocketPort":     364,
+	}
+	for field, offset := range want {
+		if offsets[field] != offset {
+			t.Errorf("%s 偏移为 %d，官方为 %d", field, offsets[field], offset)
+		}
+	}
+}
+
+// TestStopReleasesDataHandle 停止预览/回放失败时也释放回调句柄，迟到的回调被丢弃
+func TestStopReleasesDataHandle(t *testing.T) {
+	callback := RealDataCallback(func(int, int, []byte) {})
+
+	// 未初始化SDK时对无效句柄停止必然失败
+	const realHandle, playHandle = 9001, 9002
+	realPlayMutex.Lock()
+	realPlayHandles[realHandle] = newDataHandle(callback)
+	realPlayMutex.Unlock()
+	playBackMutex.Lock()
+	playBackHandles[playHandle] = newDataHandle(callback)
+	playBackMutex.Unlock()
+
+	if (cgoBackend{}).StopRealPlay(realHandle) {
+		t.Fatal("停止无效预览句柄应失败")
+	}
+	if (cgoBackend{}).StopPlayBack(playHandle) {
+		t.Fatal("停止无效回放句柄应失败")
+	}
+
+	realPlayMutex.Lock()
+	_, realLeft := realPlayHandles[realHandle]
+	realPlayMutex.Unlock()
+	playBackMutex.Lock()
+	_, playLeft := playBackHandles[playHandle]
+	playBackMutex.Unlock()
+	if realLeft || playLeft {
+		t.Errorf("停止失败后句柄表未清理：预览 %v，回放 %v", realLeft, playLeft)
+	}
+
+	dataHandleMutex.RLock()
+	left := len(dataHandles)
+	dataHandleMutex.RUnlock()
+	if left != 0 {
+		t.Errorf("仍有 %d 个回调句柄未释放", left)
+	}
+}
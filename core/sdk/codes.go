@@ -0,0 +1,51 @@
+package sdk
+
+// 常用错误码（与 hiksdk_wrapper.h 及官方文档保持一致）
+const (
+	NET_DVR_NOERROR              = 0   // 没有错误
+	NET_DVR_PASSWORD_ERROR       = 1   // 用户名密码错误
+	NET_DVR_NOENOUGHPRI          = 2   // 权限不足
+	NET_DVR_NOINIT               = 3   // 没有初始化
+	NET_DVR_CHANNEL_ERROR        = 4   // 通道号错误
+	NET_DVR_OVER_MAXLINK         = 5   // 连接到DVR的客户端个数超过最大
+	NET_DVR_VERSIONNOMATCH       = 6   // 版本不匹配
+	NET_DVR_NETWORK_FAIL_CONNECT = 7   // 连接服务器失败
+	NET_DVR_NETWORK_SEND_ERROR   = 8   // 向服务器发送失败
+	NET_DVR_NETWORK_RECV_ERROR   = 9   // 从服务器接收数据失败
+	NET_DVR_NETWORK_RECV_TIMEOUT = 10  // 从服务器接收数据超时
+	NET_DVR_PARAMETER_ERROR      = 17  // 参数错误
+	NET_DVR_NOSUPPORT            = 23  // 设备不支持该功能
+	NET_DVR_NOENOUGH_BUF         = 43  // 缓冲区太小
+	NET_DVR_USERNOTEXIST         = 47  // 用户不存在（登录ID已注销或不可用）
+	NET_DVR_USER_LOCKED          = 153 // 用户被锁定
+	NET_DVR_LOADLIBRARY_ERROR    = -1  // 未启用CGO，SDK动态库不可用（非SDK错误码，SDK的401为RTSP无权限）
+)
+
+// 初始化参数类型（SetSDKInitCfg）
+const (
+	NET_SDK_INIT_CFG_SDK_PATH    = 2 // 组件库（HCNetSDKCom）所在目录
+	NET_SDK_INIT_CFG_LIBEAY_PATH = 3 // libcrypto（libeay32）完整路径
+	NET_SDK_INIT_CFG_SSLEAY_PATH = 4 // libssl（ssleay32）完整路径
+)
+
+// 查找录像文件的返回状态（FindNextFileV40 的返回值）
+const (
+	NET_DVR_FILE_SUCCESS   = 1000 // 获取文件信息成功
+	NET_DVR_FILE_NOFIND    = 1001 // 没有文件
+	NET_DVR_ISFINDING      = 1002 // 正在查找，请等待
+	NET_DVR_NOMOREFILE     = 1003 // 没有更多的文件，查找结束
+	NET_DVR_FILE_EXCEPTION = 1004 // 查找文件时异常
+)
+
+// 回放控制命令（PlayBackControlV40）
+const (
+	NET_DVR_PLAYSTART   = 1  // 开始播放（下载）
+	NET_DVR_PLAYSTOP    = 2  // 停止播放
+	NET_DVR_PLAYPAUSE   = 3  // 暂停播放
+	NET_DVR_PLAYRESTART = 4  // 恢复播放
+	NET_DVR_PLAYFAST    = 5  // 快放（每次速度加倍，最快16倍）
+	NET_DVR_PLAYSLOW    = 6  // 慢放（每次速度减半，最慢1/16）
+	NET_DVR_PLAYNORMAL  = 7  // 正常速度
+	NET_DVR_PLAYGETPOS  = 13 // 获取回放进度（输出int：0-100，200表示网络异常）
+	NET_DVR_PLAYSETTIME = 26 // 按绝对时间定位（输入 NET_DVR_TIME）
+)
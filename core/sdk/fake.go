@@ -0,0 +1,1009 @@
+package sdk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Call 模拟后端记录的一次调用
+type Call struct {
+	Method string // 方法名（与 Backend 接口方法同名）
+	Args   []any  // 调用参数
+}
+
+// String 返回便于调试的调用描述
+func (c Call) String() string {
+	return fmt.Sprintf("%s%v", c.Method, c.Args)
+}
+
+// 编译期检查 Fake 实现了 Backend 接口
+var _ Backend = (*Fake)(nil)
+
+// fakeConfigKey 设备配置键
+type fakeConfigKey struct {
+	command int
+	channel int
+}
+
+// fakeAbilityKey 能力集键
+type fakeAbilityKey struct {
+	abilityType int
+	in          string
+}
+
+// fakeFailure 注入的失败规则
+type fakeFailure struct {
+	code  int // 失败时的错误码
+	times int // 剩余失败次数，<0 表示一直失败
+}
+
+// fakeFind 文件查找状态
+type fakeFind struct {
+	files   []FindData // 符合条件的文件
+	next    int        // 下一个返回的文件
+	waiting bool       // 下一次 FindNextFileV40 是否返回 NET_DVR_ISFINDING
+}
+
+// fakeDownload 下载状态
+type fakeDownload struct {
+	fileName string // 保存路径
+	data     []byte // 录像数据
+	written  int    // 已写入文件的字节数
+	started  bool   // 是否已开始下载
+}
+
+// fakeISAPIError 注入的ISAPI请求失败
+type fakeISAPIError struct {
+	code   int    // 错误码
+	status []byte // 设备返回的响应状态
+}
+
+// fakePlayback 回放状态
+type fakePlayback struct {
+	cond     PlayCond         // 回放条件
+	callback RealDataCallback // 数据回调
+	started  bool             // 是否已开始播放
+	paused   bool             // 是否暂停
+	speed    int              // 速度档位：0-正常，>0 快放，<0 慢放
+	osdTime  time.Time        // 当前OSD时间
+}
+
+// Fake 纯Go实现的模拟后端
+// 记录所有调用并按需模拟错误，用于在无设备、无CGO的环境下测试上层逻辑
+//
+// 默认行为：
+//   - 登录成功并返回 DeviceInfo 字段中的设备信息，登录ID从0递增；
+//     注入的 LoginV40 失败只返回其中的锁定信息
+//   - 对未登录（或已登出）的登录ID调用任何接口都返回 NET_DVR_USERNOTEXIST
+//   - 未调用 Init 时登录返回 NET_DVR_NOINIT
+type Fake struct {
+	// DeviceInfo 登录成功时返回的设备信息（锁定相关字段在 LoginV40 失败时也返回）
+	DeviceInfo DeviceInfo
+	// LoginDelay 异步登录返回结果前的延迟（模拟网络耗时）
+	LoginDelay time.Duration
+	// ResolvedIP、ResolvedPort 动态IP解析的返回值
+	ResolvedIP   string
+	ResolvedPort uint32
+
+	mu              sync.Mutex
+	calls           []Call
+	failures        map[string]*fakeFailure
+	lastError       int
+	initialized     bool
+	nextUserID      int
+	nextAlarmHandle int
+	nextListen      int
+	nextRealHandle  int
+	sessions        map[int]bool
+	alarmHandles    map[int]int              // 报警句柄 -> 登录ID
+	listens         map[int]MessageCallback  // 监听句柄 -> 监听回调
+	realPlays       map[int]RealDataCallback // 预览句柄 -> 数据回调
+	configs         map[fakeConfigKey][]byte // GetDVRConfig 的返回数据
+	pictures        map[int][]byte           // 通道号 -> 抓图返回的图片
+	recordFiles     map[int][]FindData       // 通道号 -> 录像文件
+	recordData      map[int][]byte           // 通道号 -> 下载返回的录像数据
+	nextFind        int
+	nextFile        int
+	finds           map[int]*fakeFind         // 查找句柄 -> 查找状态
+	downloads       map[int]*fakeDownload     // 下载句柄 -> 下载状态
+	playbacks       map[int]*fakePlayback     // 回放句柄 -> 回放状态
+	abilities       map[fakeAbilityKey][]byte // GetDeviceAbility 的返回数据
+	isapi           map[string][]byte         // ISAPI请求信令 -> 响应报文
+	isapiErrors     map[string]fakeISAPIError // ISAPI请求信令 -> 注入的失败
+	errorMsgs       map[int]string            // 错误码 -> GetErrorMsg 返回的说明
+	pendingLogins   int                       // 尚未返回结果的异步登录数
+	maxPending      int                       // 同时进行的异步登录数的最大值
+	callback        MessageCallback
+}
+
+// NewFake 创建模拟后端
+func NewFake() *Fake {
+	return &Fake{
+		DeviceInfo: DeviceInfo{
+			SerialNumber: "FAKE0000000000000000",
+			ChanNum:      1,
+			StartChan:    1,
+		},
+		failures:     make(map[string]*fakeFailure),
+		sessions:     make(map[int]bool),
+		alarmHandles: make(map[int]int),
+		listens:      make(map[int]MessageCallback),
+		realPlays:    make(map[int]RealDataCallback),
+		configs:      make(map[fakeConfigKey][]byte),
+		pictures:     make(map[int][]byte),
+		recordFiles:  make(map[int][]FindData),
+		recordData:   make(map[int][]byte),
+		finds:        make(map[int]*fakeFind),
+		downloads:    make(map[int]*fakeDownload),
+		playbacks:    make(map[int]*fakePlayback),
+		abilities:    make(map[fakeAbilityKey][]byte),
+		isapi:        make(map[string][]byte),
+		isapiErrors:  make(map[string]fakeISAPIError),
+		errorMsgs:    make(map[int]string),
+	}
+}
+
+// NewLoggedInFake 创建已初始化并登录了一台模拟设备的模拟后端
+// 返回值：
+//   - *Fake: 模拟后端
+//   - int: 登录ID
+func NewLoggedInFake() (*Fake, int) {
+	f := NewFake()
+	f.Init()
+	return f, f.LoginDevice()
+}
+
+// LoginDevice 登录一台模拟设备（地址 127.0.0.1），返回登录ID，失败时返回-1
+func (f *Fake) LoginDevice() int {
+	return f.LoginV40(&LoginInfo{DeviceAddress: "127.0.0.1"}, nil)
+}
+
+// SetConfig 设置 GetDVRConfig 对指定命令和通道返回的配置数据
+// 未设置的配置返回 NET_DVR_NOSUPPORT
+func (f *Fake) SetConfig(command, channel int, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.configs[fakeConfigKey{command, channel}] = append([]byte(nil), data...)
+}
+
+// SetAbility 设置 GetDeviceAbility 对指定能力类型和输入XML返回的能力集
+// 未设置的能力返回 NET_DVR_NOSUPPORT
+func (f *Fake) SetAbility(abilityType int, in string, xml []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.abilities[fakeAbilityKey{abilityType, in}] = append([]byte(nil), xml...)
+}
+
+// SetISAPI 设置 STDXMLConfig 对指定请求信令（如 "GET /ISAPI/System/capabilities"）返回的响应报文
+// 未设置的请求返回 NET_DVR_NOSUPPORT，响应状态为设备不支持（notSupport）
+func (f *Fake) SetISAPI(request string, response []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.isapi[request] = append([]byte(nil), response...)
+}
+
+// SetISAPIError 设置 STDXMLConfig 对指定请求信令返回的错误码和响应状态（ResponseStatus 报文）
+// 优先于 SetISAPI 设置的响应报文
+func (f *Fake) SetISAPIError(request string, code int, status []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.isapiErrors[request] = fakeISAPIError{code: code, status: append([]byte(nil), status...)}
+}
+
+// SetErrorMsg 设置最后错误为指定错误码时 GetErrorMsg 返回的说明
+// 未设置的错误码返回空字符串
+func (f *Fake) SetErrorMsg(code int, msg string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errorMsgs[code] = msg
+}
+
+// SetPicture 设置指定通道抓图返回的图片数据
+// 未设置的通道抓图返回 NET_DVR_NOSUPPORT
+func (f *Fake) SetPicture(channel int, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pictures[channel] = append([]byte(nil), data...)
+}
+
+// SetRecordFiles 设置指定通道的录像文件，FindFileV40 返回与查找时间段有交集的文件
+func (f *Fake) SetRecordFiles(channel int, files []FindData) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recordFiles[channel] = append([]FindData(nil), files...)
+}
+
+// SetRecordData 设置指定通道按时间下载返回的录像数据
+// 每次 GetDownloadPos 向文件追加四分之一的数据；未设置的通道下载返回 NET_DVR_NOSUPPORT
+func (f *Fake) SetRecordData(channel int, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recordData[channel] = append([]byte(nil), data...)
+}
+
+// FailWith 使指定方法此后的每次调用都以错误码 code 失败
+func (f *Fake) FailWith(method string, code int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[method] = &fakeFailure{code: code, times: -1}
+}
+
+// FailOnce 使指定方法的下一次调用以错误码 code 失败
+func (f *Fake) FailOnce(method string, code int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[method] = &fakeFailure{code: code, times: 1}
+}
+
+// ClearFailures 清除所有注入的失败规则
+func (f *Fake) ClearFailures() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = make(map[string]*fakeFailure)
+}
+
+// Calls 返回所有已记录调用的副本
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsTo 返回指定方法的已记录调用
+func (f *Fake) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []Call
+	for _, c := range f.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// ResetCalls 清空已记录的调用
+func (f *Fake) ResetCalls() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// DropSession 模拟会话失效（如设备重启、密码被修改）
+// 之后使用该登录ID的调用都返回 NET_DVR_USERNOTEXIST
+func (f *Fake) DropSession(userID int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.sessions, userID)
+}
+
+// Emit 模拟设备上传一条报警消息
+// 同步调用已注册的报警回调，未注册回调时返回false
+func (f *Fake) Emit(command int, alarmer *Alarmer, info []byte) bool {
+	f.mu.Lock()
+	callback := f.callback
+	f.mu.Unlock()
+
+	if callback == nil {
+		return false
+	}
+	callback(command, alarmer, info)
+	return true
+}
+
+// EmitListen 模拟设备向报警监听端口主动上传报警
+// 返回false表示监听句柄不存在
+func (f *Fake) EmitListen(listenHandle, command int, alarmer *Alarmer, info []byte) bool {
+	f.mu.Lock()
+	callback := f.listens[listenHandle]
+	f.mu.Unlock()
+
+	if callback == nil {
+		return false
+	}
+	callback(command, alarmer, info)
+	return true
+}
+
+// EmitRealData 模拟设备推送一包预览数据
+// 同步调用预览的数据回调，返回false表示预览句柄不存在
+func (f *Fake) EmitRealData(realHandle, dataType int, data []byte) bool {
+	f.mu.Lock()
+	callback := f.realPlays[realHandle]
+	f.mu.Unlock()
+
+	if callback == nil {
+		return false
+	}
+	callback(realHandle, dataType, append([]byte(nil), data...))
+	return true
+}
+
+// EmitPlayData 模拟设备推送一包回放数据
+// 同步调用回放的数据回调，返回false表示回放句柄不存在或未注册回调
+func (f *Fake) EmitPlayData(playHandle, dataType int, data []byte) bool {
+	f.mu.Lock()
+	var callback RealDataCallback
+	if playback, ok := f.playbacks[playHandle]; ok {
+		callback = playback.callback
+	}
+	f.mu.Unlock()
+
+	if callback == nil {
+		return false
+	}
+	callback(playHandle, dataType, append([]byte(nil), data...))
+	return true
+}
+
+// PlayBackState 返回回放的暂停状态和速度档位（0-正常，>0 快放，<0 慢放）
+// 回放句柄不存在时 ok 为false
+func (f *Fake) PlayBackState(playHandle int) (paused bool, speed int, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	playback, ok := f.playbacks[playHandle]
+	if !ok {
+		return false, 0, false
+	}
+	return playback.paused, playback.speed, true
+}
+
+// begin 记录调用并检查注入的失败规则
+// 调用方必须持有锁；返回false表示本次调用应失败（错误码已设置）
+func (f *Fake) begin(method string, args ...any) bool {
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+
+	if failure, ok := f.failures[method]; ok {
+		if failure.times > 0 {
+			failure.times--
+			if failure.times == 0 {
+				delete(f.failures, method)
+			}
+		}
+		f.lastError = failure.code
+		return false
+	}
+
+	f.lastError = NET_DVR_NOERROR
+	return true
+}
+
+// fail 设置错误码并返回false
+func (f *Fake) fail(code int) bool {
+	f.lastError = code
+	return false
+}
+
+// checkSession 检查登录ID是否有效
+func (f *Fake) checkSession(userID int) bool {
+	if !f.sessions[userID] {
+		return f.fail(NET_DVR_USERNOTEXIST)
+	}
+	return true
+}
+
+// login 分配新的登录ID
+func (f *Fake) login(deviceInfo *DeviceInfo) int {
+	if !f.initialized {
+		f.fail(NET_DVR_NOINIT)
+		return -1
+	}
+
+	userID := f.nextUserID
+	f.nextUserID++
+	f.sessions[userID] = true
+
+	if deviceInfo != nil {
+		*deviceInfo = f.DeviceInfo
+	}
+	return userID
+}
+
+func (f *Fake) Init() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("Init") {
+		return false
+	}
+	f.initialized = true
+	return true
+}
+
+func (f *Fake) Cleanup() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("Cleanup") {
+		return false
+	}
+	f.initialized = false
+	f.sessions = make(map[int]bool)
+	f.alarmHandles = make(map[int]int)
+	f.listens = make(map[int]MessageCallback)
+	f.realPlays = make(map[int]RealDataCallback)
+	f.finds = make(map[int]*fakeFind)
+	f.downloads = make(map[int]*fakeDownload)
+	f.playbacks = make(map[int]*fakePlayback)
+	return true
+}
+
+func (f *Fake) SetConnectTime(waitTime, tryTimes uint32) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("SetConnectTime", waitTime, tryTimes)
+}
+
+func (f *Fake) SetReconnect(interval uint32, enable bool) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("SetReconnect", interval, enable)
+}
+
+func (f *Fake) SetLogToFile(level int, logDir string, autoDelete bool) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("SetLogToFile", level, logDir, autoDelete)
+}
+
+func (f *Fake) SetRecvTimeOut(timeout uint32) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("SetRecvTimeOut", timeout)
+}
+
+func (f *Fake) SetSDKInitCfg(cfgType int, path string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("SetSDKInitCfg", cfgType, path)
+}
+
+func (f *Fake) LoginV40(loginInfo *LoginInfo, deviceInfo *DeviceInfo) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("LoginV40", *loginInfo) {
+		// 与SDK一致，登录失败时返回 DeviceInfo 中的锁定信息
+		if deviceInfo != nil {
+			deviceInfo.SupportLock = f.DeviceInfo.SupportLock
+			deviceInfo.RetryLoginTime = f.DeviceInfo.RetryLoginTime
+			deviceInfo.SurplusLockTime = f.DeviceInfo.SurplusLockTime
+		}
+		return -1
+	}
+	return f.login(deviceInfo)
+}
+
+// LoginV40Async 在新的goroutine中延迟 LoginDelay 后回调登录结果
+// 注入的失败通过回调返回（模拟设备返回的登录失败）；未调用 Init 时直接返回false
+func (f *Fake) LoginV40Async(loginInfo *LoginInfo, callback LoginResultCallback) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	code := NET_DVR_NOERROR
+	if !f.begin("LoginV40Async", *loginInfo) {
+		code = f.lastError
+	} else if !f.initialized {
+		return f.fail(NET_DVR_NOINIT)
+	}
+
+	userID := -1
+	var deviceInfo DeviceInfo
+	if code == NET_DVR_NOERROR {
+		userID = f.login(&deviceInfo)
+	}
+	f.pendingLogins++
+	f.maxPending = max(f.maxPending, f.pendingLogins)
+
+	delay := f.LoginDelay
+	go func() {
+		time.Sleep(delay)
+		f.mu.Lock()
+		f.pendingLogins--
+		f.mu.Unlock()
+		if code != NET_DVR_NOERROR {
+			callback(-1, nil, code)
+			return
+		}
+		callback(userID, &deviceInfo, NET_DVR_NOERROR)
+	}()
+	return true
+}
+
+// MaxPendingLogins 返回同时进行的异步登录数的最大值
+func (f *Fake) MaxPendingLogins() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.maxPending
+}
+
+func (f *Fake) LoginV30(ip string, port int, username, password string, deviceInfo *DeviceInfo) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("LoginV30", ip, port, username, password) {
+		return -1
+	}
+	return f.login(deviceInfo)
+}
+
+func (f *Fake) Logout(userID int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("Logout", userID) || !f.checkSession(userID) {
+		return false
+	}
+	delete(f.sessions, userID)
+	return true
+}
+
+func (f *Fake) CheckUserStatus(userID int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("CheckUserStatus", userID) && f.checkSession(userID)
+}
+
+func (f *Fake) GetDVRIPByResolveSvrEx(serverIP string, serverPort uint16, dvrName, serialNumber string) (string, uint32, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("GetDVRIPByResolveSvrEx", serverIP, serverPort, dvrName, serialNumber) {
+		return "", 0, false
+	}
+	return f.ResolvedIP, f.ResolvedPort, true
+}
+
+func (f *Fake) GetDVRConfig(userID, command, channel int, buf []byte) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("GetDVRConfig", userID, command, channel) || !f.checkSession(userID) {
+		return 0, false
+	}
+	data, ok := f.configs[fakeConfigKey{command, channel}]
+	if !ok {
+		return 0, f.fail(NET_DVR_NOSUPPORT)
+	}
+	return copy(buf, data), true
+}
+
+// SetDVRConfig 记录的参数中包含配置数据的拷贝
+func (f *Fake) SetDVRConfig(userID, command, channel int, buf []byte) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("SetDVRConfig", userID, command, channel, append([]byte(nil), buf...)) {
+		return false
+	}
+	return f.checkSession(userID)
+}
+
+func (f *Fake) SetDVRMessageCallBackV30(callback MessageCallback) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("SetDVRMessageCallBackV30") {
+		return false
+	}
+	f.callback = callback
+	return true
+}
+
+func (f *Fake) SetupAlarmChanV41(userID int, param *AlarmSetupParam) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("SetupAlarmChanV41", userID, *param) || !f.checkSession(userID) {
+		return -1
+	}
+	handle := f.nextAlarmHandle
+	f.nextAlarmHandle++
+	f.alarmHandles[handle] = userID
+	return handle
+}
+
+func (f *Fake) CloseAlarmChanV30(alarmHandle int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("CloseAlarmChanV30", alarmHandle) {
+		return false
+	}
+	if _, ok := f.alarmHandles[alarmHandle]; !ok {
+		return f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	delete(f.alarmHandles, alarmHandle)
+	return true
+}
+
+func (f *Fake) StartListenV30(localIP string, localPort uint16, callback MessageCallback) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("StartListenV30", localIP, localPort) {
+		return -1
+	}
+	if !f.initialized {
+		f.fail(NET_DVR_NOINIT)
+		return -1
+	}
+	handle := f.nextListen
+	f.nextListen++
+	f.listens[handle] = callback
+	return handle
+}
+
+func (f *Fake) StopListenV30(listenHandle int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("StopListenV30", listenHandle) {
+		return false
+	}
+	if _, ok := f.listens[listenHandle]; !ok {
+		return f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	delete(f.listens, listenHandle)
+	return true
+}
+
+func (f *Fake) RealPlayV40(userID int, info *PreviewInfo, callback RealDataCallback) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("RealPlayV40", userID, *info) || !f.checkSession(userID) {
+		return -1
+	}
+	handle := f.nextRealHandle
+	f.nextRealHandle++
+	f.realPlays[handle] = callback
+	return handle
+}
+
+func (f *Fake) StopRealPlay(realHandle int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("StopRealPlay", realHandle) {
+		return false
+	}
+	if _, ok := f.realPlays[realHandle]; !ok {
+		return f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	delete(f.realPlays, realHandle)
+	return true
+}
+
+func (f *Fake) PTZControlWithSpeedOther(userID, channel, command, stop, speed int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("PTZControlWithSpeedOther", userID, channel, command, stop, speed) && f.checkSession(userID)
+}
+
+func (f *Fake) PTZPresetOther(userID, channel, command, presetIndex int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("PTZPresetOther", userID, channel, command, presetIndex) && f.checkSession(userID)
+}
+
+func (f *Fake) PTZCruiseOther(userID, channel, command, route, point, input int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("PTZCruiseOther", userID, channel, command, route, point, input) && f.checkSession(userID)
+}
+
+func (f *Fake) PTZTrackOther(userID, channel, command int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("PTZTrackOther", userID, channel, command) && f.checkSession(userID)
+}
+
+func (f *Fake) PTZSelZoomInEx(userID, channel int, frame *PointFrame) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begin("PTZSelZoomInEx", userID, channel, *frame) && f.checkSession(userID)
+}
+
+// CaptureJPEGPicture 将图片写入 fileName
+func (f *Fake) CaptureJPEGPicture(userID, channel int, para *JPEGPara, fileName string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("CaptureJPEGPicture", userID, channel, *para, fileName) || !f.checkSession(userID) {
+		return false
+	}
+	data, ok := f.pictures[channel]
+	if !ok {
+		return f.fail(NET_DVR_NOSUPPORT)
+	}
+	if err := os.WriteFile(fileName, data, 0o644); err != nil {
+		return f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	return true
+}
+
+// CaptureJPEGPictureNew 记录的参数中包含缓冲区大小
+func (f *Fake) CaptureJPEGPictureNew(userID, channel int, para *JPEGPara, buf []byte) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("CaptureJPEGPictureNew", userID, channel, *para, len(buf)) || !f.checkSession(userID) {
+		return 0, false
+	}
+	data, ok := f.pictures[channel]
+	if !ok {
+		return 0, f.fail(NET_DVR_NOSUPPORT)
+	}
+	if len(buf) < len(data) {
+		return 0, f.fail(NET_DVR_NOENOUGH_BUF)
+	}
+	return copy(buf, data), true
+}
+
+// FindFileV40 第一次 FindNextFileV40 返回 NET_DVR_ISFINDING，之后逐个返回文件
+func (f *Fake) FindFileV40(userID int, cond *FileCond) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("FindFileV40", userID, *cond) || !f.checkSession(userID) {
+		return -1
+	}
+	find := &fakeFind{waiting: true}
+	for _, file := range f.recordFiles[cond.Channel] {
+		if file.Start.Before(cond.Stop) && file.Stop.After(cond.Start) &&
+			(cond.FileType == 0xff || cond.FileType == file.FileType) {
+			find.files = append(find.files, file)
+		}
+	}
+	handle := f.nextFind
+	f.nextFind++
+	f.finds[handle] = find
+	return handle
+}
+
+func (f *Fake) FindNextFileV40(findHandle int, data *FindData) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("FindNextFileV40", findHandle) {
+		return -1
+	}
+	find, ok := f.finds[findHandle]
+	switch {
+	case !ok:
+		f.fail(NET_DVR_PARAMETER_ERROR)
+		return -1
+	case find.waiting:
+		find.waiting = false
+		return NET_DVR_ISFINDING
+	case len(find.files) == 0:
+		return NET_DVR_FILE_NOFIND
+	case find.next >= len(find.files):
+		return NET_DVR_NOMOREFILE
+	}
+	*data = find.files[find.next]
+	find.next++
+	return NET_DVR_FILE_SUCCESS
+}
+
+func (f *Fake) FindCloseV30(findHandle int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("FindCloseV30", findHandle) {
+		return false
+	}
+	if _, ok := f.finds[findHandle]; !ok {
+		return f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	delete(f.finds, findHandle)
+	return true
+}
+
+// GetFileByTimeV40 创建（清空）保存文件，开始下载后由 GetDownloadPos 逐步写入
+func (f *Fake) GetFileByTimeV40(userID int, fileName string, cond *PlayCond) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("GetFileByTimeV40", userID, fileName, *cond) || !f.checkSession(userID) {
+		return -1
+	}
+	data, ok := f.recordData[cond.Channel]
+	if !ok {
+		f.fail(NET_DVR_NOSUPPORT)
+		return -1
+	}
+	if err := os.WriteFile(fileName, nil, 0o644); err != nil {
+		f.fail(NET_DVR_PARAMETER_ERROR)
+		return -1
+	}
+	handle := f.nextFile
+	f.nextFile++
+	f.downloads[handle] = &fakeDownload{fileName: fileName, data: data}
+	return handle
+}
+
+// PlayBackByTimeV40 回放句柄与下载句柄共用编号；OSD时间从回放开始时间起
+func (f *Fake) PlayBackByTimeV40(userID int, cond *PlayCond) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("PlayBackByTimeV40", userID, *cond) || !f.checkSession(userID) {
+		return -1
+	}
+	handle := f.nextFile
+	f.nextFile++
+	f.playbacks[handle] = &fakePlayback{cond: *cond, osdTime: cond.Start}
+	return handle
+}
+
+func (f *Fake) SetPlayDataCallBackV40(playHandle int, callback RealDataCallback) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("SetPlayDataCallBackV40", playHandle) {
+		return false
+	}
+	playback, ok := f.playbacks[playHandle]
+	if !ok {
+		return f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	playback.callback = callback
+	return true
+}
+
+func (f *Fake) GetPlayBackOsdTime(playHandle int) (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("GetPlayBackOsdTime", playHandle) {
+		return time.Time{}, false
+	}
+	playback, ok := f.playbacks[playHandle]
+	if !ok {
+		return time.Time{}, f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	return playback.osdTime, true
+}
+
+func (f *Fake) StopPlayBack(playHandle int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("StopPlayBack", playHandle) {
+		return false
+	}
+	if _, ok := f.playbacks[playHandle]; !ok {
+		return f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	delete(f.playbacks, playHandle)
+	return true
+}
+
+// PlayBackControlV40 记录的参数中包含输入参数的拷贝
+func (f *Fake) PlayBackControlV40(playHandle, command int, in, out []byte) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("PlayBackControlV40", playHandle, command, append([]byte(nil), in...)) {
+		return 0, false
+	}
+	if download, ok := f.downloads[playHandle]; ok {
+		if command == NET_DVR_PLAYSTART {
+			download.started = true
+		}
+		return 0, true
+	}
+	playback, ok := f.playbacks[playHandle]
+	if !ok {
+		return 0, f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	switch command {
+	case NET_DVR_PLAYSTART:
+		playback.started = true
+	case NET_DVR_PLAYPAUSE:
+		playback.paused = true
+	case NET_DVR_PLAYRESTART:
+		playback.paused = false
+	case NET_DVR_PLAYFAST:
+		playback.speed = min(playback.speed+1, 4)
+	case NET_DVR_PLAYSLOW:
+		playback.speed = max(playback.speed-1, -4)
+	case NET_DVR_PLAYNORMAL:
+		playback.speed = 0
+	case NET_DVR_PLAYSETTIME:
+		if len(in) < TimeSize {
+			return 0, f.fail(NET_DVR_PARAMETER_ERROR)
+		}
+		playback.osdTime = DecodeTime(in)
+	case NET_DVR_PLAYGETPOS:
+		// 按OSD时间在回放时间段中的位置计算进度
+		if len(out) < 4 {
+			return 0, f.fail(NET_DVR_NOENOUGH_BUF)
+		}
+		total := playback.cond.Stop.Sub(playback.cond.Start)
+		pos := int(playback.osdTime.Sub(playback.cond.Start) * 100 / total)
+		binary.LittleEndian.PutUint32(out, uint32(pos))
+		return 4, true
+	}
+	return 0, true
+}
+
+func (f *Fake) GetDownloadPos(fileHandle int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("GetDownloadPos", fileHandle) {
+		return -1
+	}
+	download, ok := f.downloads[fileHandle]
+	if !ok {
+		f.fail(NET_DVR_PARAMETER_ERROR)
+		return -1
+	}
+	if len(download.data) == 0 {
+		return 100
+	}
+	if !download.started {
+		return 0
+	}
+	if download.written < len(download.data) {
+		n := min(len(download.data)-download.written, (len(download.data)+3)/4)
+		file, err := os.OpenFile(download.fileName, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return 200
+		}
+		defer file.Close()
+		if _, err := file.Write(download.data[download.written : download.written+n]); err != nil {
+			return 200
+		}
+		download.written += n
+	}
+	return download.written * 100 / len(download.data)
+}
+
+func (f *Fake) StopGetFile(fileHandle int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("StopGetFile", fileHandle) {
+		return false
+	}
+	if _, ok := f.downloads[fileHandle]; !ok {
+		return f.fail(NET_DVR_PARAMETER_ERROR)
+	}
+	delete(f.downloads, fileHandle)
+	return true
+}
+
+// GetDeviceAbility 记录的参数中包含输入XML和输出缓冲区大小
+func (f *Fake) GetDeviceAbility(userID, abilityType int, in, out []byte) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("GetDeviceAbility", userID, abilityType, string(in), len(out)) || !f.checkSession(userID) {
+		return 0, false
+	}
+	data, ok := f.abilities[fakeAbilityKey{abilityType, string(in)}]
+	if !ok {
+		return 0, f.fail(NET_DVR_NOSUPPORT)
+	}
+	if len(out) < len(data) {
+		return 0, f.fail(NET_DVR_NOENOUGH_BUF)
+	}
+	return copy(out, data), true
+}
+
+// fakeNotSupportStatus 设备不支持ISAPI请求时返回的响应状态
+const fakeNotSupportStatus = `<?xml version="1.0" encoding="UTF-8"?>
+<ResponseStatus version="2.0" xmlns="http://www.isapi.org/ver20/XMLSchema">
+<statusCode>4</statusCode>
+<statusString>Invalid Operation</statusString>
+<subStatusCode>notSupport</subStatusCode>
+</ResponseStatus>`
+
+// STDXMLConfig 记录的参数中包含请求报文和输出缓冲区大小
+func (f *Fake) STDXMLConfig(userID int, request string, in, out, status []byte) (int, int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.begin("STDXMLConfig", userID, request, string(in), len(out)) || !f.checkSession(userID) {
+		return 0, 0, false
+	}
+	if e, ok := f.isapiErrors[request]; ok {
+		return 0, copy(status, e.status), f.fail(e.code)
+	}
+	data, ok := f.isapi[request]
+	if !ok {
+		return 0, copy(status, fakeNotSupportStatus), f.fail(NET_DVR_NOSUPPORT)
+	}
+	if len(out) < len(data) {
+		return 0, 0, f.fail(NET_DVR_NOENOUGH_BUF)
+	}
+	return copy(out, data), 0, true
+}
+
+func (f *Fake) GetLastError() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastError
+}
+
+func (f *Fake) GetErrorMsg() (int, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastError, f.errorMsgs[f.lastError]
+}
@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFakeLoginAndFailures 模拟后端的登录与错误注入
+func TestFakeLoginAndFailures(t *testing.T) {
+	f := NewFake()
+
+	// 未初始化时登录失败
+	if id := f.LoginV40(&LoginInfo{DeviceAddress: "10.0.0.1"}, nil); id >= 0 {
+		t.Fatalf("未初始化时登录应失败，实际登录ID: %d", id)
+	}
+	if code := f.GetLastError(); code != NET_DVR_NOINIT {
+		t.Fatalf("错误码应为 %d，实际: %d", NET_DVR_NOINIT, code)
+	}
+
+	f.Init()
+	var info DeviceInfo
+	id := f.LoginV40(&LoginInfo{DeviceAddress: "10.0.0.1"}, &info)
+	if id < 0 {
+		t.Fatalf("登录失败，错误码: %d", f.GetLastError())
+	}
+	if info.SerialNumber != f.DeviceInfo.SerialNumber {
+		t.Errorf("设备序列号不匹配: %q", info.SerialNumber)
+	}
+
+	// 单次失败只生效一次
+	f.FailOnce("PTZPresetOther", NET_DVR_NOSUPPORT)
+	if f.PTZPresetOther(id, 1, 8, 1) {
+		t.Fatal("注入失败后调用应失败")
+	}
+	if code := f.GetLastError(); code != NET_DVR_NOSUPPORT {
+		t.Errorf("错误码应为 %d，实际: %d", NET_DVR_NOSUPPORT, code)
+	}
+	if !f.PTZPresetOther(id, 1, 8, 1) {
+		t.Errorf("第二次调用应成功，错误码: %d", f.GetLastError())
+	}
+
+	// 登出后的登录ID不可再用
+	f.Logout(id)
+	if f.PTZTrackOther(id, 1, 36) {
+		t.Fatal("登出后调用应失败")
+	}
+	if code := f.GetLastError(); code != NET_DVR_USERNOTEXIST {
+		t.Errorf("错误码应为 %d，实际: %d", NET_DVR_USERNOTEXIST, code)
+	}
+
+	if n := len(f.CallsTo("PTZPresetOther")); n != 2 {
+		t.Errorf("应记录2次预置点调用，实际: %d", n)
+	}
+}
+
+// TestEncodeTime NET_DVR_TIME 编解码
+func TestEncodeTime(t *testing.T) {
+	want := time.Date(2024, 12, 31, 23, 59, 58, 0, time.Local)
+	data := EncodeTime(want)
+	if len(data) != TimeSize || data[0] != 0xE8 || data[1] != 0x07 {
+		t.Fatalf("编码结果错误: % X", data)
+	}
+	if got := DecodeTime(data); !got.Equal(want) {
+		t.Errorf("解码结果错误: %v", got)
+	}
+}
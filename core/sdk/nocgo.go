@@ -0,0 +1,189 @@
+//go:build !cgo
+
+package sdk
+
+import "time"
+
+// unavailableBackend 未启用CGO时的占位后端
+// 所有调用均失败，GetLastError 返回 NET_DVR_LOADLIBRARY_ERROR
+type unavailableBackend struct{}
+
+func newDefaultBackend() Backend {
+	return unavailableBackend{}
+}
+
+func (unavailableBackend) Init() bool {
+	return false
+}
+
+func (unavailableBackend) Cleanup() bool {
+	return false
+}
+
+func (unavailableBackend) SetConnectTime(waitTime, tryTimes uint32) bool {
+	return false
+}
+
+func (unavailableBackend) SetReconnect(interval uint32, enable bool) bool {
+	return false
+}
+
+func (unavailableBackend) SetLogToFile(level int, logDir string, autoDelete bool) bool {
+	return false
+}
+
+func (unavailableBackend) SetRecvTimeOut(timeout uint32) bool {
+	return false
+}
+
+func (unavailableBackend) SetSDKInitCfg(cfgType int, path string) bool {
+	return false
+}
+
+func (unavailableBackend) LoginV40(loginInfo *LoginInfo, deviceInfo *DeviceInfo) int {
+	return -1
+}
+
+func (unavailableBackend) LoginV40Async(loginInfo *LoginInfo, callback LoginResultCallback) bool {
+	return false
+}
+
+func (unavailableBackend) LoginV30(ip string, port int, username, password string, deviceInfo *DeviceInfo) int {
+	return -1
+}
+
+func (unavailableBackend) Logout(userID int) bool {
+	return false
+}
+
+func (unavailableBackend) CheckUserStatus(userID int) bool {
+	return false
+}
+
+func (unavailableBackend) GetDVRIPByResolveSvrEx(serverIP string, serverPort uint16, dvrName, serialNumber string) (string, uint32, bool) {
+	return "", 0, false
+}
+
+func (unavailableBackend) GetDVRConfig(userID, command, channel int, buf []byte) (int, bool) {
+	return 0, false
+}
+
+func (unavailableBackend) SetDVRConfig(userID, command, channel int, buf []byte) bool {
+	return false
+}
+
+func (unavailableBackend) SetDVRMessageCallBackV30(callback MessageCallback) bool {
+	return false
+}
+
+func (unavailableBackend) SetupAlarmChanV41(userID int, param *AlarmSetupParam) int {
+	return -1
+}
+
+func (unavailableBackend) CloseAlarmChanV30(alarmHandle int) bool {
+	return false
+}
+
+func (unavailableBackend) StartListenV30(localIP string, localPort uint16, callback MessageCallback) int {
+	return -1
+}
+
+func (unavailableBackend) StopListenV30(listenHandle int) bool {
+	return false
+}
+
+func (unavailableBackend) RealPlayV40(userID int, info *PreviewInfo, callback RealDataCallback) int {
+	return -1
+}
+
+func (unavailableBackend) StopRealPlay(realHandle int) bool {
+	return false
+}
+
+func (unavailableBackend) PTZControlWithSpeedOther(userID, channel, command, stop, speed int) bool {
+	return false
+}
+
+func (unavailableBackend) PTZPresetOther(userID, channel, command, presetIndex int) bool {
+	return false
+}
+
+func (unavailableBackend) PTZCruiseOther(userID, channel, command, route, point, input int) bool {
+	return false
+}
+
+func (unavailableBackend) PTZTrackOther(userID, channel, command int) bool {
+	return false
+}
+
+func (unavailableBackend) PTZSelZoomInEx(userID, channel int, frame *PointFrame) bool {
+	return false
+}
+
+func (unavailableBackend) CaptureJPEGPicture(userID, channel int, para *JPEGPara, fileName string) bool {
+	return false
+}
+
+func (unavailableBackend) CaptureJPEGPictureNew(userID, channel int, para *JPEGPara, buf []byte) (int, bool) {
+	return 0, false
+}
+
+func (unavailableBackend) FindFileV40(userID int, cond *FileCond) int {
+	return -1
+}
+
+func (unavailableBackend) FindNextFileV40(findHandle int, data *FindData) int {
+	return -1
+}
+
+func (unavailableBackend) FindCloseV30(findHandle int) bool {
+	return false
+}
+
+func (unavailableBackend) GetFileByTimeV40(userID int, fileName string, cond *PlayCond) int {
+	return -1
+}
+
+func (unavailableBackend) PlayBackControlV40(playHandle, command int, in, out []byte) (int, bool) {
+	return 0, false
+}
+
+func (unavailableBackend) GetDownloadPos(fileHandle int) int {
+	return -1
+}
+
+func (unavailableBackend) StopGetFile(fileHandle int) bool {
+	return false
+}
+
+func (unavailableBackend) PlayBackByTimeV40(userID int, cond *PlayCond) int {
+	return -1
+}
+
+func (unavailableBackend) SetPlayDataCallBackV40(playHandle int, callback RealDataCallback) bool {
+	return false
+}
+
+func (unavailableBackend) GetPlayBackOsdTime(playHandle int) (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (unavailableBackend) StopPlayBack(playHandle int) bool {
+	return false
+}
+
+func (unavailableBackend) GetDeviceAbility(userID, abilityType int, in, out []byte) (int, bool) {
+	return 0, false
+}
+
+func (unavailableBackend) STDXMLConfig(userID int, request string, in, out, status []byte) (int, int, bool) {
+	return 0, 0, false
+}
+
+func (unavailableBackend) GetLastError() int {
+	return NET_DVR_LOADLIBRARY_ERROR
+}
+
+func (unavailableBackend) GetErrorMsg() (int, string) {
+	return NET_DVR_LOADLIBRARY_ERROR, ""
+}
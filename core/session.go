@@ -0,0 +1,67 @@
+package core
+
+import (
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+)
+
+// LoginHandle 可更新的登录句柄
+// 多个控制器共享同一个 LoginHandle，重新登录后只需更新一次，
+// 所有控制器即可透明地使用新的登录ID
+type LoginHandle struct {
+	id     atomic.Int64
+	logger atomic.Pointer[slog.Logger] // 设备日志记录器，未设置时使用全局日志记录器
+	device atomic.Pointer[string]      // 设备标识（用于指标），未设置时使用登录ID
+}
+
+// NewLoginHandle 创建登录句柄
+// 参数：
+//   - loginID: 登录ID（NET_DVR_Login_V40 的返回值）
+func NewLoginHandle(loginID int) *LoginHandle {
+	h := &LoginHandle{}
+	h.id.Store(int64(loginID))
+	return h
+}
+
+// ID 获取当前登录ID
+func (h *LoginHandle) ID() int {
+	return int(h.id.Load())
+}
+
+// Set 更新登录ID
+func (h *LoginHandle) Set(loginID int) {
+	h.id.Store(int64(loginID))
+}
+
+// SetLogger 设置该设备的日志记录器
+// 共享该句柄的所有控制器都使用此记录器
+// 参数：
+//   - logger: 日志记录器，nil表示使用全局日志记录器（见 SetLogger）
+func (h *LoginHandle) SetLogger(logger *slog.Logger) {
+	h.logger.Store(logger)
+}
+
+// Logger 获取日志记录器，日志附带当前登录ID（login_id）
+func (h *LoginHandle) Logger() *slog.Logger {
+	l := h.logger.Load()
+	if l == nil {
+		l = Logger()
+	}
+	return l.With(slog.Int("login_id", h.ID()))
+}
+
+// SetDevice 设置设备标识（如 IP:端口），用作指标的 device 标签
+// 参数：
+//   - device: 设备标识
+func (h *LoginHandle) SetDevice(device string) {
+	h.device.Store(&device)
+}
+
+// Device 获取设备标识，未设置时返回 "login:<登录ID>"
+func (h *LoginHandle) Device() string {
+	if d := h.device.Load(); d != nil {
+		return *d
+	}
+	return "login:" + strconv.Itoa(h.ID())
+}
@@ -0,0 +1,171 @@
+// Package snapshot 设备抓图
+//
+// 通过 NET_DVR_CaptureJPEGPicture_NEW 让设备对指定通道抓取一张 JPEG 图片并返回图片数据，
+// 设备不支持内存抓图时退回到 NET_DVR_CaptureJPEGPicture（经由临时文件）
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// 图片质量（NET_DVR_JPEGPARA.wPicQuality）
+const (
+	QualityBest   = 0 // 最好
+	QualityBetter = 1 // 较好
+	QualityNormal = 2 // 一般
+)
+
+// 图片尺寸（NET_DVR_JPEGPARA.wPicSize，常用取值）
+// 设备只支持部分尺寸，不支持时抓图失败；不确定时使用 SizeAuto
+const (
+	SizeCIF    = 0    // CIF（352x288）
+	SizeQCIF   = 1    // QCIF（176x144）
+	SizeD1     = 2    // D1（704x576）
+	SizeUXGA   = 3    // UXGA（1600x1200）
+	SizeSVGA   = 4    // SVGA（800x600）
+	SizeHD720P = 5    // HD720P（1280x720）
+	SizeVGA    = 6    // VGA（640x480）
+	SizeXVGA   = 7    // XVGA（1280x960）
+	SizeHD900P = 8    // HD900P（1600x900）
+	SizeHD1080 = 9    // HD1080（1920x1080）
+	SizeAuto   = 0xFF // 使用当前码流分辨率
+)
+
+// 抓图缓冲区大小
+const (
+	// DefaultBufferSize 初始缓冲区大小，不足时自动加倍
+	DefaultBufferSize = 512 * 1024
+	// MaxBufferSize 缓冲区上限
+	MaxBufferSize = 16 * 1024 * 1024
+)
+
+// jpegSOI JPEG 文件头
+var jpegSOI = []byte{0xFF, 0xD8}
+
+// Capturer 设备抓图
+// 记住上次成功的缓冲区大小，避免每次都从初始大小重试
+type Capturer struct {
+	mu      sync.Mutex
+	backend sdk.Backend       // SDK后端
+	login   *core.LoginHandle // 登录句柄
+	bufSize int               // 当前缓冲区大小
+}
+
+// NewCapturer 创建设备抓图（使用默认SDK后端）
+// 参数：
+//   - userID: 登录句柄
+func NewCapturer(userID int) *Capturer {
+	return NewCapturerWithBackend(sdk.Default(), userID)
+}
+
+// NewCapturerWithBackend 使用指定SDK后端创建设备抓图
+// 参数：
+//   - backend: SDK后端（测试时可传入 sdk.NewFake()）
+//   - userID: 登录句柄
+func NewCapturerWithBackend(backend sdk.Backend, userID int) *Capturer {
+	return NewCapturerWithHandle(backend, core.NewLoginHandle(userID))
+}
+
+// NewCapturerWithHandle 使用共享登录句柄创建设备抓图
+// 重新登录后更新句柄即可继续使用
+// 参数：
+//   - backend: SDK后端
+//   - login: 登录句柄
+func NewCapturerWithHandle(backend sdk.Backend, login *core.LoginHandle) *Capturer {
+	return &Capturer{
+		backend: backend,
+		login:   login,
+		bufSize: DefaultBufferSize,
+	}
+}
+
+// Capture 抓取指定通道的 JPEG 图片
+// 参数：
+//   - channel: 通道号
+//   - quality: 图片质量（QualityBest、QualityBetter、QualityNormal）
+//   - resolution: 图片尺寸（SizeCIF ... SizeHD1080，SizeAuto 表示当前码流分辨率）
+//
+// 返回值：
+//   - []byte: JPEG 图片数据
+//   - error: 错误信息，成功时为nil
+func (c *Capturer) Capture(channel, quality, resolution int) ([]byte, error) {
+	if quality < QualityBest || quality > QualityNormal {
+		return nil, fmt.Errorf("无效的图片质量: %d", quality)
+	}
+	if resolution < 0 || resolution > SizeAuto {
+		return nil, fmt.Errorf("无效的图片尺寸: %d", resolution)
+	}
+	userID := c.login.ID()
+	if userID < 0 {
+		return nil, fmt.Errorf("无效的登录ID：%d", userID)
+	}
+
+	para := &sdk.JPEGPara{PicSize: resolution, PicQuality: quality}
+	data, err := c.captureToBuffer(userID, channel, para)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, jpegSOI) {
+		return nil, fmt.Errorf("抓图[通道:%d]返回的数据不是 JPEG 图片（%d字节）", channel, len(data))
+	}
+
+	c.login.Logger().Debug("抓图成功", "channel", channel, "bytes", len(data))
+	return data, nil
+}
+
+// captureToBuffer 抓图到内存，缓冲区不足时加倍重试，设备不支持时改用文件方式
+func (c *Capturer) captureToBuffer(userID, channel int, para *sdk.JPEGPara) ([]byte, error) {
+	c.mu.Lock()
+	size := c.bufSize
+	c.mu.Unlock()
+
+	operation := fmt.Sprintf("抓图[通道:%d]", channel)
+	for {
+		buf := make([]byte, size)
+		n, ok := c.backend.CaptureJPEGPictureNew(userID, channel, para, buf)
+		if ok {
+			c.mu.Lock()
+			c.bufSize = max(c.bufSize, size)
+			c.mu.Unlock()
+			return buf[:n], nil
+		}
+
+		switch c.backend.GetLastError() {
+		case sdk.NET_DVR_NOENOUGH_BUF:
+			if size >= MaxBufferSize {
+				return nil, core.NewHKErrorFrom(c.backend, operation)
+			}
+			size = min(size*2, MaxBufferSize)
+		case sdk.NET_DVR_NOSUPPORT:
+			return c.captureToFile(userID, channel, para)
+		default:
+			return nil, core.NewHKErrorFrom(c.backend, operation)
+		}
+	}
+}
+
+// captureToFile 通过 NET_DVR_CaptureJPEGPicture 抓图到临时文件后读出
+func (c *Capturer) captureToFile(userID, channel int, para *sdk.JPEGPara) ([]byte, error) {
+	f, err := os.CreateTemp("", "hiksdk-snapshot-*.jpg")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	if !c.backend.CaptureJPEGPicture(userID, channel, para, path) {
+		return nil, core.NewHKErrorFrom(c.backend, fmt.Sprintf("抓图[通道:%d]", channel))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取抓图文件失败: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,92 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/samsaralc/hiksdk/core"
+	"github.com/samsaralc/hiksdk/core/sdk"
+)
+
+// newTestCapturer 创建基于模拟后端的设备抓图
+func newTestCapturer(t *testing.T) (*Capturer, *sdk.Fake) {
+	t.Helper()
+	fake, userID := sdk.NewLoggedInFake()
+	return NewCapturerWithBackend(fake, userID), fake
+}
+
+// jpeg 构造指定大小的 JPEG 数据
+func jpeg(size int) []byte {
+	data := make([]byte, size)
+	copy(data, []byte{0xFF, 0xD8, 0xFF, 0xE0})
+	return data
+}
+
+// TestCaptureGrowBuffer 缓冲区不足时加倍重试，并记住成功的大小
+func TestCaptureGrowBuffer(t *testing.T) {
+	c, fake := newTestCapturer(t)
+	picture := jpeg(DefaultBufferSize + 100)
+	fake.SetPicture(1, picture)
+
+	data, err := c.Capture(1, QualityBest, SizeHD1080)
+	if err != nil {
+		t.Fatalf("抓图失败: %v", err)
+	}
+	if !bytes.Equal(data, picture) {
+		t.Errorf("图片数据错误: %d字节", len(data))
+	}
+	calls := fake.CallsTo("CaptureJPEGPictureNew")
+	if len(calls) != 2 || calls[0].Args[3] != DefaultBufferSize || calls[1].Args[3] != 2*DefaultBufferSize {
+		t.Fatalf("应加倍缓冲区重试一次: %v", calls)
+	}
+	if para := calls[1].Args[2].(sdk.JPEGPara); para.PicSize != SizeHD1080 || para.PicQuality != QualityBest {
+		t.Errorf("抓图参数错误: %+v", para)
+	}
+
+	fake.ResetCalls()
+	if _, err := c.Capture(1, QualityNormal, SizeAuto); err != nil {
+		t.Fatalf("再次抓图失败: %v", err)
+	}
+	if calls := fake.CallsTo("CaptureJPEGPictureNew"); len(calls) != 1 || calls[0].Args[3] != 2*DefaultBufferSize {
+		t.Errorf("应沿用上次成功的缓冲区大小: %v", calls)
+	}
+}
+
+// TestCaptureFallbackToFile 设备不支持内存抓图时改用文件方式
+func TestCaptureFallbackToFile(t *testing.T) {
+	c, fake := newTestCapturer(t)
+	fake.SetPicture(2, jpeg(64))
+	fake.FailOnce("CaptureJPEGPictureNew", sdk.NET_DVR_NOSUPPORT)
+
+	data, err := c.Capture(2, QualityBetter, SizeAuto)
+	if err != nil {
+		t.Fatalf("抓图失败: %v", err)
+	}
+	if len(data) != 64 || len(fake.CallsTo("CaptureJPEGPicture")) != 1 {
+		t.Errorf("应通过文件方式抓图: %d字节 %v", len(data), fake.Calls())
+	}
+}
+
+// TestCaptureErrors 参数错误、SDK错误和非 JPEG 数据
+func TestCaptureErrors(t *testing.T) {
+	c, fake := newTestCapturer(t)
+	if _, err := c.Capture(1, 3, SizeAuto); err == nil {
+		t.Error("无效的图片质量应返回错误")
+	}
+	if _, err := c.Capture(1, QualityBest, 0x100); err == nil {
+		t.Error("无效的图片尺寸应返回错误")
+	}
+
+	fake.FailOnce("CaptureJPEGPictureNew", sdk.NET_DVR_CHANNEL_ERROR)
+	_, err := c.Capture(1, QualityBest, SizeAuto)
+	var hkErr *core.HKError
+	if !errors.As(err, &hkErr) || hkErr.Code != sdk.NET_DVR_CHANNEL_ERROR {
+		t.Errorf("应返回通道号错误，实际: %v", err)
+	}
+
+	fake.SetPicture(1, []byte("not a jpeg"))
+	if _, err := c.Capture(1, QualityBest, SizeAuto); err == nil {
+		t.Error("非 JPEG 数据应返回错误")
+	}
+}
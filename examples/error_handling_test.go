@@ -34,6 +34,8 @@ func TestErrorHandling(t *testing.T) {
 			t.Logf("  操作: %s", hkErr.Operation)
 			t.Logf("  JSON格式: %s", hkErr.JSON())
 		}
+		// 按类别判断，无需解析错误码
+		t.Logf("  认证失败: %v, 可重试: %v", core.IsAuthFailure(err), core.IsRetryable(err))
 	} else {
 		t.Log("⚠️  未预期的成功")
 	}